@@ -0,0 +1,88 @@
+package configManager
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// Applies command line overrides of the form --key=value on top of the configuration file
+// A boolean option may be given as just --key to set it to true
+// Overrides are remembered and applied again after every reload, so they always win over the file
+// Every argument must be an override of a registered option, nothing is applied if one isn't or a value is rejected
+// Keys given to a Sub view are relative to it
+func (c *ConfigSet) ParseArgs(args []string) error {
+	parsed := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, err := c.parseArg(arg)
+		if err != nil {
+			return err
+		}
+		parsed[key] = value
+	}
+
+	c = c.base()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prev := maps.Clone(c.overrides)
+	restore := c.checkpoint()
+
+	if c.overrides == nil {
+		c.overrides = make(map[string]string, len(parsed))
+	}
+	maps.Copy(c.overrides, parsed)
+	if err := c.applyOverrides(); err != nil {
+		c.overrides = prev
+		restore()
+		return err
+	}
+
+	c.publish()
+	return nil
+}
+
+// Splits a single --key=value argument
+func (c *ConfigSet) parseArg(arg string) (key, value string, err error) {
+	kv, ok := strings.CutPrefix(arg, "--")
+	if !ok || kv == "" {
+		return "", "", fmt.Errorf("invalid argument %q, expected --key=value", arg)
+	}
+
+	key, value, hasValue := strings.Cut(kv, "=")
+	o := c.Lookup(key)
+	if o == nil {
+		return "", "", fmt.Errorf("No such option: %v", key)
+	}
+	key = o.Name
+	if err := c.base().checkLock(key); err != nil {
+		return "", "", err
+	}
+	if !hasValue {
+		if _, isBool := o.Value.Get().(bool); !isBool {
+			return "", "", fmt.Errorf("missing value for %s, expected --%s=value", key, key)
+		}
+		value = "true"
+	}
+	return key, value, nil
+}
+
+// Sets every remembered override
+func (c *ConfigSet) applyOverrides() error {
+	if len(c.overrides) == 0 {
+		return nil
+	}
+	for _, o := range c.sortOptions(c.formal) {
+		value, ok := c.overrides[o.Name]
+		if _, locked := c.locks[o.Name]; !ok || locked {
+			continue
+		}
+		if err := c.setValue(o, value); err != nil {
+			return err
+		}
+		c.markSet(o, "command line")
+	}
+	return nil
+}
+
+// Applies command line overrides of the form --key=value to the global set, e.g. ParseArgs(os.Args[1:])
+func ParseArgs(args []string) error { return globalConfig.ParseArgs(args) }
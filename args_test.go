@@ -0,0 +1,40 @@
+package configManager
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_parseArgs(t *testing.T) {
+	var c ConfigSet
+	level, _ := AddOptionToSet(&c, "log.level", "info")
+	workers, _ := AddOptionToSet(&c, "workers", int64(1), Max(10))
+	debug, _ := AddOptionToSet(&c, "debug", false)
+	c.ParseFromData([]byte(`{"log.level":"warn","workers":2}`))
+
+	if err := c.ParseArgs([]string{"--log.level=debug", "--debug"}); err != nil {
+		t.Fatal(err)
+	}
+	if *level != "debug" || *workers != 2 || !*debug {
+		t.Fatalf("Overrides not applied: [%v] [%v] [%v]", *level, *workers, *debug)
+	}
+
+	for _, args := range [][]string{{"--nope=1"}, {"workers=3"}, {"--workers"}, {"--workers=3", "--level"}} {
+		if err := c.ParseArgs(args); err == nil {
+			t.Errorf("ParseArgs(%q) accepted invalid arguments", args)
+		}
+	}
+	if err := c.ParseArgs([]string{"--workers=3", "--log.level=error", "--workers=11"}); !errors.Is(err, ErrRange) {
+		t.Fatalf("Override outside range accepted, err: %v", err)
+	}
+	if *level != "debug" || *workers != 2 {
+		t.Fatalf("Rejected overrides were applied: [%v] [%v]", *level, *workers)
+	}
+
+	if _, err := c.reloadData([]byte(`{"log.level":"warn","workers":4}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *level != "debug" || *workers != 4 {
+		t.Fatalf("Overrides lost on reload: [%v] [%v]", *level, *workers)
+	}
+}
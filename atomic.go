@@ -0,0 +1,121 @@
+package configManager
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// A handle to an option's value that is safe to read from any goroutine
+// Pointers returned by AddOption are written in place during Parse and Set, which is a data race
+// when other goroutines read them, an Atomic handle publishes every new value as a whole instead
+type Atomic[T any] struct {
+	p atomic.Pointer[T]
+}
+
+// Returns the current value
+func (a *Atomic[T]) Load() T {
+	if p := a.p.Load(); p != nil {
+		return *p
+	}
+	var z T
+	return z
+}
+
+// Replaces the current value without going through the option
+// The ConfigSet is not aware of values stored this way and will overwrite them on the next Set
+func (a *Atomic[T]) Store(v T) { a.p.Store(&v) }
+
+// Wraps the Value of an atomic option, publishing a copy of every accepted value
+type atomicValue[T any] struct {
+	inner       Value // bound to scratch, only touched by Set
+	scratch     *T
+	handle      *Atomic[T]
+	constraints []Constraint
+	c           *ConfigSet // Set the option belongs to, for types registered on it
+}
+
+func (a *atomicValue[T]) Set(s string) error {
+	prev := a.inner.String()
+	if err := a.inner.Set(s); err != nil {
+		return err
+	}
+	// constraints are checked before publishing so readers never see a rejected value
+	if err := checkConstraints(a.inner.Get(), a.constraints); err != nil {
+		a.inner.Set(prev)
+		return err
+	}
+	a.handle.Store(*a.scratch)
+	return nil
+}
+
+func (a *atomicValue[T]) Get() any {
+	if a == nil || a.inner == nil {
+		var z T
+		if v, err := zeroValueOf(a.set(), &z); err == nil {
+			return v.Get()
+		}
+		return z
+	}
+	return a.inner.Get()
+}
+
+func (a *atomicValue[T]) String() string {
+	if a == nil || a.inner == nil {
+		var z T
+		if v, err := zeroValueOf(a.set(), &z); err == nil {
+			return v.String()
+		}
+		return fmt.Sprint(z)
+	}
+	return a.inner.String()
+}
+
+// Returns the set the option belongs to, nil for a zero value receiver
+func (a *atomicValue[T]) set() *ConfigSet {
+	if a == nil {
+		return nil
+	}
+	return a.c
+}
+
+func (a *atomicValue[T]) MarshalConfig() (any, error) { return marshalValue(a.inner) }
+
+// Wraps a pointer to a zero value with the factory registered for T, for zero value receivers
+// The factories of c are looked up first, c is nil for receivers built by reflection which only see RegisterType
+func zeroValueOf[T any](c *ConfigSet, p *T) (Value, error) {
+	t := reflect.TypeOf(p)
+	if c != nil {
+		if f, r := c.factoryFor(t); r != NoRegistry {
+			return f(p), nil
+		}
+	} else if f, ok := globalFactory(t); ok {
+		return f(p), nil
+	}
+	return nil, fmt.Errorf("no ValueFactory registered for type %v", t)
+}
+
+// Add a new option to the configuration set c, returning an atomic handle to its value
+// Works with every type AddOptionToSet accepts, use it for options read by other goroutines while the set is reloaded
+func AddAtomicOptionToSet[T any](c *ConfigSet, key string, defaultValue T, constraints ...Constraint) (*Atomic[T], error) {
+	scratch := new(T)
+	*scratch = defaultValue
+	t := reflect.TypeOf(scratch)
+
+	factory, r := c.factoryFor(t)
+	if r == NoRegistry {
+		return nil, fmt.Errorf("no ValueFactory registered for type %v", t)
+	}
+
+	h := new(Atomic[T])
+	h.Store(defaultValue)
+
+	v := &atomicValue[T]{factory(scratch), scratch, h, constraints, c}
+	return h, c.Var(v, key, constraints...)
+}
+
+// Add a new configuration option, returning an atomic handle to its value
+// key is the name it has on the file and defaultValue is used when the option is not present
+func AddAtomicOption[T any](key string, defaultValue T, constraints ...Constraint) (*Atomic[T], error) {
+	return AddAtomicOptionToSet(&globalConfig, key, defaultValue, constraints...)
+}
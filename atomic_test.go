@@ -0,0 +1,88 @@
+package configManager
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func Test_atomicOption(t *testing.T) {
+	var c ConfigSet
+
+	limit, err := AddAtomicOptionToSet(&c, "limit", int64(10), Max(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := limit.Load(); v != 10 {
+		t.Fatalf("Default value mismatch, expected: [10] received: [%v]", v)
+	}
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				if v := limit.Load(); v > 1000 {
+					t.Errorf("Reader observed rejected value %v", v)
+					return
+				}
+			}
+		}
+	}()
+
+	for i := range 500 {
+		c.Set("limit", strconv.Itoa(i*3))
+	}
+	close(done)
+	wg.Wait()
+
+	if err := c.Set("limit", "5"); err != nil {
+		t.Fatal(err)
+	}
+	if v := limit.Load(); v != 5 {
+		t.Fatalf("Option value mismatch, expected: [5] received: [%v]", v)
+	}
+
+	iz, err := c.IsZeroValue("limit")
+	if err != nil || iz {
+		t.Fatalf("IsZeroValue on atomic option returned %v, %v", iz, err)
+	}
+	if err := c.Set("limit", "1001"); !errors.Is(err, ErrRange) {
+		t.Fatalf("Set accepted value above max, err: %v", err)
+	}
+}
+
+type setOnlyCount int64
+
+func Test_atomicSetRegisteredType(t *testing.T) {
+	var c ConfigSet
+	RegisterTypeInSet(&c, func(p *setOnlyCount) Value { return (*int64Value)(p) })
+
+	n, err := AddAtomicOptionToSet(&c, "count", setOnlyCount(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("count", "4"); err != nil || n.Load() != 4 {
+		t.Fatalf("Set not published: %v %v", err, n.Load())
+	}
+	if iz, err := c.IsZeroValue("count"); err != nil || iz {
+		t.Fatalf("IsZeroValue on a set registered type returned %v, %v", iz, err)
+	}
+
+	var zero atomicValue[setOnlyCount]
+	if zero.Get() != setOnlyCount(0) || zero.String() != "0" {
+		t.Fatalf("Unexpected zero receiver: %v %q", zero.Get(), zero.String())
+	}
+	if _, err := zeroValueOf(&c, new(setOnlyCount)); err != nil {
+		t.Fatalf("Set factory not found: %v", err)
+	}
+	if _, err := zeroValueOf(nil, new(setOnlyCount)); err == nil {
+		t.Fatal("Zero value built without a factory")
+	}
+}
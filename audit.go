@@ -0,0 +1,75 @@
+package configManager
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+)
+
+// Wrapped by every finding of a file audit
+var ErrInsecureFile = errors.New("insecure configuration file")
+
+// Hardening checks run on the configuration file by Parse
+// Flags files anyone can modify: world writable files, files or directories owned by
+// someone other than Owners, and world writable directories without the sticky bit
+type FileAudit struct {
+	Enforce bool        // Fail Parse on findings, otherwise they are only passed to Warn
+	Owners  []int       // User IDs allowed to own the file and its directory, defaults to the current user and root
+	Warn    func(error) // Receives findings when not enforcing
+}
+
+// Checks the file at loc, returning one error per finding
+// Ownership is only checked on platforms that have numeric user IDs
+func (a *FileAudit) Check(loc string) []error { return a.CheckFS(nil, loc) }
+
+// Checks the file at loc on fsys the same way as Check, nil means the operating system's file system
+// Ownership is only checked when fsys reports numeric user IDs
+func (a *FileAudit) CheckFS(fsys fs.FS, loc string) []error {
+	owners := a.Owners
+	if len(owners) == 0 {
+		owners = []int{0, os.Getuid()}
+	}
+
+	var findings []error
+	fi, err := statFile(fsys, loc)
+	if err != nil {
+		return nil // missing files are reported by Parse itself
+	}
+	if fi.Mode().Perm()&0o002 != 0 {
+		findings = append(findings, fmt.Errorf("%w: %s is world writable (%v)", ErrInsecureFile, loc, fi.Mode().Perm()))
+	}
+	if uid, ok := fileOwner(fi); ok && !slices.Contains(owners, uid) {
+		findings = append(findings, fmt.Errorf("%w: %s is owned by unexpected user %d", ErrInsecureFile, loc, uid))
+	}
+
+	dir := dirOf(fsys, loc)
+	di, err := statFile(fsys, dir)
+	if err != nil {
+		return findings
+	}
+	if di.Mode().Perm()&0o002 != 0 && di.Mode()&fs.ModeSticky == 0 {
+		findings = append(findings, fmt.Errorf("%w: directory %s is world writable", ErrInsecureFile, dir))
+	}
+	if uid, ok := fileOwner(di); ok && !slices.Contains(owners, uid) {
+		findings = append(findings, fmt.Errorf("%w: directory %s is owned by unexpected user %d", ErrInsecureFile, dir, uid))
+	}
+	return findings
+}
+
+// Runs the audit on loc as configured, returning an error only when enforcing
+// Findings go to warn when the audit has no Warn of its own
+func (a *FileAudit) run(fsys fs.FS, loc string, warn func(error)) error {
+	findings := a.CheckFS(fsys, loc)
+	if a.Enforce {
+		return errors.Join(findings...)
+	}
+	if a.Warn != nil {
+		warn = a.Warn
+	}
+	for _, f := range findings {
+		warn(f)
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+//go:build !unix
+
+package configManager
+
+import "io/fs"
+
+func fileOwner(fs.FileInfo) (int, bool) { return 0, false }
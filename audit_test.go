@@ -0,0 +1,40 @@
+package configManager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_fileAudit(t *testing.T) {
+	dir := t.TempDir()
+	loc := filepath.Join(dir, "config.json")
+	os.WriteFile(loc, []byte(`{"greeting":"hello"}`), 0644)
+
+	c := ConfigSet{Location: loc, Audit: &FileAudit{Enforce: true}}
+	greeting, _ := AddOptionToSet(&c, "greeting", "")
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *greeting != "hello" {
+		t.Fatal("Audited file was not parsed")
+	}
+
+	os.Chmod(loc, 0666)
+	var d ConfigSet
+	d.Location, d.Audit = loc, &FileAudit{Enforce: true}
+	if err := d.Parse(); !errors.Is(err, ErrInsecureFile) {
+		t.Fatalf("Parse accepted world writable file, err: %v", err)
+	}
+
+	var warnings []error
+	e := ConfigSet{Location: loc, Audit: &FileAudit{Owners: []int{-1}, Warn: func(err error) { warnings = append(warnings, err) }}}
+	if err := e.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	// world writable plus the unexpected owner of the file and the directory on unix
+	if len(warnings) == 0 {
+		t.Fatal("Findings were not passed to Warn")
+	}
+}
@@ -0,0 +1,73 @@
+package configManager
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// Returns the name of the nth backup of loc, 1 being the newest
+func backupName(loc string, n int) string { return fmt.Sprintf("%s.bak.%d", loc, n) }
+
+// Shifts the backups of loc by one, dropping the oldest, and backs up its current content
+// Nothing happens when loc doesn't exist yet or already holds data
+func (c *ConfigSet) rotateBackups(fsys fs.FS, loc string, data []byte) error {
+	if c.Backups <= 0 {
+		return nil
+	}
+	current, err := readFile(fsys, loc)
+	if errors.Is(err, fs.ErrNotExist) || err == nil && bytes.Equal(current, data) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for n := c.Backups - 1; n >= 1; n-- {
+		old, err := readFile(fsys, backupName(loc, n))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err == nil {
+			err = c.writeBackup(fsys, loc, n+1, old)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return c.writeBackup(fsys, loc, 1, current)
+}
+
+// Writes the nth backup of loc, on the operating system's file system it gets loc's permissions
+// so a backup never exposes more than the file itself
+func (c *ConfigSet) writeBackup(fsys fs.FS, loc string, n int, data []byte) error {
+	name := backupName(loc, n)
+	if err := writeFS(fsys, name, data, c.writeMode()); err != nil {
+		return err
+	}
+	if fsys == nil {
+		if info, err := os.Stat(loc); err == nil {
+			return os.Chmod(name, info.Mode().Perm())
+		}
+	}
+	return nil
+}
+
+// Puts the nth backup of Location back in its place, 1 being the newest, the replaced content becomes a backup in turn
+// Call Reload or Parse afterwards to apply it
+func (c *ConfigSet) RestoreBackup(n int) error {
+	c = c.base()
+	if c.Location == "" {
+		return fmt.Errorf("No file location provided")
+	}
+	data, err := readFile(c.FS, backupName(c.Location, n))
+	if err != nil {
+		return err
+	}
+	return c.writeFile(c.Location, data)
+}
+
+// Puts the nth backup of the global set's Location back in its place
+func RestoreBackup(n int) error { return globalConfig.RestoreBackup(n) }
@@ -0,0 +1,62 @@
+package configManager
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_saveBackups(t *testing.T) {
+	var mem memFS
+	var c ConfigSet
+	c.FS = &mem
+	c.Location = "app.json"
+	c.Backups = 2
+	AddOptionToSet(&c, "version", int64(0))
+
+	for _, v := range []string{"1", "2", "2", "3", "4"} {
+		c.Set("version", v)
+		if err := c.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, want := range map[string]string{"app.json": "4", "app.json.bak.1": "3", "app.json.bak.2": "2"} {
+		data, err := fs.ReadFile(&mem, name)
+		if err != nil || !strings.Contains(string(data), `"version": `+want) {
+			t.Fatalf("%s should hold version %s: %s %v", name, want, data, err)
+		}
+	}
+	if _, err := fs.Stat(&mem, "app.json.bak.3"); err == nil {
+		t.Fatal("More backups kept than asked")
+	}
+
+	if err := c.RestoreBackup(2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Reload(); err != nil || c.View().String("version") != "2" {
+		t.Fatalf("Backup not restored: %v", err)
+	}
+	if data, _ := fs.ReadFile(&mem, "app.json.bak.1"); !strings.Contains(string(data), `"version": 4`) {
+		t.Fatalf("Replaced content not backed up: %s", data)
+	}
+}
+
+func Test_backupPermissions(t *testing.T) {
+	loc := filepath.Join(t.TempDir(), "secret.json")
+	os.WriteFile(loc, []byte(`{}`), 0o600)
+
+	var c ConfigSet
+	c.Location = loc
+	c.Backups = 1
+	AddOptionToSet(&c, "token", "x")
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(loc + ".bak.1")
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("Backup permissions differ from the file: %v %v", info, err)
+	}
+}
@@ -0,0 +1,118 @@
+package configManager
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Collects option definitions and registers them together, reporting every invalid definition at once
+//
+//	r := c.Batch()
+//	host := r.String("db.host", "localhost")
+//	port := r.Int64("db.port", 5432, Min(1))
+//	err := r.Commit()
+//
+// Returned pointers hold the default right away but the options only exist once Commit succeeds
+type Registrar struct {
+	c    *ConfigSet
+	defs []batchDef
+}
+
+type batchDef struct {
+	name        string
+	value       Value
+	constraints []Constraint
+	err         error // set when the definition can't be built, e.g. an unregistered type
+}
+
+// Starts a batch of option definitions for the set c
+func (c *ConfigSet) Batch() *Registrar { return &Registrar{c: c} }
+
+// Adds an option with a Value to the batch, see ConfigSet.Var
+func (r *Registrar) Var(value Value, name string, constraints ...Constraint) {
+	r.defs = append(r.defs, batchDef{name, value, constraints, nil})
+}
+
+// Adds an option to the batch, its type inferred from the default value like AddOptionToSet
+func BatchOption[T any](r *Registrar, key string, defaultValue T, constraints ...Constraint) *T {
+	p := new(T)
+	*p = defaultValue
+
+	t := reflect.TypeOf(p)
+	factory, reg := r.c.factoryFor(t)
+	if reg == NoRegistry {
+		r.defs = append(r.defs, batchDef{key, nil, nil, fmt.Errorf("%s: no ValueFactory registered for type %v", key, t)})
+		return p
+	}
+	r.Var(factory(p), key, constraints...)
+	return p
+}
+
+// =-=-= Typed shorthands for BatchOption
+
+func (r *Registrar) Bool(key string, defaultValue bool, constraints ...Constraint) *bool {
+	return BatchOption(r, key, defaultValue, constraints...)
+}
+
+func (r *Registrar) String(key string, defaultValue string, constraints ...Constraint) *string {
+	return BatchOption(r, key, defaultValue, constraints...)
+}
+
+func (r *Registrar) Int32(key string, defaultValue int32, constraints ...Constraint) *int32 {
+	return BatchOption(r, key, defaultValue, constraints...)
+}
+
+func (r *Registrar) Int64(key string, defaultValue int64, constraints ...Constraint) *int64 {
+	return BatchOption(r, key, defaultValue, constraints...)
+}
+
+func (r *Registrar) Float32(key string, defaultValue float32, constraints ...Constraint) *float32 {
+	return BatchOption(r, key, defaultValue, constraints...)
+}
+
+func (r *Registrar) Float64(key string, defaultValue float64, constraints ...Constraint) *float64 {
+	return BatchOption(r, key, defaultValue, constraints...)
+}
+
+// Validates every definition of the batch and registers them all, or none if any is invalid
+// The error joins one error per invalid definition: duplicates, unregistered types and defaults failing constraints
+func (r *Registrar) Commit() error {
+	c, prefix := r.c, ""
+	if c.root != nil {
+		c, prefix = c.root, c.prefix
+	}
+	// held throughout so no other definition can slip in between validating and defining
+	c.registering.Lock()
+	defer c.registering.Unlock()
+
+	var errs []error
+	seen := make(map[string]bool, len(r.defs))
+	for _, d := range r.defs {
+		if d.err != nil {
+			errs = append(errs, d.err)
+			continue
+		}
+		if seen[d.name] {
+			errs = append(errs, fmt.Errorf("%s option defined twice in batch", d.name))
+			continue
+		}
+		seen[d.name] = true
+		if err := c.validate(d.value, prefix+d.name, d.constraints); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	c.reserve(len(r.defs))
+	for _, d := range r.defs {
+		c.define(d.value, prefix+d.name, d.constraints)
+	}
+	r.defs = nil
+	return nil
+}
+
+// Starts a batch of option definitions for the global set
+func Batch() *Registrar { return globalConfig.Batch() }
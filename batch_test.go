@@ -0,0 +1,46 @@
+package configManager
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_batch(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "name", "app")
+
+	r := c.Batch()
+	r.String("name", "other")
+	r.Int64("workers", 0, Min(1))
+	r.Bool("debug", false)
+	r.Bool("debug", true)
+	BatchOption(r, "size", 3i)
+
+	err := r.Commit()
+	if err == nil {
+		t.Fatal("Invalid batch committed")
+	}
+	if !errors.Is(err, ErrRange) {
+		t.Fatalf("Constraint failure not reported, err: %v", err)
+	}
+	for _, want := range []string{"name option redefined", "invalid default for workers", "debug option defined twice", "type *complex128"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("Error does not report %q:\n%v", want, err)
+		}
+	}
+	if c.Lookup("debug") != nil {
+		t.Fatal("Options of a rejected batch were registered")
+	}
+
+	r = c.Batch()
+	host := r.String("db.host", "localhost")
+	port := r.Int64("db.port", 5432, Min(1))
+	if err := r.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	c.ParseFromData([]byte(`{"db.port":6543}`))
+	if *host != "localhost" || *port != 6543 || c.Lookup("db.host") == nil {
+		t.Fatalf("Batch options not registered: [%v] [%v]", *host, *port)
+	}
+}
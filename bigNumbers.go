@@ -0,0 +1,103 @@
+package configManager
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Implemented by values that are set from numbers exactly as they were written rather than through float64
+// e.g. a Decimal keeps the scale of 1.50, which would become 1.5
+type exactValue interface{ exactNumbers() }
+
+func (*bigIntValue) exactNumbers()   {}
+func (*bigFloatValue) exactNumbers() {}
+func (*decimalValue) exactNumbers()  {}
+
+// Returns the original text of a number float64 can't hold exactly, such as an integer beyond 2^53
+// Decoded files hold numbers as float64, the text keeps every digit for the option's Set
+func exactNumber(v any, raw json.RawMessage) (string, bool) {
+	text, ok := numberText(v, raw)
+	if !ok {
+		return "", false
+	}
+	exact, _, err := big.ParseFloat(text, 10, 1024, big.ToNearestEven)
+	if err != nil || exact.Cmp(big.NewFloat(v.(float64))) == 0 {
+		return "", false
+	}
+	return text, true
+}
+
+// Returns the original text of a decoded number, ok is false when v isn't one or raw doesn't hold it
+func numberText(v any, raw json.RawMessage) (string, bool) {
+	f, ok := v.(float64)
+	if !ok || len(raw) == 0 {
+		return "", false
+	}
+	// the text may be stale, e.g. when a conditional section replaced the value
+	var decoded float64
+	if json.Unmarshal(raw, &decoded) != nil || decoded != f {
+		return "", false
+	}
+	return strings.TrimSpace(string(raw)), true
+}
+
+// =-=-= bigIntValue
+
+// Holds a big.Int, written in decimal or with a 0x, 0o or 0b prefix
+// Saved as a JSON number without going through float64, so no digits are lost
+type bigIntValue big.Int
+
+// The default was copied into p by value and shares its digits with the caller's, p gets digits of its own
+func newBigIntValue(p *big.Int) *bigIntValue {
+	*p = *new(big.Int).Set(p)
+	return (*bigIntValue)(p)
+}
+
+func (b *bigIntValue) Set(s string) error {
+	var n big.Int
+	if _, ok := n.SetString(strings.TrimSpace(s), 0); !ok {
+		return fmt.Errorf("%w: invalid integer %q", ErrParse, s)
+	}
+	(*big.Int)(b).Set(&n)
+	return nil
+}
+
+func (b *bigIntValue) Get() any { return new(big.Int).Set((*big.Int)(b)) }
+
+func (b *bigIntValue) String() string { return (*big.Int)(b).String() }
+
+func (b *bigIntValue) MarshalConfig() (any, error) { return json.Number(b.String()), nil }
+
+// =-=-= bigFloatValue
+
+// Holds a big.Float, keeping the precision of its default or enough for every digit given when it has none
+type bigFloatValue big.Float
+
+// Like newBigIntValue, p gets a mantissa of its own
+func newBigFloatValue(p *big.Float) *bigFloatValue {
+	*p = *new(big.Float).Copy(p)
+	return (*bigFloatValue)(p)
+}
+
+func (b *bigFloatValue) Set(s string) error {
+	s = strings.TrimSpace(s)
+	prec := (*big.Float)(b).Prec()
+	if prec == 0 {
+		// about 3.3 bits per decimal digit
+		prec = max(64, uint(len(s))*4)
+	}
+	f, _, err := big.ParseFloat(s, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return fmt.Errorf("%w: invalid number %q", ErrParse, s)
+	}
+	(*big.Float)(b).Set(f)
+	return nil
+}
+
+func (b *bigFloatValue) Get() any { return new(big.Float).Copy((*big.Float)(b)) }
+
+func (b *bigFloatValue) String() string { return (*big.Float)(b).Text('g', -1) }
+
+func (b *bigFloatValue) MarshalConfig() (any, error) { return json.Number(b.String()), nil }
@@ -0,0 +1,102 @@
+package configManager
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func Test_bigIntOption(t *testing.T) {
+	var c ConfigSet
+	amount, err := AddOptionToSet(&c, "wei", *big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const wei = "123456789012345678901234567890"
+	if err := c.ParseFromData([]byte(`{"wei": ` + wei + `}`)); err != nil {
+		t.Fatal(err)
+	}
+	if amount.String() != wei {
+		t.Fatalf("Digits lost, expected: %s received: %s", wei, amount)
+	}
+
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"wei": `+wei) && !strings.Contains(string(data), `"wei":`+wei) {
+		t.Fatalf("Not saved as an exact number:\n%s", data)
+	}
+
+	got := c.Lookup("wei").Value.Get().(*big.Int)
+	got.SetInt64(1)
+	if amount.String() != wei {
+		t.Fatal("Get returned the option's own value")
+	}
+
+	if err := c.Set("wei", "0x10"); err != nil || amount.Int64() != 16 {
+		t.Fatalf("Prefixed integer not accepted: %v %s", err, amount)
+	}
+	if err := c.Set("wei", "1.5"); !errors.Is(err, ErrParse) {
+		t.Fatalf("Fraction accepted: %v", err)
+	}
+}
+
+func Test_bigFloatOption(t *testing.T) {
+	var c ConfigSet
+	ratio, err := AddOptionToSet(&c, "ratio", big.Float{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const pi = "3.14159265358979323846264338327950288"
+	if err := c.ParseFromData([]byte(`{"ratio": "` + pi + `"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := ratio.Text('f', 35); got != pi[:37] {
+		t.Fatalf("Precision lost, expected: %s received: %s", pi, got)
+	}
+	if err := c.Set("ratio", "pi"); !errors.Is(err, ErrParse) {
+		t.Fatalf("Invalid number accepted: %v", err)
+	}
+}
+
+func Test_exactNumbers(t *testing.T) {
+	var c ConfigSet
+	id, _ := AddOptionToSet(&c, "id", int64(0))
+	if err := c.ParseFromData([]byte(`{"id": 9007199254740993}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *id != 9007199254740993 {
+		t.Fatalf("Integer rounded through float64: %d", *id)
+	}
+}
+
+func Test_wholeNumbers(t *testing.T) {
+	var c ConfigSet
+	n, _ := AddOptionToSet(&c, "n", 0)
+	u, _ := AddOptionToSet(&c, "u", uint64(0))
+	if err := c.ParseFromData([]byte(`{"n": 1000000, "u": 1e7}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *n != 1000000 || *u != 10000000 {
+		t.Fatalf("Whole numbers not set: %d %d", *n, *u)
+	}
+}
+
+func Test_bigIntDefaultCopied(t *testing.T) {
+	def := big.NewInt(1)
+	def.Lsh(def, 100)
+	want := def.String()
+
+	var c ConfigSet
+	if _, err := AddOptionToSet(&c, "n", *def); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("n", "7"); err != nil {
+		t.Fatal(err)
+	}
+	if def.String() != want {
+		t.Fatalf("Set changed the caller's default to %s", def)
+	}
+}
@@ -0,0 +1,33 @@
+package configManager
+
+import (
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// CBOR decoding into map[string]any instead of the default map[any]any, so nested objects
+// look the same as with every other format
+var cborDecoder, _ = cbor.DecOptions{
+	DefaultMapType: reflect.TypeOf(map[string]any(nil)),
+}.DecMode()
+
+// CBOR encoding with sorted map keys so saved blobs are reproducible
+var cborEncoder, _ = cbor.CanonicalEncOptions().EncMode()
+
+func cborUnmarshal(data []byte, v any) error { return cborDecoder.Unmarshal(data, v) }
+
+func cborMarshal(v any) ([]byte, error) { return cborEncoder.Marshal(v) }
+
+// MessagePack encoding with sorted map keys so saved blobs are reproducible
+func msgpackMarshal(v any) ([]byte, error) {
+	return msgpack.Marshal(sortedMsgpack{v})
+}
+
+type sortedMsgpack struct{ v any }
+
+func (s sortedMsgpack) EncodeMsgpack(enc *msgpack.Encoder) error {
+	enc.SetSortMapKeys(true)
+	return enc.Encode(s.v)
+}
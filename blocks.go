@@ -0,0 +1,193 @@
+package configManager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// A named block in a list of blocks, e.g. one job of {"jobs": [{"name": "api", "targets": ["a:80"]}]}
+// Fields hold decoded values, the accessors convert them and return the zero value when missing or mistyped
+type Block map[string]any
+
+// Key holding the name of a block
+const BlockNameKey = "name"
+
+// Returns the block's name
+func (b Block) Name() string { return b.String(BlockNameKey) }
+
+// Reports whether the block has the field
+func (b Block) Has(key string) bool {
+	_, ok := b[key]
+	return ok
+}
+
+// Returns a field as a string, numbers and booleans are formatted
+func (b Block) String(key string) string {
+	switch v := b[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Returns a field as an integer, strings are parsed
+func (b Block) Int(key string) int64 {
+	switch v := b[key].(type) {
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 0, 64)
+		return n
+	}
+	return 0
+}
+
+// Returns a field as a float, strings are parsed
+func (b Block) Float(key string) float64 {
+	switch v := b[key].(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	case int:
+		return float64(v)
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+	return 0
+}
+
+// Returns a field as a bool, strings are parsed
+func (b Block) Bool(key string) bool {
+	switch v := b[key].(type) {
+	case bool:
+		return v
+	case string:
+		t, _ := strconv.ParseBool(v)
+		return t
+	}
+	return false
+}
+
+// Returns a list field as strings, a single value becomes a list of one
+func (b Block) Strings(key string) []string {
+	switch v := b[key].(type) {
+	case nil:
+		return nil
+	case []any:
+		out := make([]string, len(v))
+		for i, e := range v {
+			out[i] = fmt.Sprint(e)
+		}
+		return out
+	case []string:
+		return v
+	default:
+		return []string{b.String(key)}
+	}
+}
+
+// Returns an object field as a Block, such as params in {"name": "api", "params": {"format": "json"}}
+func (b Block) Sub(key string) Block {
+	if m, ok := b[key].(map[string]any); ok {
+		return Block(m)
+	}
+	return nil
+}
+
+// An option holding a list of blocks
+// Every element must be an object, and names, when given, must be unique
+type BlockList []Block
+
+// Returns the block with the given name
+func (l BlockList) Find(name string) (Block, bool) {
+	for _, b := range l {
+		if b.Name() == name {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// =-=-= blockListValue
+
+type blockListValue BlockList
+
+func newBlockListValue(p *BlockList) *blockListValue { return (*blockListValue)(p) }
+
+func (l *blockListValue) Set(s string) error {
+	var raw []map[string]any
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return ErrParse
+	}
+
+	seen := make(map[string]bool)
+	list := make(BlockList, len(raw))
+	for i, m := range raw {
+		if m == nil {
+			return fmt.Errorf("%w: block %d is not an object", ErrParse, i)
+		}
+		b := Block(m)
+		if b.Has(BlockNameKey) {
+			if seen[b.Name()] {
+				return fmt.Errorf("%w: duplicate block name %q", ErrParse, b.Name())
+			}
+			seen[b.Name()] = true
+		}
+		list[i] = b
+	}
+
+	*l = blockListValue(list)
+	return nil
+}
+
+// Returns a copy, snapshots must not share blocks with the option
+func (l blockListValue) Get() any {
+	if l == nil {
+		return BlockList(nil)
+	}
+	out := make(BlockList, len(l))
+	for i, b := range l {
+		out[i] = cloneDecoded(map[string]any(b)).(map[string]any)
+	}
+	return out
+}
+
+func (l blockListValue) String() string {
+	if l == nil {
+		return "[]"
+	}
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+func (l blockListValue) MarshalConfig() (any, error) {
+	out := make([]map[string]any, len(l))
+	for i, b := range l {
+		out[i] = b
+	}
+	return out, nil
+}
+
+// Returns the blocks of the named BlockList option, nil if there is no such option
+func (c *ConfigSet) Blocks(name string) []Block {
+	c, name = c.owner(name)
+	o, ok := c.formal[name]
+	if !ok {
+		return nil
+	}
+	l, _ := o.Value.Get().(BlockList)
+	return l
+}
+
+// Returns the blocks of the named BlockList option, nil if there is no such option
+func Blocks(name string) []Block { return globalConfig.Blocks(name) }
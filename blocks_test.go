@@ -0,0 +1,43 @@
+package configManager
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func Test_blocks(t *testing.T) {
+	toParse := `{
+		"jobs": [
+			{"name": "api", "targets": ["a:80", "b:80"], "interval": 15, "params": {"format": "json"}},
+			{"name": "db", "targets": "c:5432", "enabled": false}
+		]
+	}`
+
+	var c ConfigSet
+	AddOptionToSet(&c, "jobs", BlockList{})
+	if err := c.ParseFromData([]byte(toParse)); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := c.Blocks("jobs")
+	if len(jobs) != 2 || jobs[0].Name() != "api" {
+		t.Fatalf("Unexpected blocks: %v", jobs)
+	}
+	if got := jobs[0].Strings("targets"); !slices.Equal(got, []string{"a:80", "b:80"}) {
+		t.Fatalf("Unexpected targets: %v", got)
+	}
+	if jobs[0].Int("interval") != 15 || jobs[0].Sub("params").String("format") != "json" {
+		t.Fatal("Typed field access mismatch")
+	}
+	if got := jobs[1].Strings("targets"); !slices.Equal(got, []string{"c:5432"}) {
+		t.Fatalf("Single target not read as list: %v", got)
+	}
+	if jobs[1].Bool("enabled") || jobs[1].Has("interval") {
+		t.Fatal("Missing fields reported as set")
+	}
+
+	if err := c.Set("jobs", `[{"name":"a"},{"name":"a"}]`); !errors.Is(err, ErrParse) {
+		t.Fatalf("Set accepted duplicate block names, err: %v", err)
+	}
+}
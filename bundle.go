@@ -0,0 +1,161 @@
+package configManager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Version of the bundle format written by Export
+const BundleVersion = 1
+
+// Returned by Import when a bundle can't be applied to this set
+var ErrIncompatible = errors.New("incompatible bundle")
+
+// Describes one option in a bundle
+type SchemaEntry struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Default     string   `json:"default"`
+	Constraints []string `json:"constraints,omitempty"`
+}
+
+// A self describing snapshot of a configuration, produced by Export and consumed by Import
+type Bundle struct {
+	Version  int               `json:"version"`
+	Created  time.Time         `json:"created"`
+	Location string            `json:"location,omitempty"`
+	Schema   []SchemaEntry     `json:"schema"`
+	Values   map[string]string `json:"values"` // set options only, in string form, encrypted fields as envelopes
+	Hash     string            `json:"hash"`   // sha256 of schema and values
+}
+
+// Computes the hash covering the bundle's schema and values
+func (b *Bundle) computeHash() string {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	enc.Encode(b.Schema)
+	for _, k := range slices.Sorted(maps.Keys(b.Values)) {
+		enc.Encode([2]string{k, b.Values[k]})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Builds a bundle of the set's schema and set options
+// Options marked with EncryptField are included encrypted, Import decrypts them with the same keys
+func (c *ConfigSet) Bundle() (*Bundle, error) {
+	c = c.base()
+	b := &Bundle{
+		Version:  BundleVersion,
+		Created:  c.now().UTC(),
+		Location: c.Location,
+		Values:   make(map[string]string),
+	}
+
+	c.VisitAll(func(o *Option) {
+		b.Schema = append(b.Schema, schemaEntry(o))
+	})
+	var err error
+	c.Visit(func(o *Option) {
+		v := o.Value.String()
+		if kr, ok := c.encrypted[o.Name]; ok && err == nil {
+			if v, err = kr.Encrypt(o.Name, v); err != nil {
+				err = fmt.Errorf("%s: %w", o.Name, err)
+			}
+		}
+		b.Values[o.Name] = v
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b.Hash = b.computeHash()
+	return b, nil
+}
+
+func schemaEntry(o *Option) SchemaEntry {
+	e := SchemaEntry{
+		Name:    o.Name,
+		Type:    fmt.Sprintf("%T", o.Value.Get()),
+		Default: o.DefValue,
+	}
+	for _, c := range o.constraints {
+		e.Constraints = append(e.Constraints, c.String())
+	}
+	return e
+}
+
+// Exports values, schema and metadata as a single JSON document
+// Useful for backing up settings or reproducing a user's environment
+func (c *ConfigSet) Export() ([]byte, error) {
+	c = c.base()
+	b, err := c.Bundle()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(b, "", "  ")
+}
+
+// Checks that a bundle can be applied to this set without applying it
+// Fails with ErrIncompatible if the version is unknown, the hash doesn't match
+// or a value targets an option that is missing or has a different type
+func (c *ConfigSet) CheckBundle(b *Bundle) error {
+	c = c.base()
+	if b.Version != BundleVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrIncompatible, b.Version)
+	}
+	if b.Hash != b.computeHash() {
+		return fmt.Errorf("%w: hash mismatch, bundle is corrupt or was edited", ErrIncompatible)
+	}
+
+	types := make(map[string]string, len(b.Schema))
+	for _, e := range b.Schema {
+		types[e.Name] = e.Type
+	}
+
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(b.Values)) {
+		o, ok := c.formal[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: unknown option %s", ErrIncompatible, name))
+			continue
+		}
+		if t := fmt.Sprintf("%T", o.Value.Get()); types[name] != t {
+			errs = append(errs, fmt.Errorf("%w: option %s has type %s, bundle has %s", ErrIncompatible, name, t, types[name]))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Imports a bundle produced by Export
+// The bundle is checked with CheckBundle first and values are applied all or nothing,
+// if any value is rejected every option is restored to its previous state
+func (c *ConfigSet) Import(data []byte) error {
+	c = c.base()
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return fmt.Errorf("%w: %v", ErrIncompatible, err)
+	}
+	if err := c.CheckBundle(&b); err != nil {
+		return err
+	}
+	return c.setAll(b.Values)
+}
+
+// Sets every named option, restoring all of them if any fails
+func (c *ConfigSet) setAll(values map[string]string) error {
+	restore := c.checkpoint()
+
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if err := c.Set(name, values[name]); err != nil {
+			restore()
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package configManager
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_bundleRoundTrip(t *testing.T) {
+	var src ConfigSet
+	AddOptionToSet(&src, "greeting", "hello")
+	AddOptionToSet(&src, "repeats", int64(1), Min(1))
+	src.Set("greeting", "howdy")
+	src.Set("repeats", "3")
+
+	data, err := src.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dst ConfigSet
+	greeting, _ := AddOptionToSet(&dst, "greeting", "hello")
+	repeats, _ := AddOptionToSet(&dst, "repeats", int64(1), Min(1))
+
+	if err := dst.Import(data); err != nil {
+		t.Fatal(err)
+	}
+	if *greeting != "howdy" || *repeats != 3 {
+		t.Fatalf("Imported values mismatch: [%v] [%v]", *greeting, *repeats)
+	}
+}
+
+func Test_bundleIncompatible(t *testing.T) {
+	var src ConfigSet
+	AddOptionToSet(&src, "repeats", int64(1))
+	src.Set("repeats", "3")
+	data, _ := src.Export()
+
+	var dst ConfigSet
+	AddOptionToSet(&dst, "repeats", "one")
+	if err := dst.Import(data); !errors.Is(err, ErrIncompatible) {
+		t.Fatalf("Import accepted bundle with mismatched type, err: %v", err)
+	}
+
+	var other ConfigSet
+	repeats, _ := AddOptionToSet(&other, "repeats", int64(1))
+	tampered := strings.Replace(string(data), `"repeats": "3"`, `"repeats": "4"`, 1)
+	if err := other.Import([]byte(tampered)); !errors.Is(err, ErrIncompatible) {
+		t.Fatalf("Import accepted tampered bundle, err: %v", err)
+	}
+	if *repeats != 1 {
+		t.Fatalf("Rejected bundle was applied, received: [%v]", *repeats)
+	}
+}
@@ -0,0 +1,114 @@
+package configManager
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// A number of bytes written with a unit, such as "512KiB", "10MB" or "1.5G"
+// K, M, G, T, P and E are decimal, 1000 based, alone or followed by B, and binary, 1024 based, followed by i or iB
+// Units are case insensitive, a number alone counts bytes
+type ByteSize int64
+
+const (
+	KB ByteSize = 1000
+	MB          = 1000 * KB
+	GB          = 1000 * MB
+	TB          = 1000 * GB
+	PB          = 1000 * TB
+	EB          = 1000 * PB
+
+	KiB ByteSize = 1 << (10 * (iota - 5))
+	MiB
+	GiB
+	TiB
+	PiB
+	EiB
+)
+
+// Units String picks from, largest first
+var byteUnits = []struct {
+	name string
+	size ByteSize
+}{
+	{"EiB", EiB}, {"EB", EB}, {"PiB", PiB}, {"PB", PB}, {"TiB", TiB}, {"TB", TB},
+	{"GiB", GiB}, {"GB", GB}, {"MiB", MiB}, {"MB", MB}, {"KiB", KiB}, {"KB", KB},
+}
+
+// Parses a size such as "512KiB" or "1.5G", fractions are rounded down to a whole byte
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	end := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if end < 0 {
+		end = len(s)
+	}
+	number, unit := s[:end], strings.ToLower(strings.TrimSpace(s[end:]))
+
+	scale := ByteSize(1)
+	if unit != "" && unit != "b" {
+		base := ByteSize(1000)
+		if rest, ok := strings.CutSuffix(strings.TrimSuffix(unit, "b"), "i"); ok {
+			unit, base = rest, 1024
+		} else {
+			unit = strings.TrimSuffix(unit, "b")
+		}
+		power := strings.Index("kmgtpe", unit)
+		if len(unit) != 1 || power < 0 {
+			return 0, fmt.Errorf("%w: unknown unit in byte size %q", ErrParse, s)
+		}
+		for range power + 1 {
+			scale *= base
+		}
+	}
+
+	if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+		if n > math.MaxInt64/int64(scale) {
+			return 0, fmt.Errorf("%w: byte size %q is too large", ErrRange, s)
+		}
+		return ByteSize(n) * scale, nil
+	}
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil || number == "" {
+		return 0, fmt.Errorf("%w: invalid byte size %q", ErrParse, s)
+	}
+	size := math.Floor(f * float64(scale))
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("%w: byte size %q is too large", ErrRange, s)
+	}
+	return ByteSize(size), nil
+}
+
+// Writes the size with the largest unit it's a whole number of, e.g. "512KiB", "10MB" or "1500B"
+func (b ByteSize) String() string {
+	if b != 0 {
+		for _, u := range byteUnits {
+			if b%u.size == 0 {
+				return strconv.FormatInt(int64(b/u.size), 10) + u.name
+			}
+		}
+	}
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
+
+// =-=-= byteSizeValue
+
+type byteSizeValue ByteSize
+
+func newByteSizeValue(p *ByteSize) *byteSizeValue { return (*byteSizeValue)(p) }
+
+func (b *byteSizeValue) Set(s string) error {
+	size, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+	*b = byteSizeValue(size)
+	return nil
+}
+
+func (b byteSizeValue) Get() any { return ByteSize(b) }
+
+func (b byteSizeValue) String() string { return ByteSize(b).String() }
+
+func (b byteSizeValue) MarshalConfig() (any, error) { return b.String(), nil }
@@ -0,0 +1,82 @@
+package configManager
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_parseByteSize(t *testing.T) {
+	for in, want := range map[string]ByteSize{
+		"512KiB": 512 * KiB,
+		"10MB":   10 * MB,
+		"1.5G":   1500 * MB,
+		"1.5Gi":  1536 * MiB,
+		"2 gib":  2 * GiB,
+		"100":    100,
+		"64b":    64,
+		"1k":     1000,
+		"8EiB":   0, // overflows, checked below
+	} {
+		got, err := ParseByteSize(in)
+		if in == "8EiB" {
+			if !errors.Is(err, ErrRange) {
+				t.Fatalf("ParseByteSize(%q) accepted an overflowing size: %v", in, err)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Fatalf("ParseByteSize(%q), expected: %d received: %d %v", in, want, got, err)
+		}
+	}
+
+	for _, in := range []string{"", "MB", "10XB", "1.2.3K", "-5K", "10 MiBs"} {
+		if _, err := ParseByteSize(in); !errors.Is(err, ErrParse) {
+			t.Fatalf("ParseByteSize(%q) accepted an invalid size: %v", in, err)
+		}
+	}
+}
+
+func Test_byteSizeString(t *testing.T) {
+	for size, want := range map[ByteSize]string{
+		0:          "0B",
+		1500:       "1500B",
+		512 * KiB:  "512KiB",
+		10 * MB:    "10MB",
+		1500 * MB:  "1500MB",
+		1536 * MiB: "1536MiB",
+		4 * TiB:    "4TiB",
+	} {
+		if got := size.String(); got != want {
+			t.Fatalf("ByteSize(%d).String(), expected: %q received: %q", int64(size), want, got)
+		}
+	}
+}
+
+func Test_byteSizeOption(t *testing.T) {
+	var c ConfigSet
+	cache, err := AddOptionToSet(&c, "cache.size", 64*MiB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFromData([]byte(`{"cache": {"size": "512KiB"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *cache != 512*KiB {
+		t.Fatalf("Unexpected size: %d", int64(*cache))
+	}
+
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"512KiB"`) {
+		t.Fatalf("Size not saved in human form:\n%s", data)
+	}
+
+	var bad ConfigSet
+	AddOptionToSet(&bad, "cache.size", 64*MiB)
+	if err := bad.ParseFromData([]byte(`{"cache": {"size": "lots"}}`)); !errors.Is(err, ErrParse) {
+		t.Fatalf("Invalid size accepted: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package configManager
+
+import "encoding/json"
+
+// Marshals every option with m instead of the set's format, e.g. json.Marshal to answer an API while the file stays TOML
+// Dotted names are nested into objects the same way as for JSON, the set itself is left untouched
+func (c *ConfigSet) SaveWith(m func(v any) ([]byte, error)) ([]byte, error) {
+	c = c.base()
+	return c.marshalWith(m, true, nil)
+}
+
+// Parses data with u instead of the set's format, like ParseFromData
+// Nested objects are flattened into dotted names, the set's Unmarshaller is left untouched
+func (c *ConfigSet) ParseWith(u func(data []byte, v any) error, data []byte) error {
+	c = c.base()
+	c.problems = nil
+	return c.handle(c.gated(func() error {
+		return c.parseDecoded("data", false, func() (map[string]any, map[string]json.RawMessage, error) {
+			d := make(map[string]any)
+			if err := u(data, &d); err != nil {
+				return nil, nil, locate(data, err)
+			}
+			d, err := c.applySections(c.flatten(d))
+			return d, nil, err
+		})
+	}))
+}
+
+// Marshals every option of the global set with m instead of its format
+func SaveWith(m func(v any) ([]byte, error)) ([]byte, error) { return globalConfig.SaveWith(m) }
+
+// Parses data into the global set with u instead of its format
+func ParseWith(u func(data []byte, v any) error, data []byte) error {
+	return globalConfig.ParseWith(u, data)
+}
@@ -0,0 +1,53 @@
+package configManager
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func Test_saveWith(t *testing.T) {
+	var c ConfigSet
+	c.Format = TOML
+	AddOptionToSet(&c, "db.host", "localhost")
+	AddOptionToSet(&c, "debug", true)
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			data, err := c.SaveWith(json.Marshal)
+			if err != nil || string(data) != `{"db":{"host":"localhost"},"debug":true}` {
+				t.Errorf("Unexpected JSON: %s %v", data, err)
+			}
+		})
+		wg.Go(func() {
+			data, err := c.SaveTo()
+			if err != nil || !strings.Contains(string(data), "[db]") {
+				t.Errorf("Unexpected TOML: %s %v", data, err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if c.Marshaller != nil || c.Unmarshaller != nil {
+		t.Fatal("Codec fields modified")
+	}
+}
+
+func Test_parseWith(t *testing.T) {
+	var c ConfigSet
+	c.Format = TOML
+	host, _ := AddOptionToSet(&c, "db.host", "localhost")
+	port, _ := AddOptionToSet(&c, "db.port", int64(0))
+
+	if err := c.ParseWith(json.Unmarshal, []byte(`{"db": {"host": "db.internal"}, "db.port": 5432}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *host != "db.internal" || *port != 5432 {
+		t.Fatalf("Unexpected values: [%v] [%v]", *host, *port)
+	}
+	if err := c.ParseWith(json.Unmarshal, []byte(`not json`)); err == nil {
+		t.Fatal("Malformed data accepted")
+	}
+}
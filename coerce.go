@@ -0,0 +1,205 @@
+package configManager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Cross type conversions Parse may perform when a file's value doesn't have the option's type
+// Combine them with | to allow several, see SetCoercion
+type Coercion uint8
+
+const (
+	StringToNumber Coercion = 1 << iota // "80" sets a number option
+	StringToBool                        // "true" sets a bool option
+	NumberToBool                        // 1 and 0 set a bool option
+	FloatToInt                          // 2.0 sets an integer option, 2.5 is always rejected
+
+	Strict  Coercion = 0                                                         // Values must have the option's type
+	Lenient          = StringToNumber | StringToBool | NumberToBool | FloatToInt // Every conversion, the default
+)
+
+// Sets which conversions Parse performs, values needing any other conversion are rejected
+// Formats that only have text values, XML and INI, always allow conversions from strings
+// Values given to Set and ParseArgs are always text and not affected
+func (c *ConfigSet) SetCoercion(policy Coercion) {
+	c = c.base()
+	c.coercion = policy
+	c.coercionSet = true
+}
+
+// Returns the conversions Parse performs, Lenient unless changed with SetCoercion
+func (c *ConfigSet) Coercion() Coercion {
+	c = c.base()
+	if !c.coercionSet {
+		return Lenient
+	}
+	return c.coercion
+}
+
+// Checks that assigning v, as read from the file, to o needs no conversion the policy disallows
+func (c *ConfigSet) checkCoercion(o *Option, v any, raw json.RawMessage) error {
+	policy := c.Coercion()
+	if policy == Lenient {
+		return nil
+	}
+
+	var target reflect.Kind
+	if g := o.Value.Get(); g != nil {
+		target = reflect.TypeOf(g).Kind()
+	}
+	want := numberKind(target)
+	if want == 0 && target != reflect.Bool {
+		return nil
+	}
+
+	if v == nil {
+		return fmt.Errorf("%w: %v is null", ErrParse, o.Name)
+	}
+
+	var need Coercion
+	switch s := v.(type) {
+	case string:
+		if c.Format == XML || c.Format == INI {
+			return nil
+		}
+		need = StringToNumber
+		if target == reflect.Bool {
+			need = StringToBool
+		}
+	case bool:
+		// strconv.ParseBool is the only way a bool turns into anything else, numbers reject it anyway
+		return nil
+	default:
+		have := numberKind(reflect.TypeOf(s).Kind())
+		if have == 0 {
+			return nil
+		}
+		switch {
+		case target == reflect.Bool:
+			need = NumberToBool
+		case want == reflect.Int && have == reflect.Float64 && !c.integerLiteral(v, raw):
+			need = FloatToInt
+		}
+	}
+
+	if need == 0 || policy&need != 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s not allowed for %v, value is %T", ErrParse, need, o.Name, v)
+}
+
+// Groups kinds into reflect.Int and reflect.Float64, 0 for kinds that aren't numbers
+func numberKind(k reflect.Kind) reflect.Kind {
+	switch {
+	case k >= reflect.Int && k <= reflect.Uintptr:
+		return reflect.Int
+	case k == reflect.Float32 || k == reflect.Float64:
+		return reflect.Float64
+	}
+	return 0
+}
+
+// Reports whether a decoded float was written as an integer
+// JSON decodes every number as float64 so its original text decides, other formats keep integers apart
+func (c *ConfigSet) integerLiteral(v any, raw json.RawMessage) bool {
+	if c.Format != JSON && c.Format != JSONC {
+		return false
+	}
+	if raw != nil {
+		var decoded any
+		if json.Unmarshal(raw, &decoded) == nil && Equal(decoded, v) {
+			return !bytes.ContainsAny(raw, ".eE")
+		}
+	}
+	// values from conditional sections have no text of their own
+	f := reflect.ValueOf(v).Float()
+	return f == math.Trunc(f) && !math.IsInf(f, 0)
+}
+
+func (p Coercion) String() string {
+	switch p {
+	case StringToNumber:
+		return "string to number"
+	case StringToBool:
+		return "string to bool"
+	case NumberToBool:
+		return "number to bool"
+	case FloatToInt:
+		return "float to integer"
+	}
+	return fmt.Sprintf("Coercion(%d)", uint8(p))
+}
+
+// =-=-= Legacy values
+
+// Rewrites a value read from a file before it's checked and set, e.g. an old encoding the option no longer accepts
+// v is the decoded value, ok is false when the hook doesn't apply to it
+// Save writes the option's current value so migrated files are written in the modern form
+type LegacyCoercion func(o *Option, v any) (converted any, ok bool)
+
+type legacyCoercion struct {
+	format fileFormat
+	fn     LegacyCoercion
+}
+
+// Adds hooks applied to values parsed while the set's Format is format, in the order they were added
+// They run before the Coercion policy is checked, so Strict sets can still accept legacy files
+//
+//	c.AddLegacyCoercion(configManager.INI, configManager.LegacyBools)
+func (c *ConfigSet) AddLegacyCoercion(format fileFormat, fn ...LegacyCoercion) {
+	if c.root != nil {
+		c.root.AddLegacyCoercion(format, fn...)
+		return
+	}
+	for _, f := range fn {
+		c.legacy = append(c.legacy, legacyCoercion{format, f})
+	}
+}
+
+// Adds hooks applied to values the global set parses while its Format is format
+func AddLegacyCoercion(format fileFormat, fn ...LegacyCoercion) {
+	globalConfig.AddLegacyCoercion(format, fn...)
+}
+
+// Runs the legacy hooks of the set's format on v, raw is dropped when v changes as it no longer describes it
+func (c *ConfigSet) applyLegacy(o *Option, v any, raw json.RawMessage) (any, json.RawMessage) {
+	for _, l := range c.legacy {
+		if l.format != c.Format {
+			continue
+		}
+		if converted, ok := l.fn(o, v); ok {
+			v, raw = converted, nil
+		}
+	}
+	return v, raw
+}
+
+// Reads 0 and 1, "0" and "1", yes and no, on and off as booleans for bool options
+func LegacyBools(o *Option, v any) (any, bool) {
+	if _, ok := o.Value.Get().(bool); !ok {
+		return nil, false
+	}
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.Kind() == reflect.String:
+		switch strings.ToLower(strings.TrimSpace(rv.String())) {
+		case "1", "yes", "on":
+			return true, true
+		case "0", "no", "off":
+			return false, true
+		}
+	case numberKind(rv.Kind()) != 0:
+		switch f, _ := toFloat(rv); f {
+		case 1:
+			return true, true
+		case 0:
+			return false, true
+		}
+	}
+	return nil, false
+}
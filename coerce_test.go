@@ -0,0 +1,119 @@
+package configManager
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_coercion(t *testing.T) {
+	data := map[string]string{
+		"string to int":    `{"n": "80"}`,
+		"string to bool":   `{"b": "true"}`,
+		"number to bool":   `{"b": 1}`,
+		"integral float":   `{"n": 2.0}`,
+		"plain int":        `{"n": 80}`,
+		"int to float":     `{"f": 2}`,
+		"bool":             `{"b": true}`,
+		"number to string": `{"s": 5}`,
+	}
+	strictOK := map[string]bool{"plain int": true, "int to float": true, "bool": true, "number to string": true}
+
+	for _, policy := range []Coercion{Lenient, Strict} {
+		for name, in := range data {
+			var c ConfigSet
+			if policy != Lenient {
+				c.SetCoercion(policy)
+			}
+			AddOptionToSet(&c, "n", int64(0))
+			AddOptionToSet(&c, "f", float64(0))
+			AddOptionToSet(&c, "b", false)
+			AddOptionToSet(&c, "s", "")
+
+			err := c.ParseFromData([]byte(in))
+			if want := policy == Lenient || strictOK[name]; (err == nil) != want {
+				t.Fatalf("%s with policy %d: unexpected result %v", name, policy, err)
+			}
+			if err != nil && !errors.Is(err, ErrParse) {
+				t.Fatalf("%s: rejection doesn't wrap ErrParse: %v", name, err)
+			}
+		}
+	}
+}
+
+func Test_coercionNull(t *testing.T) {
+	for _, policy := range []Coercion{Strict, StringToNumber, FloatToInt, NumberToBool | StringToBool} {
+		var c ConfigSet
+		c.SetCoercion(policy)
+		port, _ := AddOptionToSet(&c, "port", int64(80))
+		AddOptionToSet(&c, "debug", false)
+
+		for _, in := range []string{`{"port": null}`, `{"debug": null}`} {
+			if err := c.ParseFromData([]byte(in)); !errors.Is(err, ErrParse) {
+				t.Fatalf("%s with policy %d: null not rejected: %v", in, policy, err)
+			}
+		}
+		if *port != 80 {
+			t.Fatalf("Null changed the option: [%v]", *port)
+		}
+	}
+}
+
+func Test_coercionPartial(t *testing.T) {
+	var c ConfigSet
+	c.SetCoercion(StringToNumber)
+	port, _ := AddOptionToSet(&c, "port", int64(0))
+	debug, _ := AddOptionToSet(&c, "debug", false)
+
+	if err := c.ParseFromData([]byte(`{"port": "8080", "debug": "yes"}`)); err == nil {
+		t.Fatal("Disallowed string to bool accepted")
+	}
+	if err := c.ParseFromData([]byte(`{"port": "8080", "debug": false}`)); err != nil || *port != 8080 || *debug {
+		t.Fatalf("Allowed coercion rejected: %v", err)
+	}
+	if err := c.Set("debug", "1"); err != nil {
+		t.Fatalf("Set affected by coercion policy: %v", err)
+	}
+	if c.Coercion() != StringToNumber {
+		t.Fatal("Coercion not stored")
+	}
+}
+
+func Test_coercionTextFormats(t *testing.T) {
+	var c ConfigSet
+	c.Format = INI
+	c.SetCoercion(Strict)
+	port, _ := AddOptionToSet(&c, "server.port", int64(0))
+
+	if err := c.ParseFromData([]byte("[server]\nport = 80\n")); err != nil || *port != 80 {
+		t.Fatalf("INI value rejected under Strict: %v", err)
+	}
+}
+
+func Test_legacyCoercion(t *testing.T) {
+	var c ConfigSet
+	c.Format = TOML
+	c.SetCoercion(Strict)
+	c.AddLegacyCoercion(TOML, LegacyBools)
+	debug, _ := AddOptionToSet(&c, "debug", false)
+	verbose, _ := AddOptionToSet(&c, "verbose", true)
+	level, _ := AddOptionToSet(&c, "level", int64(0))
+
+	if err := c.ParseFromData([]byte("debug = 1\nverbose = \"off\"\nlevel = 1\n")); err != nil {
+		t.Fatal(err)
+	}
+	if !*debug || *verbose || *level != 1 {
+		t.Fatalf("Unexpected values: [%v] [%v] [%v]", *debug, *verbose, *level)
+	}
+	data, _ := c.SaveTo()
+	if string(data) != "debug = true\nlevel = 1\nverbose = false\n" {
+		t.Fatalf("Legacy values saved:\n%s", data)
+	}
+
+	var d ConfigSet
+	d.SetCoercion(Strict)
+	d.AddLegacyCoercion(TOML, LegacyBools)
+	AddOptionToSet(&d, "debug", false)
+	if err := d.ParseFromData([]byte(`{"debug": 1}`)); !errors.Is(err, ErrParse) {
+		t.Fatalf("Hook of another format applied: %v", err)
+	}
+}
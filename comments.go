@@ -0,0 +1,344 @@
+package configManager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Comments of a configuration file, carried over by Save so hand-written notes survive a round trip
+// Keys are full option names, or section headers such as "[server]"
+type fileComments struct {
+	head   []string            // Lines before the first setting
+	before map[string][]string // Lines directly above each key
+	inline map[string]string   // Comment following each key on its line
+	tail   []string            // Lines after the last setting
+}
+
+// Finds keys and comments line by line in one format's syntax
+type commentScanner interface {
+	// Reports whether the trimmed line is, or continues, a whole line comment
+	comment(line string) bool
+	// Returns the key set on the line, ok is false for lines setting nothing
+	key(line string) (key string, ok bool)
+	// Splits a comment off the end of the line
+	inline(line string) (code, comment string)
+	// Starts the comments Save writes
+	prefix() string
+}
+
+// Starts the body of comments written by Annotate, so the next Save replaces them instead of keeping them
+const provenanceMarker = "provenance: "
+
+// Returns the scanner of the set's format, nil for formats without comments or not written line by line
+func (c *ConfigSet) commentScanner() commentScanner {
+	switch c.Format {
+	case JSONC:
+		return &jsoncScanner{}
+	case TOML:
+		return &sectionScanner{comments: "#", inlineComments: true}
+	case INI:
+		return &sectionScanner{comments: ";#"}
+	}
+	return nil
+}
+
+// Prepares data to replace the file at loc, keeping its comments and adding provenance notes when Annotate is set
+func (c *ConfigSet) commented(loc string, data []byte) []byte {
+	data = c.keepComments(loc, data)
+	if c.Annotate {
+		data = c.annotate(data)
+	}
+	return data
+}
+
+// Writes a provenance note above every option of data
+func (c *ConfigSet) annotate(data []byte) []byte {
+	s := c.commentScanner()
+	if s == nil {
+		return data
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var b bytes.Buffer
+	for line := range strings.Lines(string(data)) {
+		code, _ := s.inline(strings.TrimRight(line, "\r\n"))
+		if name, ok := s.key(strings.TrimSpace(code)); ok && c.formal[name] != nil {
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			fmt.Fprintf(&b, "%s%s %s%s\n", indent, s.prefix(), provenanceMarker, c.provenance(name))
+		}
+		b.WriteString(line)
+	}
+	return b.Bytes()
+}
+
+// Describes where the named option's value comes from, e.g. "set by Set at 2025-01-02T15:04:05Z"
+func (c *ConfigSet) provenance(name string) string {
+	if _, set := c.actual[name]; !set {
+		return "default"
+	}
+	note := "set"
+	if origin := c.origins[name]; origin != "" {
+		note += " by " + origin
+	}
+	if at, ok := c.setAt[name]; ok {
+		note += " at " + at.UTC().Format(time.RFC3339)
+	}
+	return note
+}
+
+// Carries the comments of the file at loc over to data, about to replace it
+// Comments stay above or after the key they were written next to, those of keys data no longer has are dropped
+// Only JSONC, TOML and INI have comments, data is returned unchanged for other formats
+func (c *ConfigSet) keepComments(loc string, data []byte) []byte {
+	if c.commentScanner() == nil {
+		return data
+	}
+	current, err := readFile(c.FS, loc)
+	if err != nil {
+		return data
+	}
+	fc := scanComments(c.commentScanner(), current)
+	if fc.head == nil && fc.tail == nil && len(fc.before) == 0 && len(fc.inline) == 0 {
+		return data
+	}
+	return fc.apply(c.commentScanner(), data)
+}
+
+func scanComments(s commentScanner, data []byte) fileComments {
+	fc := fileComments{before: make(map[string][]string), inline: make(map[string]string)}
+	var pending []string
+	seen := false
+	for line := range strings.Lines(string(data)) {
+		line = strings.TrimRight(line, "\r\n")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			if pending != nil {
+				pending = append(pending, "")
+			}
+			continue
+		case s.comment(trimmed):
+			if !strings.HasPrefix(trimmed, s.prefix()+" "+provenanceMarker) {
+				pending = append(pending, trimmed)
+			}
+			continue
+		}
+
+		if !seen {
+			fc.head, pending, seen = pending, nil, true
+		}
+		code, comment := s.inline(line)
+		key, ok := s.key(strings.TrimSpace(code))
+		if !ok {
+			pending = nil
+			continue
+		}
+		if pending != nil {
+			fc.before[key] = pending
+			pending = nil
+		}
+		if comment != "" {
+			fc.inline[key] = comment
+		}
+	}
+	if !seen {
+		fc.head = pending
+	} else {
+		fc.tail = pending
+	}
+	return fc
+}
+
+// Writes the comments into data, a freshly marshalled file
+func (fc fileComments) apply(s commentScanner, data []byte) []byte {
+	var b bytes.Buffer
+	writeLines := func(lines []string, indent string) {
+		for _, l := range lines {
+			switch {
+			case l == "":
+			case strings.HasPrefix(l, "*"):
+				// continuation of a /* */ block, aligned under its opening
+				b.WriteString(indent + " " + l)
+			default:
+				b.WriteString(indent + l)
+			}
+			b.WriteByte('\n')
+		}
+	}
+
+	writeLines(fc.head, "")
+	for line := range strings.Lines(string(data)) {
+		line = strings.TrimRight(line, "\r\n")
+		code, comment := s.inline(line)
+		key, ok := s.key(strings.TrimSpace(code))
+		if !ok {
+			b.WriteString(line + "\n")
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		writeLines(fc.before[key], indent)
+		b.WriteString(line)
+		if c, ok := fc.inline[key]; ok && comment == "" {
+			b.WriteString(" " + c)
+		}
+		b.WriteByte('\n')
+	}
+	writeLines(fc.tail, "")
+	return b.Bytes()
+}
+
+// =-=-= TOML and INI
+
+// Keys of formats where [section] headers prefix the keys below them
+type sectionScanner struct {
+	comments       string // Characters starting a comment line
+	inlineComments bool   // Whether comments may follow a value
+	section        string
+}
+
+func (s *sectionScanner) comment(line string) bool {
+	return strings.ContainsRune(s.comments, rune(line[0]))
+}
+
+func (s *sectionScanner) prefix() string { return s.comments[:1] }
+
+func (s *sectionScanner) key(line string) (string, bool) {
+	if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+		s.section = strings.Trim(line, "[] \t")
+		return line, true
+	}
+	key, _, found := strings.Cut(line, "=")
+	if !found {
+		return "", false
+	}
+	key, _ = StripQuotes("", strings.TrimSpace(key))
+	if s.section != "" {
+		key = s.section + NamespaceSeparator + key
+	}
+	return key, true
+}
+
+func (s *sectionScanner) inline(line string) (string, string) {
+	if !s.inlineComments {
+		return line, ""
+	}
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case strings.ContainsRune(s.comments, r):
+			return strings.TrimRight(line[:i], " \t"), line[i:]
+		}
+	}
+	return line, ""
+}
+
+// =-=-= JSONC
+
+// Keys of JSON written one member per line, nested objects prefix the keys inside them
+type jsoncScanner struct {
+	path    []string // Names of the open objects and arrays, "[" for arrays
+	inBlock bool     // Inside a /* */ comment spanning lines
+}
+
+func (s *jsoncScanner) comment(line string) bool {
+	if s.inBlock {
+		s.inBlock = !strings.Contains(line, "*/")
+		return true
+	}
+	if strings.HasPrefix(line, "//") {
+		return true
+	}
+	if strings.HasPrefix(line, "/*") {
+		s.inBlock = !strings.Contains(line[2:], "*/")
+		return true
+	}
+	return false
+}
+
+func (s *jsoncScanner) prefix() string { return "//" }
+
+func (s *jsoncScanner) key(line string) (string, bool) {
+	line = strings.TrimSuffix(line, ",")
+	switch {
+	case line == "":
+		return "", false
+	case line[0] == '}' || line[0] == ']':
+		if len(s.path) > 0 {
+			s.path = s.path[:len(s.path)-1]
+		}
+		return "", false
+	case line == "{":
+		// the root, or an object in an array
+		s.path = append(s.path, "")
+		return "", false
+	case line == "[":
+		s.path = append(s.path, "[")
+		return "", false
+	case line[0] != '"':
+		return "", false
+	}
+
+	end := jsonStringEnd(line)
+	var name string
+	if end < 0 || json.Unmarshal([]byte(line[:end]), &name) != nil {
+		return "", false
+	}
+	value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line[end:]), ":"))
+
+	inArray := false
+	var names []string
+	for _, p := range s.path {
+		inArray = inArray || p == "["
+		if p != "" && p != "[" {
+			names = append(names, p)
+		}
+	}
+	switch value {
+	case "{":
+		s.path = append(s.path, name)
+	case "[":
+		s.path = append(s.path, "[")
+	}
+	if inArray {
+		return "", false
+	}
+	return strings.Join(append(names, name), NamespaceSeparator), true
+}
+
+func (s *jsoncScanner) inline(line string) (string, string) {
+	inString := false
+	for i := 0; i < len(line); i++ {
+		switch ch := line[i]; {
+		case inString && ch == '\\':
+			i++
+		case ch == '"':
+			inString = !inString
+		case !inString && ch == '/' && i+1 < len(line) && (line[i+1] == '/' || line[i+1] == '*'):
+			return strings.TrimRight(line[:i], " \t"), line[i:]
+		}
+	}
+	return line, ""
+}
+
+// Returns the offset just past the JSON string line starts with, -1 if it isn't terminated
+func jsonStringEnd(line string) int {
+	for i := 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
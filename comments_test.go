@@ -0,0 +1,114 @@
+package configManager
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func Test_keepComments(t *testing.T) {
+	for _, tc := range []struct {
+		format fileFormat
+		file   string
+		want   []string
+	}{
+		{TOML, `# Service configuration
+
+name = "api" # shown in logs
+
+[server]
+  # port to listen on
+  port = 80
+# end of file
+`, []string{"# Service configuration\n", `name = "web" # shown in logs`, "  # port to listen on\n  port = 8080", "# end of file\n"}},
+		{INI, `; Service configuration
+name = api
+
+[server]
+# port to listen on
+port = 80
+`, []string{"; Service configuration\n", "# port to listen on\nport = 8080"}},
+		{JSONC, `// Service configuration
+{
+  "name": "api", // shown in logs
+  "server": {
+    /* port to
+     * listen on */
+    "port": 80
+  }
+}
+`, []string{"// Service configuration\n{", `"name": "web", // shown in logs`, "    /* port to\n     * listen on */\n    \"port\": 8080"}},
+	} {
+		var mem memFS
+		mem.WriteFile("app.conf", []byte(tc.file), 0644)
+		c := ConfigSet{FS: &mem, Location: "app.conf", Format: tc.format}
+		AddOptionToSet(&c, "name", "")
+		AddOptionToSet(&c, "server.port", int64(0))
+		if err := c.Parse(); err != nil {
+			t.Fatal(err)
+		}
+
+		c.Set("name", "web")
+		c.Set("server.port", "8080")
+		if err := c.Save(); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := fs.ReadFile(&mem, "app.conf")
+		for _, want := range tc.want {
+			if !strings.Contains(string(data), want) {
+				t.Fatalf("%v: missing %q from saved file:\n%s", tc.format, want, data)
+			}
+		}
+
+		again := ConfigSet{FS: &mem, Location: "app.conf", Format: tc.format}
+		port, _ := AddOptionToSet(&again, "server.port", int64(0))
+		AddOptionToSet(&again, "name", "")
+		if err := again.Parse(); err != nil || *port != 8080 {
+			t.Fatalf("%v: saved file doesn't parse back: %v %v", tc.format, *port, err)
+		}
+
+		// comments are merged once, not added again by every Save
+		c.Save()
+		if second, _ := fs.ReadFile(&mem, "app.conf"); string(second) != string(data) {
+			t.Fatalf("%v: second Save changed the file:\n%s", tc.format, second)
+		}
+	}
+}
+
+func Test_annotateProvenance(t *testing.T) {
+	var mem memFS
+	mem.WriteFile("app.toml", []byte("# my settings\nname = \"api\"\n"), 0644)
+	c := ConfigSet{FS: &mem, Location: "app.toml", Format: TOML, Annotate: true}
+	AddOptionToSet(&c, "name", "")
+	AddOptionToSet(&c, "server.port", int64(80))
+	AddOptionToSet(&c, "server.host", "localhost")
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	c.Set("server.port", "8080")
+
+	for range 2 {
+		if err := c.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := fs.ReadFile(&mem, "app.toml")
+	for _, want := range []string{
+		"# my settings\n# provenance: set by app.toml at ",
+		"  # provenance: set by Set at ",
+		"  # provenance: default\n  host = ",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Fatalf("Missing %q from annotated file:\n%s", want, data)
+		}
+	}
+	if n := strings.Count(string(data), "provenance:"); n != 3 {
+		t.Fatalf("Expected 3 notes, got %d:\n%s", n, data)
+	}
+
+	c.Annotate = false
+	c.Save()
+	if data, _ := fs.ReadFile(&mem, "app.toml"); strings.Contains(string(data), "provenance:") || !strings.Contains(string(data), "# my settings") {
+		t.Fatalf("Notes not removed or user comments lost:\n%s", data)
+	}
+}
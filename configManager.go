@@ -1,16 +1,24 @@
 package configManager
 
 import (
+	"context"
 	"encoding/json"
-	"encoding/xml"
 	"errors"
 	"fmt"
-	"os"
-	"path"
+	"io"
+	"io/fs"
+	"log/slog"
+	"math/big"
 	"reflect"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/vmihailenco/msgpack/v5"
 )
 
 // Returned by Set when an option's value fails to parse
@@ -22,6 +30,27 @@ var ErrNoParser = errors.New("no parser provided for custom format")
 // Returned by Parse when value is not within the allowed range
 var ErrRange = errors.New("value outside allowed range")
 
+// Returned by Set, Parse and every other way of setting options when an option rejects its value
+// Err is the cause, usually wrapping ErrParse or ErrRange, errors.Is and errors.As see through it
+type OptionError struct {
+	Name string // Option that rejected the value, or the element path given to Set
+	Raw  string // Text the option was given, before transformers
+	Err  error
+}
+
+func (e *OptionError) Error() string { return fmt.Sprintf("%s: invalid value %q: %v", e.Name, e.Raw, e.Err) }
+
+func (e *OptionError) Unwrap() error { return e.Err }
+
+// Prefixes err with the option's name, unless it's an *OptionError already naming it
+func optionErr(name string, err error) error {
+	var oe *OptionError
+	if errors.As(err, &oe) && oe.Name == name {
+		return err
+	}
+	return fmt.Errorf("%s: %w", name, err)
+}
+
 // Used to dynamically store the value of an option
 // Since all options are read from a file the default value is a string
 // Methods may be called with a zero value receiver
@@ -31,16 +60,61 @@ type Value interface {
 	Get() any         // Get the value of this option
 }
 
+// Optionally implemented by a Value to control how it's written by SaveTo
+// The returned value is handed to the marshaller instead of the result of Get,
+// e.g. to always emit a string, a nested object or an array
+type ConfigMarshaler interface {
+	MarshalConfig() (any, error)
+}
+
+// Returns what SaveTo should write for v
+func marshalValue(v Value) (any, error) {
+	if m, ok := v.(ConfigMarshaler); ok {
+		return m.MarshalConfig()
+	}
+	return v.Get(), nil
+}
+
 type Option struct {
 	Name     string // name as it appears on the file
 	DefValue string // Default value as string
 	Value    Value
+
+	Usage      string // Help message shown by WriteUsage
+	Required   bool   // Marked (required) by WriteUsage
+	Deprecated string // Marked (deprecated) by WriteUsage when not empty, explains what to use instead
+
+	Experimental bool // Only accepted from files when ExperimentalSwitch is on, see MarkExperimental
+	Secret       bool // Holds a credential, files holding it are saved readable by their owner only, see MarkSecret
+
+	constraints  []Constraint
+	declared     []Constraint // All constraints including profiles, nil when the option has no profiles
+	transformers []Transformer
+	order        int // Options defined before this one, see DeclaredKeys
+}
+
+// Sets the option's value from a string, checking all constraints
+// If a constraint fails the previous value is restored
+func (o *Option) set(s string) error {
+	prev := o.Value.String()
+
+	err := o.Value.Set(s)
+	if err != nil {
+		return err
+	}
+
+	err = checkConstraints(o.Value.Get(), o.constraints)
+	if err != nil {
+		o.Value.Set(prev)
+		return err
+	}
+	return nil
 }
 
 // Check wether this option is set to it's zero value
 func (o *Option) IsZeroValue() (ok bool, err error) {
 	// Build a zero value of the flag's Value type, and see if the
-	// result of calling its String method equals the value passed in.
+	// result of calling its Get method is Equal to the current one.
 	// This works unless the Value type is itself an interface type.
 	typ := reflect.TypeOf(o.Value)
 	var z reflect.Value
@@ -49,7 +123,7 @@ func (o *Option) IsZeroValue() (ok bool, err error) {
 	} else {
 		z = reflect.Zero(typ)
 	}
-	// Catch panics calling the String method, which shouldn't prevent the
+	// Catch panics calling the Get method, which shouldn't prevent the
 	// usage message from being printed, but that we should report to the
 	// user so that they know to fix their code.
 	defer func() {
@@ -57,28 +131,153 @@ func (o *Option) IsZeroValue() (ok bool, err error) {
 			if typ.Kind() == reflect.Pointer {
 				typ = typ.Elem()
 			}
-			ok, err = false, fmt.Errorf("panic calling String method on zero %v for flag %s: %v", typ, o.Name, e)
+			ok, err = false, fmt.Errorf("panic calling Get method on zero %v for flag %s: %v", typ, o.Name, e)
 		}
 	}()
 
-	return o.Value.String() == z.Interface().(Value).String(), nil
+	return Equal(o.Value.Get(), z.Interface().(Value).Get()), nil
 }
 
 type fileFormat int
 
 const (
-	JSON fileFormat = iota
-	XML
+	JSON fileFormat = iota // Nested objects map to dotted option names, {"server": {"port": 80}} sets "server.port"
+	XML // Elements and attributes map to dotted option names, <server><port>80</port></server> sets "server.port", see XMLAttributes
 	CUSTOM
+	TOML // Tables map to dotted option names, [server] port = 80 sets "server.port"
+	INI  // Sections map to dotted option names the same way as TOML tables
+	HCL  // Blocks map to dotted option names, labels included, service "web" { port = 80 } sets "service.web.port"
+	MSGPACK
+	CBOR
+	JSONC // JSON allowing // and /* */ comments and trailing commas, Save keeps the file's comments but not trailing commas
 )
 
+// A set of options read from and saved to configuration files
+//
+// Concurrent use follows these rules:
+//   - options and types may be registered from several goroutines at once, and while Set and reloads run
+//   - Set, ParseArgs, reloads and every Save method are synchronized, once Set returns any Save or SaveTo
+//     started afterwards, from any goroutine, includes the new value
+//   - View doesn't block once its snapshot is built and snapshots may be shared freely, a snapshot taken after Set returns holds the value
+//   - Parse, ParseFromData and the other Parse methods must finish before the set is used from other goroutines
+//   - options read through the pointers returned when registering them are not synchronized, use View instead
 type ConfigSet struct {
+	mu sync.RWMutex // Held for writing while values change after Parse, for reading while they're saved
+
 	formal map[string]*Option // All options
 	actual map[string]*Option // Set options
 
+	selectors  map[string]string           // Values conditional sections are matched against
+	schedules  map[string][]scheduledValue // Time windows of scheduled options
+	clock      func() time.Time            // Overrides time.Now when set
+	instanceID string                      // Identifies this instance for rollouts
+	view       atomic.Pointer[Snapshot]    // Latest snapshot, replaced on every change
+
+	transformers []Transformer                // Run on every option before it's set
+	factories    map[reflect.Type]valueFactory // Set-local type registry, see RegisterTypeInSet
+	policies     []namedPolicy                 // Checked before parsed configurations are published, see AddPolicy
+
+	setAt map[string]time.Time // When each set option was last set
+	ttls  map[string]ttlPolicy // Expiry of options holding cached values
+
+	encrypted map[string]*Keyring // Options written encrypted by SaveTo
+
+	events eventHub // Subscribers of Events
+
+	root   *ConfigSet // Set a Sub view registers into, nil for a regular set
+	prefix string     // Prepended to option names by a Sub view
+
+	overrides map[string]string    // Command line overrides, see ParseArgs
+	equals    map[string]EqualFunc // Per option change detection, see SetEqualFunc
+	pins      map[string]Tier      // File tier each pinned option is saved to
+	locks     map[string]string    // Source that locked each locked option, see LockedKey
+	origins   map[string]string    // Where each set option got its value, see Explain
+	parsing   parsing              // Data ParseFromData is applying, see Interpolate
+
+	contributions map[string][]Contribution // Values each source provided for each option, see Explain
+
+	hasExperimental bool // Whether options were marked experimental, parsing is only checked then
+	hasSecrets      bool // Whether options were marked secret, Save restricts permissions then
+
+	held *[]func() // Publishing and events a parse holds back until gated accepts it, nil outside of gated
+
+	registering sync.Mutex // Held while options and set-local types are registered, see Var
+
+	coercion    Coercion // Conversions Parse performs, see SetCoercion
+	coercionSet bool     // Whether coercion was set, Lenient otherwise
+
+	errorHandling ErrorHandling // What Parse does when it fails, see SetErrorHandling
+
+	legacy []legacyCoercion // Hooks rewriting parsed values, see AddLegacyCoercion
+
+	optionBlock []Option // Unused Option structs handed out by Var, see Reserve
+
 	// Location of configuration file
 	Location string
-	// Format of configuration file, must be set to constants JSON, XML or CUSTOM
+	// Paths searched in order when Location is empty, e.g. ./app.json, ~/.config/app/app.json, /etc/app/app.json
+	// Parse sets Location to the first that exists, Save creates the first one when none do
+	Locations []string
+	// Writable location used by Save when Location is read only, e.g. a config baked into a container image
+	// Parse reads it before Location so settings saved at runtime take precedence
+	OverlayLocation string
+	// Sources read by Parse after OverlayLocation and Tiers, earlier sources take precedence over later ones and over Location
+	// Files and ValueSources mix freely, e.g. ArgsSource, EnvSource, a FileSource and a MapSource of defaults without a Location
+	Sources []Source
+	// Further files merged by Parse, higher tiers take precedence over lower ones and over Location
+	// Options pinned to a tier are saved to its file, see Pin
+	Tiers map[Tier]string
+
+	// File system Location and OverlayLocation are read from and written to, the operating system's when nil
+	// Paths on it follow fs.FS rules: slash separated and unrooted, e.g. "etc/app/config.json"
+	// Save treats it as a read only location unless it implements WritableFS, see configtest.MemFS for tests
+	FS fs.FS
+
+	// Permission and ownership checks run on the configuration file by Parse, nil disables them
+	Audit *FileAudit
+
+	// Number of previous versions Save keeps of every file it overwrites, named like config.json.bak.1 from newest to oldest
+	// A file is only backed up when its content changes, see RestoreBackup
+	Backups int
+
+	// Whether XML attributes set options like child elements, and which wins when both name the same option
+	// Save always writes elements
+	XMLAttributes XMLPreference
+
+	// Makes Save, SaveTo and every other way of saving write only options that were set, leaving defaults out
+	// so the file holds intentional overrides and defaults can change with upgrades
+	// Options set to a value equal to their default are still written
+	SaveChangedOnly bool
+
+	// Layout of saved JSON, JSONC and XML files, two space indentation with sorted keys when zero
+	SaveOptions SaveOptions
+
+	// Makes Save write a comment above each option telling whether it holds its default or where and when it was set
+	// Only formats with comments are annotated: JSONC, TOML and INI, notes from earlier saves are replaced
+	Annotate bool
+
+	// Permissions of files Save creates, 0644 when zero, or 0600 when options are secret or encrypted
+	// The umask applies as it does to any new file, existing files keep their permissions
+	// unless options are secret, then permissions FileMode doesn't grant are removed
+	FileMode fs.FileMode
+	// Permissions of directories Save creates, 0755 when zero, or 0700 when options are secret or encrypted
+	DirMode fs.FileMode
+
+	// Keeps Parse from failing, when it would the options keep their defaults and the set is marked Degraded
+	// Lets an application start in a minimal state instead of crash looping on a corrupt file
+	Failsafe bool
+	degraded error // Why the last Parse fell back to defaults, nil when it didn't
+	output io.Writer // Receives problem reports, see SetOutput
+
+	// Called with every error Parse, ParseContext, ParseFromData, ParseWith, ParseFS and Validate return,
+	// and with the error a Failsafe Parse recovered from, e.g. to route them to the application's logger
+	OnError func(error)
+
+	problems     []Problem     // Found by the last parse, see ParseReport
+	reportLimit  int           // Problems of one kind written per source, see SetReportLimits
+	reportWindow time.Duration // How long a written problem isn't written again
+	reported     reportLog     // When problems were last written
+
+	// Format of configuration file, must be set to constants JSON, JSONC, XML, TOML, INI, HCL, MSGPACK, CBOR or CUSTOM
 	Format fileFormat
 
 	// Unmarshaller to be used for CUSTOM fileFormat
@@ -110,6 +309,10 @@ func (c *ConfigSet) sortOptions(opts map[string]*Option) []*Option {
 // Visits all options in lexicographical order, calling fn for each
 // Visits unset options
 func (c *ConfigSet) VisitAll(fn func(*Option)) {
+	if c.root != nil {
+		c.root.VisitAll(c.underPrefix(fn))
+		return
+	}
 	for _, o := range c.sortOptions(c.formal) {
 		fn(o)
 	}
@@ -118,36 +321,99 @@ func (c *ConfigSet) VisitAll(fn func(*Option)) {
 // Visits all options in lexicographical order, calling fn for each
 // Only visits set options
 func (c *ConfigSet) Visit(fn func(*Option)) {
+	if c.root != nil {
+		c.root.Visit(c.underPrefix(fn))
+		return
+	}
 	for _, o := range c.sortOptions(c.actual) {
 		fn(o)
 	}
 }
 
 // Sets the value of the named option
-func (c *ConfigSet) Set(name, value string) error {
+// name may also point into a structured option such as a map or a list of blocks, e.g. "listeners[1].port",
+// "listeners[api].port" or "labels.env", only that element changes and the option validates the whole value
+func (c *ConfigSet) Set(name, value string) error { return c.SetBy(name, value, "") }
+
+// Sets the value of the named option like Set, recording by as who modified it, e.g. the user of an admin API
+// Explain and the notes written by Annotate report it as the option's source, Set is recorded when by is empty
+func (c *ConfigSet) SetBy(name, value, by string) error {
+	if c.root != nil {
+		return c.root.SetBy(c.prefix+name, value, by)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	given, raw := name, value
 	opt, ok := c.formal[name]
 	if !ok {
-		return fmt.Errorf("No such option: %v", name)
+		o, path, found, err := c.elementPath(name)
+		if !found {
+			return fmt.Errorf("No such option: %v", name)
+		}
+		if err == nil {
+			value, err = patchElement(o, path, value)
+		}
+		if err != nil {
+			return &OptionError{given, raw, err}
+		}
+		opt, name = o, o.Name
+	}
+	if err := c.checkLock(name); err != nil {
+		return err
 	}
 
-	err := opt.Value.Set(value)
+	err := c.setValue(opt, value)
+	var oe *OptionError
+	if errors.As(err, &oe) {
+		// report the element and the text given rather than the whole patched value
+		oe.Name, oe.Raw = given, raw
+	}
 	if err != nil {
 		return err
 	}
 
+	origin := by
+	if origin == "" {
+		origin = "Set"
+	}
+	c.markSet(opt, origin)
+	delete(c.schedules, name)
+	c.publish()
+	c.emit(Changed, by, nil, name)
+	return nil
+}
+
+// Records the option as set
+func (c *ConfigSet) markSet(o *Option, source string) {
 	if c.actual == nil {
 		c.actual = make(map[string]*Option)
 	}
+	c.actual[o.Name] = o
 
-	c.actual[name] = opt
-	return nil
+	if c.origins == nil {
+		c.origins = make(map[string]string)
+	}
+	c.origins[o.Name] = source
+
+	if c.setAt == nil {
+		c.setAt = make(map[string]time.Time)
+	}
+	c.setAt[o.Name] = c.now()
 }
 
 // Lookups [Option] struct of the named option
-func (c *ConfigSet) Lookup(name string) *Option { return c.formal[name] }
+func (c *ConfigSet) Lookup(name string) *Option {
+	if c.root != nil {
+		return c.root.Lookup(c.prefix + name)
+	}
+	return c.formal[name]
+}
 
 // Checks wether named option is set to it's zero value
 func (c *ConfigSet) IsZeroValue(name string) (bool, error) {
+	if c.root != nil {
+		return c.root.IsZeroValue(c.prefix + name)
+	}
 	opt, ok := c.actual[name]
 	if !ok {
 		return false, fmt.Errorf("No such option %v", name)
@@ -159,118 +425,420 @@ func (c *ConfigSet) IsZeroValue(name string) (bool, error) {
 // Defines an option with the specified name and default value.
 // The type is defined by the first argument, which is a Value interface
 // It's methods determine how the value is interacted with
-func (c *ConfigSet) Var(value Value, name string) error {
-	opt := &Option{name, value.String(), value}
+// Constraints are checked against the default value and every time the option is set
+// Options may be defined from several goroutines at once, parsing and reading should wait until they're all defined
+func (c *ConfigSet) Var(value Value, name string, constraints ...Constraint) error {
+	if c.root != nil {
+		return c.root.Var(value, c.prefix+name, constraints...)
+	}
+	c.registering.Lock()
+	defer c.registering.Unlock()
+	return c.define(value, name, constraints)
+}
 
-	_, exists := c.formal[name]
-	if exists {
-		return fmt.Errorf("%s option redefined", name)
+// Defines an option, the caller holds c.registering but not c.mu
+func (c *ConfigSet) define(value Value, name string, constraints []Constraint) error {
+	if err := c.validate(value, name, constraints); err != nil {
+		return err
 	}
 
+	var declared []Constraint
+	if hasProfiles(constraints) {
+		declared, constraints = constraints, activeConstraints(constraints, c.Selectors())
+	}
+
+	opt := c.newOption()
+	*opt = Option{Name: name, DefValue: intern(value.String()), Value: value, constraints: constraints, declared: declared}
+
+	// Set, reloads and View read the options under c.mu
+	c.mu.Lock()
 	if c.formal == nil {
 		c.formal = make(map[string]*Option)
 	}
-
+	opt.order = len(c.formal)
 	c.formal[name] = opt
+	c.invalidate()
+	c.mu.Unlock()
+
+	c.emit(Registered, "", nil, name)
+	return nil
+}
+
+// Checks an option could be defined, without defining it
+func (c *ConfigSet) validate(value Value, name string, constraints []Constraint) error {
+	_, exists := c.formal[name]
+	if exists {
+		return fmt.Errorf("%s option redefined", name)
+	}
+
+	if hasProfiles(constraints) {
+		constraints = activeConstraints(constraints, c.Selectors())
+	}
+	err := checkConstraints(value.Get(), constraints)
+	if err != nil {
+		return fmt.Errorf("invalid default for %s: %w", name, err)
+	}
 	return nil
 }
 
 // Parse the configuration from the given data and sets all options
 func (c *ConfigSet) ParseFromData(data []byte) error {
-	switch c.Format {
-	case JSON: c.Unmarshaller = json.Unmarshal
-	case XML: c.Unmarshaller = xml.Unmarshal
-	case CUSTOM:
-		if c.Unmarshaller == nil {
-			return ErrNoParser
-		}
-	}
+	c = c.base()
+	c.problems = nil
+	return c.handle(c.gated(func() error { return c.parseData(data, "data", false) }))
+}
 
-	var d = make(map[string]interface{})
+// Parses data read from source, every problem found is reported to the set's output
+// Options listed under LockedKey are locked when canLock is set, see LockedKey
+func (c *ConfigSet) parseData(data []byte, source string, canLock bool) error {
+	return c.parseDecoded(source, canLock, func() (map[string]any, map[string]json.RawMessage, error) { return c.decode(data) })
+}
 
-	err := c.Unmarshaller(data, &d)
+// Parses what decode returns, see parseData
+func (c *ConfigSet) parseDecoded(source string, canLock bool, decode func() (map[string]any, map[string]json.RawMessage, error)) error {
+	r := report{source: source}
+	defer c.writeReport(&r)
+
+	d, raw, err := decode()
+	if err == nil {
+		var locks []string
+		if locks, err = c.takeLocks(d); canLock {
+			c.lock(source, locks, d, &r)
+		} else if len(locks) > 0 {
+			r.warning(LockedKey, fmt.Errorf("ignored, only sources can lock options"), "")
+		}
+	}
 	if err != nil {
+		r.error("", err, "")
 		return err
 	}
+	c.parsing = parsing{d, raw, false}
+	defer func() { c.parsing = parsing{} }()
+
+	// a rejected value fails the parse, or leaves the option as it was with DefaultOnError
+	reject := func(o *Option, e error, hint string) {
+		if c.errorHandling == DefaultOnError {
+			r.warning(o.Name, e, fmt.Sprintf("keeping %q", o.Value.String()))
+			return
+		}
+		r.error(o.Name, e, hint)
+		err = e
+	}
 
+	var set []string
 	c.VisitAll(func(o *Option) {
-		if _, present := c.actual[o.Name]; present {
+		if v, ok := d[o.Name]; ok {
+			c.contribute(o.Name, source, v, raw[o.Name])
+		}
+		if by, locked := c.locks[o.Name]; locked {
+			// only the locking source sets the option, over anything set before it
+			if by != source {
+				return
+			}
+		} else if _, present := c.actual[o.Name]; present {
 			// do not set repeat options
 			return
 		}
 
 		if v, ok := d[o.Name]; ok {
-			vs := fmt.Sprint(v)
-
-			e := o.Value.Set(vs)
+			vs, ok, e := c.resolve(o, v, raw[o.Name])
 			if e != nil {
-				err = e
+				reject(o, e, "")
+				return
+			}
+			if !ok {
 				return
 			}
 
-			if c.actual == nil {
-				c.actual = make(map[string]*Option)
+			e = c.setValue(o, vs)
+			if e != nil {
+				reject(o, e, fmt.Sprintf("the default is %q", o.DefValue))
+				return
 			}
-			c.actual[o.Name] = o
+
+			c.markSet(o, source)
+			set = append(set, o.Name)
 		}
 	})
+	c.reportUnknown(&r, d)
 
+	failed := err != nil
+	c.afterGate(func() {
+		c.publish()
+		if !failed {
+			c.emit(Parsed, source, nil, set...)
+		}
+	})
 	return err
 }
 
+// Returns the unmarshaller of the set's format, Unmarshaller for CUSTOM
+// The set isn't modified so sets can be parsed concurrently with ParseWith
+func (c *ConfigSet) unmarshaller() (func(data []byte, v any) error, error) {
+	switch c.Format {
+	case JSON: return json.Unmarshal, nil
+	case JSONC: return jsoncUnmarshal, nil
+	case XML: return xmlUnmarshaller(c.XMLAttributes), nil
+	case TOML: return toml.Unmarshal, nil
+	case INI: return iniUnmarshal, nil
+	case HCL: return hclUnmarshal, nil
+	case MSGPACK: return msgpack.Unmarshal, nil
+	case CBOR: return cborUnmarshal, nil
+	}
+	if c.Unmarshaller == nil {
+		return nil, ErrNoParser
+	}
+	return c.Unmarshaller, nil
+}
+
+// Decodes data into a map of option names to parsed values
+// raw holds the original text of each top level value when the format is JSON or JSONC
+func (c *ConfigSet) decode(data []byte) (d map[string]any, raw map[string]json.RawMessage, err error) {
+	unmarshal, err := c.unmarshaller()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d = make(map[string]interface{})
+
+	err = unmarshal(data, &d)
+	if err != nil {
+		return nil, nil, locate(data, err)
+	}
+
+	// original text of each top level value, used to keep the key order of nested objects
+	switch c.Format {
+	case JSON: json.Unmarshal(data, &raw)
+	case JSONC: json.Unmarshal(stripJSONC(data), &raw)
+	}
+
+	if c.nestedFormat() {
+		d = c.flatten(d)
+		raw = c.flattenRaw(raw)
+	}
+
+	d, err = c.applySections(d)
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, raw, nil
+}
+
+// Resolves an option's parsed value into the string handed to Set, recording schedules on the way
+// ok is false when the option should be left unset
+func (c *ConfigSet) resolve(o *Option, v any, raw json.RawMessage) (vs string, ok bool, err error) {
+	sched, scheduled, err := parseSchedule(v, raw)
+	if err != nil {
+		return "", false, fmt.Errorf("%s: %w", o.Name, err)
+	}
+	if scheduled {
+		if c.schedules == nil {
+			c.schedules = make(map[string][]scheduledValue)
+		}
+		c.schedules[o.Name] = sched
+
+		if v, raw, ok = activeSchedule(sched, c.now()); !ok {
+			return "", false, nil
+		}
+	} else {
+		// a plain value replaces the schedule, a refresh mustn't bring it back
+		delete(c.schedules, o.Name)
+	}
+
+	v, raw, _, ok, err = c.resolveRollout(o.Name, v, raw)
+	if err != nil {
+		return "", false, fmt.Errorf("%s: %w", o.Name, err)
+	}
+	if !ok {
+		return "", false, nil
+	}
+
+	v, raw = c.applyLegacy(o, v, raw)
+	if err = c.checkCoercion(o, v, raw); err != nil {
+		return "", false, err
+	}
+	return valueText(o, v, raw), true, nil
+}
+
+// Returns the string handed to o's Set for a parsed value, raw is its original text when known
+func valueText(o *Option, v any, raw json.RawMessage) string {
+	if _, exact := o.Value.(exactValue); exact {
+		if text, ok := numberText(v, raw); ok {
+			return text
+		}
+	}
+	return stringify(v, raw)
+}
+
 // Parse the configuration file and sets all options
-func (c *ConfigSet) Parse() error {
-	if c.Location == "" {
-		return fmt.Errorf("No file location provided")
+// Files and sources are read in order of precedence: OverlayLocation, Tiers from highest, Sources, then Location
+// Only Location must exist, it's optional when Sources are provided
+// When Location is empty it's set to the first of Locations that exists
+// With Failsafe set a failing Parse leaves the options as they were and marks the set Degraded instead of failing
+func (c *ConfigSet) Parse() error { return c.ParseContext(context.Background()) }
+
+// Parses like Parse and panics if it fails, for programs that can't run without their configuration
+func (c *ConfigSet) MustParse() {
+	if err := c.Parse(); err != nil {
+		panic(err)
+	}
+}
+
+// Parses like Parse, ctx is handed to every ValueSource
+func (c *ConfigSet) ParseContext(ctx context.Context) error {
+	c = c.base()
+	c.problems = nil
+	return c.handle(c.failsafe(func() error { return c.parseLayers(ctx) }))
+}
+
+// Parses every layer, see Parse
+func (c *ConfigSet) parseLayers(ctx context.Context) error {
+	if err := c.searchLocations(false); err != nil {
+		return err
 	}
 
-	fdat, err := os.ReadFile(c.Location)
+	layers, err := c.layers()
 	if err != nil {
 		return err
 	}
 
-	return c.ParseFromData(fdat)
+	if c.Audit != nil && c.Location != "" {
+		r := report{source: c.Location}
+		err := c.Audit.run(c.FS, c.Location, func(err error) { r.warning("", err, "") })
+		c.writeReport(&r)
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.gated(func() error {
+		c.locks, c.contributions = nil, nil
+		for _, l := range layers {
+			var err error
+			if vs, ok := l.src.(ValueSource); ok {
+				err = c.parseDecoded(l.name, false, func() (map[string]any, map[string]json.RawMessage, error) {
+					return c.loadValues(ctx, l.name, vs)
+				})
+			} else {
+				var data []byte
+				data, err = l.load()
+				if err == nil && data != nil {
+					err = c.parseData(data, l.name, l.canLock)
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // Save the configuration file with set options to provided location
 // Set may be called to provide values to options, otherwise default values will be used
+// If Location is read only the file is written to OverlayLocation instead, when one is provided
+// Options pinned to a tier are written to that tier's file instead of Location, see Pin
+// Without a Location the options are saved to the first source implementing SavableSource
 func (c *ConfigSet) Save() error {
+	c = c.base()
+	if err := c.searchLocations(true); err != nil {
+		return err
+	}
 	if c.Location == "" {
-		return fmt.Errorf("No file location provided")
+		return c.saveToSource()
 	}
 
-	err := os.MkdirAll(path.Dir(c.Location), 0755)
+	data, err := c.marshal(func(o *Option) bool {
+		_, pinned := c.pins[o.Name]
+		return !pinned
+	})
 	if err != nil {
 		return fmt.Errorf("Could not save configuration: %v", err)
 	}
 
-	data, err := c.SaveTo()
+	saved := c.Location
+	err = c.writeFile(c.Location, c.commented(c.Location, data))
+	if errors.Is(err, ErrReadOnlyLocation) && c.OverlayLocation != "" {
+		saved = c.OverlayLocation
+		err = c.writeFile(c.OverlayLocation, c.commented(c.OverlayLocation, data))
+	}
 	if err != nil {
-		return fmt.Errorf("Could not save configuration: %v", err)
+		return err
 	}
+	c.emit(Saved, saved, nil)
 
-	err = os.WriteFile(c.Location, data, 0644)
-	return err
+	for _, t := range c.pinnedTiers() {
+		if err := c.SaveTier(t); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Write configuration file with set options and returns data
 // Set may be called to provide values to options, otherwise default values will be used
-func (c *ConfigSet) SaveTo() ([]byte, error) {
+func (c *ConfigSet) SaveTo() ([]byte, error) { return c.base().marshal(nil) }
+
+// Marshals the options include reports true for, all of them when include is nil
+func (c *ConfigSet) marshal(include func(*Option) bool) ([]byte, error) {
+	m, err := c.marshaller()
+	if err != nil {
+		return nil, err
+	}
+	return c.marshalWith(m, c.nestedFormat(), include)
+}
+
+// Returns the marshaller of the set's format, Marshaller for CUSTOM
+// The set isn't modified so sets can be saved concurrently with SaveWith
+func (c *ConfigSet) marshaller() (func(v any) ([]byte, error), error) {
 	switch c.Format {
-	case JSON: c.Marshaller = func(v any) ([]byte, error) { return json.MarshalIndent(v, "", "  ") }
-	case XML: c.Marshaller = func(v any) ([]byte, error) { return xml.MarshalIndent(v, "", "  ") }
-	case CUSTOM:
-		if c.Marshaller == nil {
-			return nil, ErrNoParser
-		}
+	case JSON, JSONC: return c.jsonMarshaller(), nil
+	case XML: return xmlMarshaller(c.SaveOptions.indent()), nil
+	case TOML: return toml.Marshal, nil
+	case INI: return iniMarshal, nil
+	case HCL: return hclMarshal, nil
+	case MSGPACK: return msgpackMarshal, nil
+	case CBOR: return cborMarshal, nil
+	}
+	if c.Marshaller == nil {
+		return nil, ErrNoParser
 	}
+	return c.Marshaller, nil
+}
+
+// Marshals the options include reports true for with m, nesting dotted names into objects when nested is set
+func (c *ConfigSet) marshalWith(m func(v any) ([]byte, error), nested bool, include func(*Option) bool) ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
 	toSave := make(map[string]any)
+	var err error
 	c.VisitAll(func(o *Option) {
-		toSave[o.Name] = o.Value.Get()
+		if include != nil && !include(o) {
+			return
+		}
+		if _, set := c.actual[o.Name]; c.SaveChangedOnly && !set {
+			return
+		}
+		v, e := marshalValue(o.Value)
+		if kr, ok := c.encrypted[o.Name]; ok && e == nil {
+			v, e = kr.Encrypt(o.Name, o.Value.String())
+		}
+		if e != nil {
+			err = fmt.Errorf("%s: %w", o.Name, e)
+			return
+		}
+		toSave[o.Name] = v
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	if nested {
+		toSave = unflatten(toSave)
+	}
 
-	return c.Marshaller(toSave)
+	return m(toSave)
 }
 
 // =-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
@@ -279,13 +847,37 @@ func (c *ConfigSet) SaveTo() ([]byte, error) {
 
 type valueFactory func(p any) Value
 
+// Guards valueFactories, types may be registered from several init functions or goroutines
+var factoriesMu sync.RWMutex
+
 var valueFactories = map[reflect.Type]valueFactory{
 	reflect.TypeOf((*bool)(nil)):    func(p any) Value { return newBoolValue(p.(*bool)) },
 	reflect.TypeOf((*string)(nil)):  func(p any) Value { return newStringValue(p.(*string)) },
+	reflect.TypeOf((*int)(nil)):     func(p any) Value { return newIntValue(p.(*int)) },
+	reflect.TypeOf((*int8)(nil)):    func(p any) Value { return newInt8Value(p.(*int8)) },
+	reflect.TypeOf((*int16)(nil)):   func(p any) Value { return newInt16Value(p.(*int16)) },
 	reflect.TypeOf((*int32)(nil)):   func(p any) Value { return newInt32Value(p.(*int32)) },
 	reflect.TypeOf((*int64)(nil)):   func(p any) Value { return newInt64Value(p.(*int64)) },
 	reflect.TypeOf((*float64)(nil)): func(p any) Value { return newFloat64Value(p.(*float64)) },
 	reflect.TypeOf((*float32)(nil)): func(p any) Value { return newFloat32Value(p.(*float32)) },
+	reflect.TypeOf((*uint)(nil)):    func(p any) Value { return newUintValue(p.(*uint)) },
+	reflect.TypeOf((*uint8)(nil)):   func(p any) Value { return newUint8Value(p.(*uint8)) },
+	reflect.TypeOf((*uint16)(nil)):  func(p any) Value { return newUint16Value(p.(*uint16)) },
+	reflect.TypeOf((*uint32)(nil)):  func(p any) Value { return newUint32Value(p.(*uint32)) },
+	reflect.TypeOf((*uint64)(nil)):  func(p any) Value { return newUint64Value(p.(*uint64)) },
+
+	reflect.TypeOf((*OrderedMap)(nil)):      func(p any) Value { return newOrderedMapValue(p.(*OrderedMap)) },
+	reflect.TypeOf((*BlockList)(nil)):       func(p any) Value { return newBlockListValue(p.(*BlockList)) },
+	reflect.TypeOf((*HostPatternList)(nil)): func(p any) Value { return newHostPatternListValue(p.(*HostPatternList)) },
+	reflect.TypeOf((*WeightedList)(nil)):    func(p any) Value { return newWeightedListValue(p.(*WeightedList)) },
+	reflect.TypeOf((*HeaderMap)(nil)):       func(p any) Value { return newHeaderMapValue(p.(*HeaderMap)) },
+	reflect.TypeOf((*ByteSize)(nil)):        func(p any) Value { return newByteSizeValue(p.(*ByteSize)) },
+	reflect.TypeOf((*slog.Level)(nil)):      func(p any) Value { return newLogLevelValue(p.(*slog.Level)) },
+	reflect.TypeOf((*fs.FileMode)(nil)):     func(p any) Value { return newFileModeValue(p.(*fs.FileMode)) },
+	reflect.TypeOf((*big.Int)(nil)):         func(p any) Value { return newBigIntValue(p.(*big.Int)) },
+	reflect.TypeOf((*big.Float)(nil)):       func(p any) Value { return newBigFloatValue(p.(*big.Float)) },
+	reflect.TypeOf((*Decimal)(nil)):         func(p any) Value { return newDecimalValue(p.(*Decimal)) },
+	reflect.TypeOf((*[]string)(nil)):        func(p any) Value { return newStringListValue(p.(*[]string), ",", true) },
 }
 
 /*
@@ -308,25 +900,95 @@ func RegisterType[T any](factory func(*T) Value) {
 	var ptr *T
 	t := reflect.TypeOf(ptr)
 
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
 	valueFactories[t] = func(p any) Value {
 		return factory(p.(*T))
 	}
 }
 
+// Registers a type of option only for the set c, taking precedence over the global registry
+// Use it to change how a type is parsed for one set, e.g. stricter URL parsing, without affecting others
+func RegisterTypeInSet[T any](c *ConfigSet, factory func(*T) Value) {
+	var ptr *T
+	t := reflect.TypeOf(ptr)
+
+	c = c.base()
+	c.registering.Lock()
+	defer c.registering.Unlock()
+	if c.factories == nil {
+		c.factories = make(map[reflect.Type]valueFactory)
+	}
+	c.factories[t] = func(p any) Value {
+		return factory(p.(*T))
+	}
+}
+
+// Which registry a type's factory comes from
+type Registry int
+
+const (
+	NoRegistry     Registry = iota // The type is not registered
+	SetRegistry                    // Registered with RegisterTypeInSet
+	GlobalRegistry                 // Registered with RegisterType or built in
+)
+
+func (r Registry) String() string {
+	switch r {
+	case SetRegistry:
+		return "set"
+	case GlobalRegistry:
+		return "global"
+	}
+	return "none"
+}
+
+// Returns the factory for the pointer type t, looking in the set's registry first and then the global one
+func (c *ConfigSet) factoryFor(t reflect.Type) (valueFactory, Registry) {
+	if c.root != nil {
+		return c.root.factoryFor(t)
+	}
+	c.registering.Lock()
+	f, ok := c.factories[t]
+	c.registering.Unlock()
+	if ok {
+		return f, SetRegistry
+	}
+	if f, ok := globalFactory(t); ok {
+		return f, GlobalRegistry
+	}
+	return nil, NoRegistry
+}
+
+// Returns the factory registered with RegisterType or built in for the pointer type t
+func globalFactory(t reflect.Type) (valueFactory, bool) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	f, ok := valueFactories[t]
+	return f, ok
+}
+
+// Reports which registry options of type T in the set c would be created from
+func ResolveType[T any](c *ConfigSet) Registry {
+	_, r := c.factoryFor(reflect.TypeFor[*T]())
+	return r
+}
+
 // whoever made methods not allowed to be generic: yo moms a hoe
 
 // Add a new option to the configuration set c
 // key is the name it has on the file and defaultValue is used when the option is not present
 // p is the pointer the value will be set to after parsing the configuration
-func AddOptionToSetVar[T any](c *ConfigSet, p *T, key string, defaultValue T) error {
+// constraints are checked against the default value and every time the option is set
+func AddOptionToSetVar[T any](c *ConfigSet, p *T, key string, defaultValue T, constraints ...Constraint) error {
 	*p = defaultValue
 	t := reflect.TypeOf(p)
 
-	factory, ok := valueFactories[t]
-	if !ok {
+	factory, r := c.factoryFor(t)
+	if r == NoRegistry {
 		return fmt.Errorf("no ValueFactory registered for type %v", t)
 	}
-	return c.Var(factory(p), key)
+	return c.Var(factory(p), key, constraints...)
 }
 
 // Add a new option to the configuration set c
@@ -334,9 +996,10 @@ func AddOptionToSetVar[T any](c *ConfigSet, p *T, key string, defaultValue T) er
 // type of option is inferred from the default value, only if a custom type is passed an error may be returned in case it lacks a Value wrapper
 // to register an option with a custom type first RegisterType must be called to ensure it has a Value interface wrapper
 // when called with a primitive type (bool, int, int32, int64, float32, float64 or string) this function should never return an error
-func AddOptionToSet[T any](c *ConfigSet, key string, defaultValue T) (*T, error) {
+// unless the default value fails one of the constraints
+func AddOptionToSet[T any](c *ConfigSet, key string, defaultValue T, constraints ...Constraint) (*T, error) {
 	p := new(T)
-	err := AddOptionToSetVar(c, p, key, defaultValue)
+	err := AddOptionToSetVar(c, p, key, defaultValue, constraints...)
 	return p, err
 }
 
@@ -348,27 +1011,37 @@ var globalConfig ConfigSet
 // Add a new configuration option
 // key is the name it has on the file and defaultValue is used when the option is not present
 // p is the pointer the value will be set to after parsing the configuration
-func AddOptionVar[T any](p *T, key string, defaultValue T) {
-	AddOptionToSetVar(&globalConfig, p, key, defaultValue)
+func AddOptionVar[T any](p *T, key string, defaultValue T, constraints ...Constraint) {
+	AddOptionToSetVar(&globalConfig, p, key, defaultValue, constraints...)
 }
 
 // Add a new configuration option
 // key is the name it has on the file and defaultValue is used when the option is not present
-func AddOption[T any](key string, defaultValue T) (*T, error) {
-	return AddOptionToSet(&globalConfig, key, defaultValue)
+// constraints such as Min, Max, OneOf, Pattern or Custom restrict which values are accepted
+func AddOption[T any](key string, defaultValue T, constraints ...Constraint) (*T, error) {
+	return AddOptionToSet(&globalConfig, key, defaultValue, constraints...)
 }
 
 // Parse the configuration from the given data and sets all options
-func ParseFromData(data []byte) { globalConfig.ParseFromData(data) }
+func ParseFromData(data []byte) error { return globalConfig.ParseFromData(data) }
 
 // Parse the configuration file and sets all options
-func Parse() { globalConfig.Parse() }
+func Parse() error { return globalConfig.Parse() }
+
+// Parses the global set and panics if it fails
+func MustParse() { globalConfig.MustParse() }
+
+// Parses the global set, ctx is handed to every ValueSource
+func ParseContext(ctx context.Context) error { return globalConfig.ParseContext(ctx) }
 
 // Sets the location for the configuration file
 func SetFileLocation(filename string) { globalConfig.Location = filename }
 
+// Sets the selectors conditional sections are evaluated against, e.g. {"role": "worker"}
+func SetSelectors(selectors map[string]string) { globalConfig.SetSelectors(selectors) }
+
 // Sets the format of the configuration file
-// Expects constants JSON, XML or CUSTOM
+// Expects constants JSON, JSONC, XML, TOML, INI, HCL, MSGPACK, CBOR or CUSTOM
 // If set to CUSTOM a unmarshaller must be provided via SetFileUnmarshaller
 func SetFileFormat(format fileFormat) { globalConfig.Format = format }
 
@@ -389,6 +1062,9 @@ func Visit(fn func(*Option)) { globalConfig.Visit(fn) }
 // Sets the value of the named option
 func Set(name, value string) error { return globalConfig.Set(name, value) }
 
+// Sets the value of the named option of the global set, recording by as who modified it
+func SetBy(name, value, by string) error { return globalConfig.SetBy(name, value, by) }
+
 // Lookups [Option] struct of the named option
 func Lookup(name string) *Option { return globalConfig.Lookup(name) }
 
@@ -475,6 +1151,60 @@ func (f float32Value) Get() any { return float32(f) }
 
 func (f float32Value) String() string { return strconv.FormatFloat(float64(f), 'g', -1, 32) }
 
+// =-=-= intValue
+type intValue int
+
+func newIntValue(p *int) *intValue { return (*intValue)(p) }
+
+func (i *intValue) Set(s string) error {
+	v, err := strconv.ParseInt(s, 0, 0)
+	if err != nil {
+		return ErrParse
+	}
+	*i = intValue(v)
+	return err
+}
+
+func (i intValue) Get() any { return int(i) }
+
+func (i intValue) String() string { return strconv.FormatInt(int64(i), 10) }
+
+// =-=-= int8Value
+type int8Value int8
+
+func newInt8Value(p *int8) *int8Value { return (*int8Value)(p) }
+
+func (i *int8Value) Set(s string) error {
+	v, err := strconv.ParseInt(s, 0, 8)
+	if err != nil {
+		return ErrParse
+	}
+	*i = int8Value(v)
+	return err
+}
+
+func (i int8Value) Get() any { return int8(i) }
+
+func (i int8Value) String() string { return strconv.FormatInt(int64(i), 10) }
+
+// =-=-= int16Value
+type int16Value int16
+
+func newInt16Value(p *int16) *int16Value { return (*int16Value)(p) }
+
+func (i *int16Value) Set(s string) error {
+	v, err := strconv.ParseInt(s, 0, 16)
+	if err != nil {
+		return ErrParse
+	}
+	*i = int16Value(v)
+	return err
+}
+
+func (i int16Value) Get() any { return int16(i) }
+
+func (i int16Value) String() string { return strconv.FormatInt(int64(i), 10) }
+
 // =-=-= int32Value
 type int32Value int32
 
@@ -512,10 +1242,103 @@ func (i int64Value) Get() any { return int64(i) }
 
 func (i int64Value) String() string { return strconv.FormatInt(int64(i), 10) }
 
+// =-=-= uintValue
+type uintValue uint
+
+func newUintValue(p *uint) *uintValue { return (*uintValue)(p) }
+
+func (i *uintValue) Set(s string) error {
+	v, err := strconv.ParseUint(s, 0, 0)
+	if err != nil {
+		return ErrParse
+	}
+	*i = uintValue(v)
+	return err
+}
+
+func (i uintValue) Get() any { return uint(i) }
+
+func (i uintValue) String() string { return strconv.FormatUint(uint64(i), 10) }
+
+// =-=-= uint8Value
+type uint8Value uint8
+
+func newUint8Value(p *uint8) *uint8Value { return (*uint8Value)(p) }
+
+func (i *uint8Value) Set(s string) error {
+	v, err := strconv.ParseUint(s, 0, 8)
+	if err != nil {
+		return ErrParse
+	}
+	*i = uint8Value(v)
+	return err
+}
+
+func (i uint8Value) Get() any { return uint8(i) }
+
+func (i uint8Value) String() string { return strconv.FormatUint(uint64(i), 10) }
+
+// =-=-= uint16Value
+type uint16Value uint16
+
+func newUint16Value(p *uint16) *uint16Value { return (*uint16Value)(p) }
+
+func (i *uint16Value) Set(s string) error {
+	v, err := strconv.ParseUint(s, 0, 16)
+	if err != nil {
+		return ErrParse
+	}
+	*i = uint16Value(v)
+	return err
+}
+
+func (i uint16Value) Get() any { return uint16(i) }
+
+func (i uint16Value) String() string { return strconv.FormatUint(uint64(i), 10) }
+
+// =-=-= uint32Value
+type uint32Value uint32
+
+func newUint32Value(p *uint32) *uint32Value { return (*uint32Value)(p) }
+
+func (i *uint32Value) Set(s string) error {
+	v, err := strconv.ParseUint(s, 0, 32)
+	if err != nil {
+		return ErrParse
+	}
+	*i = uint32Value(v)
+	return err
+}
+
+func (i uint32Value) Get() any { return uint32(i) }
+
+func (i uint32Value) String() string { return strconv.FormatUint(uint64(i), 10) }
+
+// =-=-= uint64Value
+type uint64Value uint64
+
+func newUint64Value(p *uint64) *uint64Value { return (*uint64Value)(p) }
+
+func (i *uint64Value) Set(s string) error {
+	v, err := strconv.ParseUint(s, 0, 64)
+	if err != nil {
+		return ErrParse
+	}
+	*i = uint64Value(v)
+	return err
+}
+
+func (i uint64Value) Get() any { return uint64(i) }
+
+func (i uint64Value) String() string { return strconv.FormatUint(uint64(i), 10) }
+
 // =-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
 // Range Values
 // =-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=-=
 
+// The range helpers below predate constraints and are kept for compatibility
+// AddOption(key, def, Min(a), Max(b)) and AddOption(key, def, OneOf(...)) work the same for every type
+
 // =-=-= stringRangeValue
 
 type stringRangeValue struct {
@@ -795,4 +1618,75 @@ func Float64RangeSet(c *ConfigSet, key string, defaultValue, minv, maxv float64)
 	return p, err
 }
 
+// =-=-= uint16Range
+
+type uint16RangeValue struct {
+	ptr           *uint16
+	val, min, max uint16
+}
+
+func newUint16RangeValue(p *uint16, min, max uint16) *uint16RangeValue {
+	return &uint16RangeValue{
+		ptr: p,
+		min: min,
+		max: max,
+	}
+}
+
+func (i *uint16RangeValue) Set(s string) error {
+	v, err := strconv.ParseUint(s, 0, 16)
+	if err != nil {
+		return ErrParse
+	}
+	v16 := uint16(v)
+
+	if v16 > i.max || v16 < i.min {
+		return ErrRange
+	}
+
+	i.val = v16
+	*i.ptr = v16
+	return nil
+}
+
+func (i uint16RangeValue) Get() any { return i.val }
+
+func (i uint16RangeValue) String() string { return strconv.FormatUint(uint64(i.val), 10) }
+
+func Uint16RangeVarSet(c *ConfigSet, p *uint16, key string, defaultValue, minv, maxv uint16) error {
+	v := newUint16RangeValue(p, minv, maxv)
+	err := v.Set(strconv.FormatUint(uint64(defaultValue), 10))
+	if err != nil {
+		return err
+	}
+	*p = defaultValue
+	return c.Var(v, key)
+}
+
+func Uint16RangeSet(c *ConfigSet, key string, defaultValue, minv, maxv uint16) (*uint16, error) {
+	p := new(uint16)
+	err := Uint16RangeVarSet(c, p, key, defaultValue, minv, maxv)
+	return p, err
+}
+
+// =-=-= port
+
+// Defines a new network port option on the set c, ports outside 1 to 65535 result in ErrRange
+func PortVarSet(c *ConfigSet, p *uint16, key string, defaultValue uint16) error {
+	return Uint16RangeVarSet(c, p, key, defaultValue, 1, 65535)
+}
+
+// Defines a new network port option on the set c, ports outside 1 to 65535 result in ErrRange
+func PortSet(c *ConfigSet, key string, defaultValue uint16) (*uint16, error) {
+	return Uint16RangeSet(c, key, defaultValue, 1, 65535)
+}
 
+// Defines a new network port option on the global set
+func PortVar(p *uint16, key string, defaultValue uint16) error {
+	return PortVarSet(&globalConfig, p, key, defaultValue)
+}
+
+// Defines a new network port option on the global set
+func Port(key string, defaultValue uint16) (*uint16, error) {
+	return PortSet(&globalConfig, key, defaultValue)
+}
@@ -0,0 +1,200 @@
+// Package configtest injects failures into the reads, writes and codecs of a configManager.ConfigSet
+// so applications can test how they handle a configuration that can't be loaded or saved
+//
+//	fsys := configtest.FailNthWrite(&configtest.MemFS{}, 1)
+//	c.FS = fsys
+//	err := c.Save() // fails with configtest.ErrInjected
+package configtest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"sync"
+	"testing/fstest"
+	"time"
+
+	"github.com/quollveth/configManager"
+)
+
+// Returned by every injected failure unless another error is given
+var ErrInjected = errors.New("injected failure")
+
+// =-=-= MemFS
+
+// A writable in-memory file system
+// Assign it to ConfigSet.FS to Parse, Save and reload without touching the disk
+// The zero value is an empty file system ready to use, it's safe for concurrent use
+type MemFS struct {
+	mu    sync.RWMutex
+	files fstest.MapFS
+}
+
+func (m *MemFS) Open(name string) (fs.File, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.files.Open(name)
+}
+
+// Creates or replaces the named file, directories are implicit
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.files == nil {
+		m.files = make(fstest.MapFS)
+	}
+	m.files[name] = &fstest.MapFile{Data: bytes.Clone(data), Mode: perm, ModTime: time.Now()}
+	return nil
+}
+
+// Directories are implicit in a MemFS, only the path is checked
+func (m *MemFS) MkdirAll(name string, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return nil
+}
+
+// =-=-= FaultFS
+
+// A WritableFS failing chosen reads and writes of the file system it wraps
+// Assign it to ConfigSet.FS, reads are counted per ReadFile, which Parse and reloads go through
+type FaultFS struct {
+	FS configManager.WritableFS
+
+	FailRead  int   // The read that fails, counting from 1, 0 for none
+	FailWrite int   // The write that fails, counting from 1, 0 for none
+	Err       error // Returned by the failing call, ErrInjected if nil
+
+	mu     sync.Mutex
+	reads  int
+	writes int
+}
+
+// Returns a FaultFS over fsys where the nth read fails
+func FailNthRead(fsys configManager.WritableFS, n int) *FaultFS {
+	return &FaultFS{FS: fsys, FailRead: n}
+}
+
+// Returns a FaultFS over fsys where the nth write fails
+func FailNthWrite(fsys configManager.WritableFS, n int) *FaultFS {
+	return &FaultFS{FS: fsys, FailWrite: n}
+}
+
+func (f *FaultFS) Open(name string) (fs.File, error) { return f.FS.Open(name) }
+
+func (f *FaultFS) ReadFile(name string) ([]byte, error) {
+	f.mu.Lock()
+	f.reads++
+	fail := f.reads == f.FailRead
+	f.mu.Unlock()
+
+	if fail {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: f.err()}
+	}
+	return fs.ReadFile(f.FS, name)
+}
+
+func (f *FaultFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	f.mu.Lock()
+	f.writes++
+	fail := f.writes == f.FailWrite
+	f.mu.Unlock()
+
+	if fail {
+		return &fs.PathError{Op: "write", Path: name, Err: f.err()}
+	}
+	return f.FS.WriteFile(name, data, perm)
+}
+
+func (f *FaultFS) MkdirAll(name string, perm fs.FileMode) error { return f.FS.MkdirAll(name, perm) }
+
+// Returns how many reads and writes went through the file system so far
+func (f *FaultFS) Calls() (reads, writes int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.reads, f.writes
+}
+
+func (f *FaultFS) err() error {
+	if f.Err != nil {
+		return f.Err
+	}
+	return ErrInjected
+}
+
+// =-=-= FlakyReader
+
+type flakyReader struct {
+	r    io.Reader
+	left int
+	err  error
+}
+
+// Returns a reader that reads the first n bytes of r then fails with err, ErrInjected if nil
+// Use it with ReaderSource to test a source failing halfway through
+func FlakyReader(r io.Reader, n int, err error) io.Reader {
+	if err == nil {
+		err = ErrInjected
+	}
+	return &flakyReader{r, n, err}
+}
+
+func (f *flakyReader) Read(p []byte) (int, error) {
+	if f.left <= 0 {
+		return 0, f.err
+	}
+	if len(p) > f.left {
+		p = p[:f.left]
+	}
+	n, err := f.r.Read(p)
+	f.left -= n
+	if err == io.EOF {
+		// the failure comes before the end so truncated data is never mistaken for complete
+		err = f.err
+	}
+	return n, err
+}
+
+// =-=-= Codecs
+
+// Wraps a marshaller so its nth call fails with ErrInjected
+// Use it with the CUSTOM format, e.g. c.Marshaller = FailNthMarshal(json.Marshal, 1)
+func FailNthMarshal(m func(v any) ([]byte, error), n int) func(v any) ([]byte, error) {
+	var mu sync.Mutex
+	calls := 0
+	return func(v any) ([]byte, error) {
+		mu.Lock()
+		calls++
+		fail := calls == n
+		mu.Unlock()
+
+		if fail {
+			return nil, ErrInjected
+		}
+		return m(v)
+	}
+}
+
+// Wraps an unmarshaller so its nth call fails with ErrInjected
+// Use it with the CUSTOM format, e.g. c.Unmarshaller = FailNthUnmarshal(json.Unmarshal, 2)
+func FailNthUnmarshal(u func(data []byte, v any) error, n int) func(data []byte, v any) error {
+	var mu sync.Mutex
+	calls := 0
+	return func(data []byte, v any) error {
+		mu.Lock()
+		calls++
+		fail := calls == n
+		mu.Unlock()
+
+		if fail {
+			return ErrInjected
+		}
+		return u(data, v)
+	}
+}
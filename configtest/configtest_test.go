@@ -0,0 +1,82 @@
+package configtest
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/quollveth/configManager"
+)
+
+func newSet(fsys *FaultFS) (*configManager.ConfigSet, *int64) {
+	var c configManager.ConfigSet
+	c.FS = fsys
+	c.Location = "app.json"
+	port, _ := configManager.AddOptionToSet(&c, "port", int64(80))
+	return &c, port
+}
+
+func Test_failNthWrite(t *testing.T) {
+	fsys := FailNthWrite(&MemFS{}, 2)
+	c, _ := newSet(fsys)
+
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save(); !errors.Is(err, ErrInjected) {
+		t.Fatalf("Second write didn't fail: %v", err)
+	}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, writes := fsys.Calls(); writes != 3 {
+		t.Fatalf("Unexpected write count: %d", writes)
+	}
+}
+
+func Test_failNthRead(t *testing.T) {
+	mem := &MemFS{}
+	mem.WriteFile("app.json", []byte(`{"port": 8080}`), 0o644)
+	fsys := FailNthRead(mem, 1)
+	fsys.Err = errors.New("disk on fire")
+	c, port := newSet(fsys)
+
+	if err := c.Parse(); err == nil || !strings.Contains(err.Error(), "disk on fire") {
+		t.Fatalf("Read didn't fail: %v", err)
+	}
+	if err := c.Parse(); err != nil || *port != 8080 {
+		t.Fatalf("Read after the failure failed: [%v] %v", *port, err)
+	}
+}
+
+func Test_flakyReader(t *testing.T) {
+	var c configManager.ConfigSet
+	configManager.AddOptionToSet(&c, "port", int64(80))
+	c.AddSource(configManager.ReaderSource(FlakyReader(strings.NewReader(`{"port": 8080}`), 5, nil)))
+
+	if err := c.Parse(); !errors.Is(err, ErrInjected) {
+		t.Fatalf("Truncated source didn't fail: %v", err)
+	}
+}
+
+func Test_failNthCodec(t *testing.T) {
+	var c configManager.ConfigSet
+	c.Format = configManager.CUSTOM
+	c.Marshaller = FailNthMarshal(json.Marshal, 1)
+	c.Unmarshaller = FailNthUnmarshal(json.Unmarshal, 2)
+	configManager.AddOptionToSet(&c, "port", int64(80))
+
+	if _, err := c.SaveTo(); !errors.Is(err, ErrInjected) {
+		t.Fatalf("Marshal didn't fail: %v", err)
+	}
+	if _, err := c.SaveTo(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFromData([]byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFromData([]byte(`{}`)); !errors.Is(err, ErrInjected) {
+		t.Fatalf("Unmarshal didn't fail: %v", err)
+	}
+}
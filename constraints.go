@@ -0,0 +1,194 @@
+package configManager
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// A rule an option's value must satisfy, checked every time the option is set
+// Constraints are attached at registration, e.g. AddOption("workers", 4, Min(1), Max(64))
+// Check receives the value as returned by the option's Get method
+type Constraint interface {
+	Check(v any) error // Returns nil if v is acceptable
+	String() string    // Describes the constraint, used in messages and usage text
+}
+
+// =-=-= ordering helpers
+
+// Compares a and b by kind so an int32 option can be checked against an untyped int bound
+// ok is false when the values can't be ordered against each other
+func compareValues(a, b any) (r int, ok bool) {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	if !av.IsValid() || !bv.IsValid() {
+		return 0, false
+	}
+
+	switch {
+	case av.CanInt() && bv.CanInt():
+		return cmp.Compare(av.Int(), bv.Int()), true
+	case av.CanUint() && bv.CanUint():
+		return cmp.Compare(av.Uint(), bv.Uint()), true
+	case av.CanInt() && bv.CanUint():
+		if av.Int() < 0 {
+			return -1, true
+		}
+		return cmp.Compare(uint64(av.Int()), bv.Uint()), true
+	case av.CanUint() && bv.CanInt():
+		if bv.Int() < 0 {
+			return 1, true
+		}
+		return cmp.Compare(av.Uint(), uint64(bv.Int())), true
+	case av.CanFloat() || bv.CanFloat():
+		af, aok := toFloat(av)
+		bf, bok := toFloat(bv)
+		if !aok || !bok {
+			return 0, false
+		}
+		return cmp.Compare(af, bf), true
+	case av.Kind() == reflect.String && bv.Kind() == reflect.String:
+		return strings.Compare(av.String(), bv.String()), true
+	}
+	return 0, false
+}
+
+func toFloat(v reflect.Value) (float64, bool) {
+	switch {
+	case v.CanFloat():
+		return v.Float(), true
+	case v.CanInt():
+		return float64(v.Int()), true
+	case v.CanUint():
+		return float64(v.Uint()), true
+	}
+	return 0, false
+}
+
+// =-=-= Min
+
+type minConstraint struct{ min any }
+
+// Requires the value to be greater than or equal to n
+// Works with any numeric or string option, n is converted to the option's type when compared
+func Min[T cmp.Ordered](n T) Constraint { return minConstraint{n} }
+
+func (m minConstraint) Check(v any) error {
+	r, ok := compareValues(v, m.min)
+	if !ok {
+		return fmt.Errorf("%w: cannot compare %v with %v", ErrRange, v, m.min)
+	}
+	if r < 0 {
+		return fmt.Errorf("%w: %v is less than %v", ErrRange, v, m.min)
+	}
+	return nil
+}
+
+func (m minConstraint) String() string { return fmt.Sprintf("min %v", m.min) }
+
+// =-=-= Max
+
+type maxConstraint struct{ max any }
+
+// Requires the value to be less than or equal to n
+// Works with any numeric or string option, n is converted to the option's type when compared
+func Max[T cmp.Ordered](n T) Constraint { return maxConstraint{n} }
+
+func (m maxConstraint) Check(v any) error {
+	r, ok := compareValues(v, m.max)
+	if !ok {
+		return fmt.Errorf("%w: cannot compare %v with %v", ErrRange, v, m.max)
+	}
+	if r > 0 {
+		return fmt.Errorf("%w: %v is greater than %v", ErrRange, v, m.max)
+	}
+	return nil
+}
+
+func (m maxConstraint) String() string { return fmt.Sprintf("max %v", m.max) }
+
+// =-=-= OneOf
+
+type oneOfConstraint struct{ allowed []any }
+
+// Requires the value to be equal to one of the allowed values
+func OneOf[T comparable](allowed ...T) Constraint {
+	a := make([]any, len(allowed))
+	for i, v := range allowed {
+		a[i] = v
+	}
+	return oneOfConstraint{a}
+}
+
+func (o oneOfConstraint) Check(v any) error {
+	for _, a := range o.allowed {
+		if a == v {
+			return nil
+		}
+		if r, ok := compareValues(v, a); ok && r == 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %v is not one of %v", ErrRange, v, o.allowed)
+}
+
+func (o oneOfConstraint) String() string { return fmt.Sprintf("one of %v", o.allowed) }
+
+// =-=-= Pattern
+
+type patternConstraint struct{ re *regexp.Regexp }
+
+// Requires the value's string form to match the regular expression expr
+// Panics if expr does not compile, same as regexp.MustCompile
+func Pattern(expr string) Constraint { return patternConstraint{regexp.MustCompile(expr)} }
+
+func (p patternConstraint) Check(v any) error {
+	s, ok := v.(string)
+	if !ok {
+		s = fmt.Sprint(v)
+	}
+	if !p.re.MatchString(s) {
+		return fmt.Errorf("%w: %q does not match %v", ErrRange, s, p.re)
+	}
+	return nil
+}
+
+func (p patternConstraint) String() string { return fmt.Sprintf("matches %v", p.re) }
+
+// =-=-= Custom
+
+type customConstraint[T any] struct {
+	desc string
+	fn   func(T) error
+}
+
+// Requires fn to return nil for the value, desc is used to describe the constraint
+// The option's Get method must return a T or the check fails
+func Custom[T any](desc string, fn func(T) error) Constraint {
+	return customConstraint[T]{desc, fn}
+}
+
+func (c customConstraint[T]) Check(v any) error {
+	t, ok := v.(T)
+	if !ok {
+		return fmt.Errorf("constraint %q expects %v, got %T", c.desc, reflect.TypeFor[T](), v)
+	}
+	return c.fn(t)
+}
+
+func (c customConstraint[T]) String() string { return c.desc }
+
+// Runs every constraint against v, returning the first failure
+func checkConstraints(v any, constraints []Constraint) error {
+	for _, c := range constraints {
+		if err := c.Check(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the constraints attached to this option
+func (o *Option) Constraints() []Constraint { return slices.Clone(o.constraints) }
@@ -0,0 +1,83 @@
+package configManager
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func Test_minMaxConstraint(t *testing.T) {
+	var c ConfigSet
+
+	workers, err := AddOptionToSet(&c, "workers", int64(4), Min(1), Max(64))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Set("workers", "65"); !errors.Is(err, ErrRange) {
+		t.Fatalf("Set accepted value above max, err: %v", err)
+	}
+	if *workers != 4 {
+		t.Fatalf("Rejected value was not rolled back, expected: [4] received: [%v]", *workers)
+	}
+
+	if err := c.Set("workers", "0"); !errors.Is(err, ErrRange) {
+		t.Fatalf("Set accepted value below min, err: %v", err)
+	}
+
+	if err := c.Set("workers", "64"); err != nil {
+		t.Fatal(err)
+	}
+	if *workers != 64 {
+		t.Fatalf("Option value mismatch, expected: [64] received: [%v]", *workers)
+	}
+
+	if _, err := AddOptionToSet(&c, "ratio", 2.5, Min(0.0), Max(1.0)); !errors.Is(err, ErrRange) {
+		t.Fatalf("Option accepted invalid default value, err: %v", err)
+	}
+}
+
+func Test_oneOfConstraint(t *testing.T) {
+	var c ConfigSet
+
+	mode, err := AddOptionToSet(&c, "mode", "fast", OneOf("fast", "safe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ParseFromData([]byte(`{"mode":"reckless"}`)); !errors.Is(err, ErrRange) {
+		t.Fatalf("Parse accepted value outside allowed set, err: %v", err)
+	}
+	if *mode != "fast" {
+		t.Fatalf("Rejected value was not rolled back, expected: [fast] received: [%v]", *mode)
+	}
+}
+
+func Test_patternAndCustomConstraint(t *testing.T) {
+	var c ConfigSet
+
+	_, err := AddOptionToSet(&c, "name", "svc-a", Pattern(`^[a-z-]+$`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("name", "Svc A"); !errors.Is(err, ErrRange) {
+		t.Fatalf("Set accepted value not matching pattern, err: %v", err)
+	}
+
+	even := Custom("even", func(n int32) error {
+		if n%2 != 0 {
+			return fmt.Errorf("%d is odd", n)
+		}
+		return nil
+	})
+	_, err = AddOptionToSet(&c, "shards", int32(2), even)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("shards", "3"); err == nil {
+		t.Fatal("Set accepted value failing custom constraint")
+	}
+	if got := len(c.Lookup("shards").Constraints()); got != 1 {
+		t.Fatalf("Constraint count mismatch, expected: [1] received: [%v]", got)
+	}
+}
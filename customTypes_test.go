@@ -3,8 +3,10 @@ package configManager
 import (
 	"fmt"
 	"math/rand"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -82,3 +84,98 @@ func Test_customTypeOpt(t *testing.T) {
 		t.Fatalf("Option value mismatch, expected: [%v] received: [%v]", rd, ov)
 	}
 }
+
+// point saved as an object instead of through Get
+type savedPoint struct{ point }
+
+func (p savedPoint) MarshalConfig() (any, error) {
+	return map[string]float32{"x": p.x, "y": p.y}, nil
+}
+
+func Test_customMarshal(t *testing.T) {
+	var c ConfigSet
+	RegisterType(func(t *savedPoint) Value { return t })
+
+	AddOptionToSet(&c, "origin", savedPoint{point{1, 2}})
+	AddOptionToSet(&c, "name", "home")
+
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `"origin": {
+    "x": 1,
+    "y": 2
+  }`
+	if !strings.Contains(string(data), want) {
+		t.Fatalf("Custom marshalled value missing from output:\n%s", data)
+	}
+}
+
+// string that only accepts lower case, registered for a single set
+type lowerString string
+
+func Test_setRegistry(t *testing.T) {
+	RegisterType(func(p *lowerString) Value { return newStringValue((*string)(p)) })
+
+	var strict, loose ConfigSet
+	RegisterTypeInSet(&strict, func(p *lowerString) Value {
+		s := (*string)(p)
+		return &lowerValue{newStringValue(s)}
+	})
+
+	if r := ResolveType[lowerString](&strict); r != SetRegistry {
+		t.Fatalf("Expected set registry, got %v", r)
+	}
+	if r := ResolveType[lowerString](&loose); r != GlobalRegistry {
+		t.Fatalf("Expected global registry, got %v", r)
+	}
+	if r := ResolveType[complex64](&loose); r != NoRegistry {
+		t.Fatalf("Expected no registry, got %v", r)
+	}
+
+	AddOptionToSet(&strict, "name", lowerString("a"))
+	AddOptionToSet(&loose, "name", lowerString("a"))
+	if err := strict.Set("name", "ABC"); err == nil {
+		t.Fatal("Set-local factory was not used")
+	}
+	if err := loose.Set("name", "ABC"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type lowerValue struct{ *stringValue }
+
+func (l *lowerValue) Set(s string) error {
+	if strings.ToLower(s) != s {
+		return ErrParse
+	}
+	return l.stringValue.Set(s)
+}
+
+type raceType int64
+
+func Test_concurrentRegistration(t *testing.T) {
+	var c ConfigSet
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Go(func() {
+			RegisterType(func(p *raceType) Value { return newInt64Value((*int64)(p)) })
+			RegisterTypeInSet(&c, func(p *lowerString) Value { return &lowerValue{newStringValue((*string)(p))} })
+			AddOptionToSet(&c, fmt.Sprintf("opt%d", i), raceType(i))
+			AddOption(fmt.Sprintf("race.opt%d", i), int64(i))
+
+			r := c.Batch()
+			r.Int64(fmt.Sprintf("batch%d", i), 1)
+			if err := r.Commit(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if n := len(slices.Collect(c.All())); n != 32 {
+		t.Fatalf("Expected 32 options, got %d", n)
+	}
+}
@@ -0,0 +1,85 @@
+package configManager
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// An exact decimal number such as a price or a fee, kept as its text so no float rounding ever applies
+// The scale is preserved, "1.50" stays "1.50" and has a Scale of 2
+// The zero value is 0
+type Decimal string
+
+// Parses a decimal written as digits with an optional sign and fractional part, exponents aren't accepted
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	sign, digits := "", s
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		sign, digits = "-", rest
+	} else {
+		digits = strings.TrimPrefix(s, "+")
+	}
+
+	whole, frac, hasPoint := strings.Cut(digits, ".")
+	if whole == "" && frac == "" || hasPoint && frac == "" || !isDigits(whole) || !isDigits(frac) {
+		return "", fmt.Errorf("%w: invalid decimal %q", ErrParse, s)
+	}
+	whole = strings.TrimLeft(whole, "0")
+	if whole == "" {
+		whole = "0"
+	}
+	if hasPoint {
+		return Decimal(sign + whole + "." + frac), nil
+	}
+	return Decimal(sign + whole), nil
+}
+
+func isDigits(s string) bool {
+	return !strings.ContainsFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+}
+
+// Returns the number of digits after the decimal point
+func (d Decimal) Scale() int {
+	_, frac, _ := strings.Cut(string(d), ".")
+	return len(frac)
+}
+
+// Returns the exact value as a rational number
+func (d Decimal) Rat() *big.Rat {
+	r, _ := new(big.Rat).SetString(d.String())
+	return r
+}
+
+// Compares d and e by value regardless of scale, "1.5" and "1.50" are equal
+func (d Decimal) Cmp(e Decimal) int { return d.Rat().Cmp(e.Rat()) }
+
+func (d Decimal) String() string {
+	if d == "" {
+		return "0"
+	}
+	return string(d)
+}
+
+// =-=-= decimalValue
+
+type decimalValue Decimal
+
+func newDecimalValue(p *Decimal) *decimalValue { return (*decimalValue)(p) }
+
+func (d *decimalValue) Set(s string) error {
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = decimalValue(v)
+	return nil
+}
+
+func (d decimalValue) Get() any { return Decimal(d) }
+
+func (d decimalValue) String() string { return Decimal(d).String() }
+
+// Saved as a number written with every digit, JSON keeps the text exactly
+func (d decimalValue) MarshalConfig() (any, error) { return json.Number(d.String()), nil }
@@ -0,0 +1,64 @@
+package configManager
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_parseDecimal(t *testing.T) {
+	for in, want := range map[string]Decimal{
+		"1.50":   "1.50",
+		"+007.5": "7.5",
+		"-0.001": "-0.001",
+		".25":    "0.25",
+		"42":     "42",
+		" 3.10 ": "3.10",
+	} {
+		got, err := ParseDecimal(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseDecimal(%q), expected: %q received: %q %v", in, want, got, err)
+		}
+	}
+
+	for _, in := range []string{"", ".", "-", "1.", "1e3", "1,5", "0x10", "1.2.3", "--1"} {
+		if _, err := ParseDecimal(in); !errors.Is(err, ErrParse) {
+			t.Fatalf("ParseDecimal(%q) accepted an invalid decimal: %v", in, err)
+		}
+	}
+
+	d := Decimal("19.990")
+	if d.Scale() != 3 || d.Cmp("19.99") != 0 || d.Cmp("20") >= 0 {
+		t.Fatalf("Unexpected scale or comparison for %s", d)
+	}
+	var zero Decimal
+	if zero.String() != "0" || zero.Cmp("0.00") != 0 {
+		t.Fatalf("Zero value is not 0: %q", zero)
+	}
+}
+
+func Test_decimalOption(t *testing.T) {
+	var c ConfigSet
+	fee, err := AddOptionToSet(&c, "billing.fee", Decimal("0.25"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	price, _ := AddOptionToSet(&c, "billing.price", Decimal("0"))
+	if err := c.ParseFromData([]byte(`{"billing": {"fee": 0.10, "price": "1234567890123456.78"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *fee != "0.10" || fee.Scale() != 2 {
+		t.Fatalf("Scale lost, expected: 0.10 received: %s", *fee)
+	}
+	if *price != "1234567890123456.78" {
+		t.Fatalf("Digits lost: %s", *price)
+	}
+
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "0.10") || !strings.Contains(string(data), "1234567890123456.78") {
+		t.Fatalf("Decimals not saved exactly:\n%s", data)
+	}
+}
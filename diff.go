@@ -0,0 +1,66 @@
+package configManager
+
+import (
+	"slices"
+	"strings"
+)
+
+// An option whose value changed, values are the ones returned by the option's Get
+type Change struct {
+	Name     string
+	Old, New any
+}
+
+// Options whose value changed, sorted by name
+type Diff []Change
+
+// Returns the names of the changed options
+func (d Diff) Names() []string {
+	names := make([]string, len(d))
+	for i, c := range d {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Reports whether the named option changed
+func (d Diff) Has(name string) bool {
+	_, found := slices.BinarySearchFunc(d, name, func(c Change, name string) int { return strings.Compare(c.Name, name) })
+	return found
+}
+
+// Reports whether any option in group changed, e.g. HasGroup("db") to know the database needs reconnecting
+func (d Diff) HasGroup(group string) bool {
+	group = strings.TrimSuffix(group, NamespaceSeparator)
+	return slices.ContainsFunc(d, func(c Change) bool { return inGroup(c.Name, group) })
+}
+
+// Re-reads every file and source and applies them as the complete configuration, all or nothing
+// Options already set are overwritten, and those no longer present go back to their default
+// Returns the options whose value changed, nothing is changed when an error is returned
+func (c *ConfigSet) Reload() (Diff, error) {
+	c = c.base()
+	c.mu.Lock()
+	before := c.snapshot()
+	changed, err := c.reloadFile()
+	after := c.snapshot()
+	c.mu.Unlock()
+	if err != nil {
+		c.emit(ReloadFailed, c.Location, err)
+		return nil, err
+	}
+
+	diff := make(Diff, 0, len(changed))
+	for _, name := range changed {
+		old, _ := before.Get(name)
+		cur, _ := after.Get(name)
+		diff = append(diff, Change{name, old, cur})
+	}
+	if len(diff) > 0 {
+		c.emit(Changed, c.Location, nil, changed...)
+	}
+	return diff, nil
+}
+
+// Reloads the global set, returning the options whose value changed
+func Reload() (Diff, error) { return globalConfig.Reload() }
@@ -0,0 +1,46 @@
+package configManager
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test_reloadDiff(t *testing.T) {
+	var mem memFS
+	mem.WriteFile("app.json", []byte(`{"db": {"host": "a", "port": 5432}, "workers": 4}`), 0o644)
+
+	var c ConfigSet
+	c.FS = &mem
+	c.Location = "app.json"
+	AddOptionToSet(&c, "db.host", "localhost")
+	AddOptionToSet(&c, "db.port", int64(0), Min(int64(0)))
+	AddOptionToSet(&c, "workers", int64(1))
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := c.Reload()
+	if err != nil || len(diff) != 0 {
+		t.Fatalf("Unchanged file produced a diff: %v %v", diff, err)
+	}
+
+	mem.WriteFile("app.json", []byte(`{"db": {"host": "b", "port": 5432}}`), 0o644)
+	diff, err = c.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(diff.Names(), []string{"db.host", "workers"}) {
+		t.Fatalf("Unexpected diff: %v", diff)
+	}
+	if diff[0] != (Change{"db.host", "a", "b"}) || diff[1] != (Change{"workers", int64(4), int64(1)}) {
+		t.Fatalf("Unexpected changes: %+v", diff)
+	}
+	if !diff.Has("workers") || diff.Has("db.port") || !diff.HasGroup("db") || diff.HasGroup("d") {
+		t.Fatal("Unexpected diff lookups")
+	}
+
+	mem.WriteFile("app.json", []byte(`{"db": {"host": "c", "port": -1}}`), 0o644)
+	if diff, err := c.Reload(); err == nil || diff != nil || c.View().String("db.host") != "b" {
+		t.Fatalf("Rejected reload applied: %v %v", diff, err)
+	}
+}
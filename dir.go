@@ -0,0 +1,77 @@
+package configManager
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// =-=-= dirValue
+
+type dirValue struct {
+	ptr  *string
+	val  string
+	mode fs.FileMode
+}
+
+func newDirValue(p *string, mode fs.FileMode) *dirValue {
+	return &dirValue{
+		ptr:  p,
+		mode: mode,
+	}
+}
+
+func (d *dirValue) Set(s string) error {
+	s = strings.TrimSpace(s)
+	if s != "" {
+		if d.mode != 0 {
+			if err := os.MkdirAll(s, d.mode); err != nil {
+				return err
+			}
+		}
+		info, err := os.Stat(s)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%w: %s is not a directory", ErrParse, s)
+		}
+	}
+	d.val = s
+	*d.ptr = s
+	return nil
+}
+
+func (d dirValue) Get() any { return d.val }
+
+func (d dirValue) String() string { return d.val }
+
+// Defines a new directory option on the set c, checked whenever it's set, the default included
+// With a mode of 0 the directory must exist, otherwise it's created with its parents using mode
+// An empty path is accepted without checks, for directories that are optional
+func DirVarSet(c *ConfigSet, p *string, key, defaultValue string, mode fs.FileMode) error {
+	v := newDirValue(p, mode)
+	err := v.Set(defaultValue)
+	if err != nil {
+		return err
+	}
+	return c.Var(v, key)
+}
+
+// Defines a new directory option on the set c, created with mode unless it's 0
+func DirSet(c *ConfigSet, key, defaultValue string, mode fs.FileMode) (*string, error) {
+	p := new(string)
+	err := DirVarSet(c, p, key, defaultValue, mode)
+	return p, err
+}
+
+// Defines a new directory option on the global set, created with mode unless it's 0
+func DirVar(p *string, key, defaultValue string, mode fs.FileMode) error {
+	return DirVarSet(&globalConfig, p, key, defaultValue, mode)
+}
+
+// Defines a new directory option on the global set, created with mode unless it's 0
+func Dir(key, defaultValue string, mode fs.FileMode) (*string, error) {
+	return DirSet(&globalConfig, key, defaultValue, mode)
+}
@@ -0,0 +1,49 @@
+package configManager
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_dirOption(t *testing.T) {
+	tmp := t.TempDir()
+	file := filepath.Join(tmp, "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var c ConfigSet
+	if _, err := DirSet(&c, "missing", filepath.Join(tmp, "missing"), 0); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Option accepted a missing directory: %v", err)
+	}
+	if _, err := DirSet(&c, "file", file, 0); !errors.Is(err, ErrParse) {
+		t.Fatalf("Option accepted a file: %v", err)
+	}
+
+	data, err := DirSet(&c, "data", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, err := DirSet(&c, "cache", filepath.Join(tmp, "default"), 0o750)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := filepath.Join(tmp, "cache", "app")
+	doc, _ := json.Marshal(map[string]string{"data": tmp, "cache": want})
+	if err := c.ParseFromData(doc); err != nil {
+		t.Fatal(err)
+	}
+	if *data != tmp || *cache != want {
+		t.Fatalf("Unexpected directories: %q %q", *data, *cache)
+	}
+	for _, dir := range []string{filepath.Join(tmp, "default"), want} {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			t.Fatalf("Directory %s not created: %v", dir, err)
+		}
+	}
+}
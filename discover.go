@@ -0,0 +1,65 @@
+package configManager
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+)
+
+// Walks up from the working directory looking for files named name, the way .editorconfig is found
+// Every file found is added as a source, nearest first so it takes precedence over the ones above it
+// Returns the files found from the root down
+func (c *ConfigSet) Discover(name string) ([]string, error) {
+	c = c.base()
+	dir := "."
+	if c.FS == nil {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		dir = wd
+	}
+	return c.DiscoverFrom(dir, name)
+}
+
+// Walks up from dir looking for files named name, see Discover
+// On FS dir is an fs.FS path and the walk ends at its root
+func (c *ConfigSet) DiscoverFrom(dir, name string) ([]string, error) {
+	c = c.base()
+	var found []string
+	for {
+		loc := joinPath(c.FS, dir, name)
+		_, err := statFile(c.FS, loc)
+		if err == nil {
+			found = append(found, loc)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+		parent := dirOf(c.FS, dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	for _, loc := range found {
+		c.AddSource(FileSource{c.FS, loc})
+	}
+	slices.Reverse(found)
+	return found, nil
+}
+
+// Joins path elements, fs.FS paths are always slash separated
+func joinPath(fsys fs.FS, elem ...string) string {
+	if fsys == nil {
+		return filepath.Join(elem...)
+	}
+	return path.Join(elem...)
+}
+
+// Walks up from the working directory looking for files named name and adds them as sources of the global set
+func Discover(name string) ([]string, error) { return globalConfig.Discover(name) }
@@ -0,0 +1,51 @@
+package configManager
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func Test_discover(t *testing.T) {
+	var mem memFS
+	mem.WriteFile(".app.json", []byte(`{"greeting":"root","name":"root","workers":1}`), 0644)
+	mem.WriteFile("work/.app.json", []byte(`{"name":"work"}`), 0644)
+	mem.WriteFile("work/project/src/.app.json", []byte(`{"greeting":"src"}`), 0644)
+
+	c := ConfigSet{FS: &mem}
+	greeting, _ := AddOptionToSet(&c, "greeting", "")
+	name, _ := AddOptionToSet(&c, "name", "")
+	workers, _ := AddOptionToSet(&c, "workers", int64(0))
+
+	found, err := c.DiscoverFrom("work/project/src", ".app.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{".app.json", "work/.app.json", "work/project/src/.app.json"}; !slices.Equal(found, want) {
+		t.Fatalf("Unexpected files found: %v", found)
+	}
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *greeting != "src" || *name != "work" || *workers != 1 {
+		t.Fatalf("Files not merged root to leaf: [%v] [%v] [%v]", *greeting, *name, *workers)
+	}
+}
+
+func Test_discoverOS(t *testing.T) {
+	root := t.TempDir()
+	leaf := filepath.Join(root, "a", "b")
+	os.MkdirAll(leaf, 0755)
+	os.WriteFile(filepath.Join(root, "a", ".app.json"), []byte(`{}`), 0644)
+	t.Chdir(leaf)
+
+	var c ConfigSet
+	found, err := c.Discover(".app.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0] != filepath.Join(root, "a", ".app.json") || len(c.Sources) != 1 {
+		t.Fatalf("Unexpected files found: %v", found)
+	}
+}
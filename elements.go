@@ -0,0 +1,236 @@
+package configManager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A step of an element path, see Set
+type pathStep struct {
+	key   string
+	index int // -1 when the step is a key
+}
+
+// Splits name into the structured option it starts with and the path to an element of its value
+// e.g. "listeners[1].port" or "labels.env", ok is false when no option is a prefix of name
+func (c *ConfigSet) elementPath(name string) (o *Option, path []pathStep, ok bool, err error) {
+	for i := len(name) - 1; i > 0; i-- {
+		if name[i] != '.' && name[i] != '[' {
+			continue
+		}
+		if o = c.formal[name[:i]]; o == nil {
+			continue
+		}
+		path, err = parseElementPath(name[i:])
+		return o, path, true, err
+	}
+	return nil, nil, false, nil
+}
+
+// Parses steps written as .key, [index] or [key], brackets allow keys holding dots such as [app.kubernetes.io/name]
+func parseElementPath(s string) ([]pathStep, error) {
+	var path []pathStep
+	for s != "" {
+		var step string
+		switch s[0] {
+		case '.':
+			end := strings.IndexAny(s[1:], ".[")
+			if end < 0 {
+				end = len(s) - 1
+			}
+			step, s = s[1:end+1], s[end+1:]
+			path = append(path, pathStep{step, -1})
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated [ in element path", ErrParse)
+			}
+			step, s = s[1:end], s[end+1:]
+			if n, err := strconv.Atoi(step); err == nil && n >= 0 {
+				path = append(path, pathStep{"", n})
+			} else {
+				path = append(path, pathStep{step, -1})
+			}
+		default:
+			return nil, fmt.Errorf("%w: expected . or [ in element path at %q", ErrParse, s)
+		}
+		if step == "" {
+			return nil, fmt.Errorf("%w: empty step in element path", ErrParse)
+		}
+	}
+	return path, nil
+}
+
+// Returns the value of o with the element at path replaced by value, as text o's Set accepts
+// Elements keep their type: value is parsed as JSON unless the element, or its siblings when it's new, are strings
+// Arrays are indexed from 0, the index after the last element appends, and arrays of objects may be indexed by BlockNameKey
+func patchElement(o *Option, path []pathStep, value string) (string, error) {
+	current, err := marshalValue(o.Value)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(current)
+	if err != nil {
+		return "", err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	tree, err := decodeOrdered(dec)
+	if err != nil {
+		return "", err
+	}
+
+	tree, err = setElement(tree, path, value, false)
+	if err != nil {
+		return "", err
+	}
+	data, err = json.Marshal(tree)
+	return string(data), err
+}
+
+// Decodes the next JSON value keeping object key order, numbers are kept as written
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		m := OrderedMap{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, MapEntry{key.(string), v})
+		}
+		_, err = dec.Token()
+		return m, err
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			v, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		_, err = dec.Token()
+		return list, err
+	}
+	return tok, nil
+}
+
+// Replaces the element at path under node, creating missing objects on the way
+// textual tells whether node's siblings are all strings, new elements are strings then
+func setElement(node any, path []pathStep, value string, textual bool) (any, error) {
+	if len(path) == 0 {
+		return elementValue(node, value, textual)
+	}
+	step, rest := path[0], path[1:]
+
+	switch n := node.(type) {
+	case nil:
+		if step.index >= 0 {
+			return nil, fmt.Errorf("%w: no element [%d]", ErrRange, step.index)
+		}
+		v, err := setElement(nil, rest, value, false)
+		return OrderedMap{{step.key, v}}, err
+
+	case OrderedMap:
+		if step.index >= 0 {
+			return nil, fmt.Errorf("%w: [%d] indexes an object", ErrParse, step.index)
+		}
+		siblings := allStrings(n.values())
+		for i, e := range n {
+			if e.Key == step.key {
+				v, err := setElement(e.Value, rest, value, siblings)
+				n[i].Value = v
+				return n, err
+			}
+		}
+		v, err := setElement(nil, rest, value, siblings)
+		return append(n, MapEntry{step.key, v}), err
+
+	case []any:
+		i := step.index
+		if step.index < 0 {
+			// arrays of blocks are indexed by name
+			i = -1
+			for j, e := range n {
+				if m, ok := e.(OrderedMap); ok {
+					if name, _ := m.Get(BlockNameKey); name == step.key {
+						i = j
+					}
+				}
+			}
+			if i < 0 {
+				return nil, fmt.Errorf("%w: no element named %q", ErrRange, step.key)
+			}
+		}
+		if i > len(n) {
+			return nil, fmt.Errorf("%w: no element [%d], there are %d", ErrRange, i, len(n))
+		}
+		if i == len(n) {
+			n = append(n, nil)
+		}
+		v, err := setElement(n[i], rest, value, allStrings(n))
+		n[i] = v
+		return n, err
+	}
+	return nil, fmt.Errorf("%w: %s: not an object or array", ErrParse, step.key)
+}
+
+// Returns value as a replacement for old, keeping its type
+func elementValue(old any, value string, textual bool) (any, error) {
+	if _, ok := old.(string); ok || old == nil && textual {
+		return value, nil
+	}
+
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.UseNumber()
+	v, err := decodeOrdered(dec)
+	if err != nil || dec.More() {
+		if old == nil {
+			return value, nil
+		}
+		return nil, fmt.Errorf("%w: %q is not valid JSON", ErrParse, value)
+	}
+
+	switch old.(type) {
+	case json.Number:
+		if _, ok := v.(json.Number); !ok {
+			return nil, fmt.Errorf("%w: expected a number, got %q", ErrParse, value)
+		}
+	case bool:
+		if _, ok := v.(bool); !ok {
+			return nil, fmt.Errorf("%w: expected true or false, got %q", ErrParse, value)
+		}
+	}
+	return v, nil
+}
+
+func (m OrderedMap) values() []any {
+	values := make([]any, len(m))
+	for i, e := range m {
+		values[i] = e.Value
+	}
+	return values
+}
+
+// Reports whether values holds strings only, false when it's empty
+func allStrings(values []any) bool {
+	for _, v := range values {
+		if _, ok := v.(string); !ok {
+			return false
+		}
+	}
+	return len(values) > 0
+}
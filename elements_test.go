@@ -0,0 +1,66 @@
+package configManager
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_setElement(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "listeners", BlockList{
+		{"name": "api", "port": 80.0},
+		{"name": "admin", "port": 81.0},
+	})
+	AddOptionToSet(&c, "labels", HeaderMap{"Env": "dev"})
+	AddOptionToSet(&c, "routes", OrderedMap{{"/b", "x"}, {"/a", "y"}})
+	hosts, _ := StringListSet(&c, "hosts", []string{"a", "b"}, ",", true)
+
+	for _, tc := range []struct{ name, value string }{
+		{"listeners[1].port", "8081"},
+		{"listeners[api].port", "8080"},
+		{"listeners[2]", `{"name": "metrics", "port": 9090}`},
+		{"labels.Env", "prod"},
+		{"labels[X-Team]", "core"},
+		{"routes[/a]", "z"},
+		{"hosts[1]", "c"},
+	} {
+		if err := c.Set(tc.name, tc.value); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+	}
+
+	l := c.Blocks("listeners")
+	if len(l) != 3 || l[0].Int("port") != 8080 || l[1].Int("port") != 8081 || l[2].Name() != "metrics" {
+		t.Fatalf("Listeners not patched: %v", l)
+	}
+	if h := c.Lookup("labels").Value.String(); h != `{"Env":"prod","X-Team":"core"}` {
+		t.Fatalf("Labels not patched: %s", h)
+	}
+	if r := c.Lookup("routes").Value.String(); r != `{"/b":"x","/a":"z"}` {
+		t.Fatalf("Routes not patched in order: %s", r)
+	}
+	if (*hosts)[1] != "c" {
+		t.Fatalf("Hosts not patched: %v", *hosts)
+	}
+	if !c.View().IsSet("listeners") {
+		t.Fatal("Patched option not marked set")
+	}
+
+	for _, tc := range []struct {
+		name, value string
+		err         error
+	}{
+		{"listeners[5].port", "1", ErrRange},
+		{"listeners[web].port", "1", ErrRange},
+		{"listeners[0].port", "fast", ErrParse},
+		{"listeners[0", "1", ErrParse},
+		{"labels.Host", "example.com", ErrParse},
+	} {
+		if err := c.Set(tc.name, tc.value); !errors.Is(err, tc.err) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.err, err)
+		}
+	}
+	if err := c.Set("missing.key", "1"); err == nil {
+		t.Fatal("Path into an unknown option accepted")
+	}
+}
@@ -0,0 +1,134 @@
+package configManager
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Prefix of encrypted field envelopes, "enc:v1:<key id>:<base64 nonce and ciphertext>"
+const envelopePrefix = "enc:v1:"
+
+// Returned when an encrypted value can't be decrypted
+var ErrDecrypt = errors.New("cannot decrypt value")
+
+// Holds the keys used to encrypt individual option values
+// Values are encrypted with the primary key and decrypted with whichever key their envelope names,
+// so keys can be rotated by adding a new primary and saving once
+type Keyring struct {
+	keys    map[string]cipher.AEAD
+	primary string
+}
+
+// Returns an empty keyring
+func NewKeyring() *Keyring { return &Keyring{keys: make(map[string]cipher.AEAD)} }
+
+// Adds an AES-256 key under id, the first key added becomes the primary
+// id must not contain a colon
+func (k *Keyring) Add(id string, key []byte) error {
+	if id == "" || strings.Contains(id, ":") {
+		return fmt.Errorf("invalid key id %q", id)
+	}
+	if len(key) != 32 {
+		return fmt.Errorf("key %s must be 32 bytes, got %d", id, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	k.keys[id] = aead
+	if k.primary == "" {
+		k.primary = id
+	}
+	return nil
+}
+
+// Makes the key with the given id the one new values are encrypted with
+func (k *Keyring) SetPrimary(id string) error {
+	if _, ok := k.keys[id]; !ok {
+		return fmt.Errorf("unknown key %s", id)
+	}
+	k.primary = id
+	return nil
+}
+
+// Additional data authenticated with a value, the key id so an envelope can't be moved to another key
+// and the option name so it can't be moved to another option
+func envelopeAAD(id, name string) []byte { return []byte(id + "\x00" + name) }
+
+// Encrypts the value of the named option with the primary key into an envelope
+// The envelope only decrypts for that option
+func (k *Keyring) Encrypt(name, plaintext string) (string, error) {
+	aead, ok := k.keys[k.primary]
+	if !ok {
+		return "", fmt.Errorf("keyring has no keys")
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), envelopeAAD(k.primary, name))
+	return envelopePrefix + k.primary + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypts an envelope Encrypt produced for the named option
+func (k *Keyring) Decrypt(name, envelope string) (string, error) {
+	rest, ok := strings.CutPrefix(envelope, envelopePrefix)
+	if !ok {
+		return "", fmt.Errorf("%w: not an encrypted envelope", ErrDecrypt)
+	}
+	id, data, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", fmt.Errorf("%w: malformed envelope", ErrDecrypt)
+	}
+	aead, ok := k.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown key %s", ErrDecrypt, id)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(data)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("%w: malformed envelope", ErrDecrypt)
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, envelopeAAD(id, name))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+	return string(plain), nil
+}
+
+// Reports whether s looks like an encrypted envelope
+func IsEncrypted(s string) bool { return strings.HasPrefix(s, envelopePrefix) }
+
+// Marks the named option as encrypted with keys from kr
+// Envelopes are decrypted before the value is set, plain values are still accepted so existing files can be migrated,
+// and SaveTo and Export always write the value encrypted with the primary key
+func (c *ConfigSet) EncryptField(name string, kr *Keyring) error {
+	c, name = c.owner(name)
+	err := c.Transform(name, func(name, value string) (string, error) {
+		if !IsEncrypted(value) {
+			return value, nil
+		}
+		return kr.Decrypt(name, value)
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.encrypted == nil {
+		c.encrypted = make(map[string]*Keyring)
+	}
+	c.encrypted[name] = kr
+	return nil
+}
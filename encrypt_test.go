@@ -0,0 +1,106 @@
+package configManager
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func Test_encryptedField(t *testing.T) {
+	kr := NewKeyring()
+	kr.Add("k1", bytes.Repeat([]byte{1}, 32))
+
+	var c ConfigSet
+	password, _ := AddOptionToSet(&c, "db.password", "")
+	AddOptionToSet(&c, "db.user", "admin")
+	c.EncryptField("db.password", kr)
+
+	// plain values are accepted for migration
+	if err := c.ParseFromData([]byte(`{"db.password":"hunter2"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("hunter2")) || !bytes.Contains(data, []byte(`"admin"`)) {
+		t.Fatalf("Unexpected saved data:\n%s", data)
+	}
+
+	// rotate: files written with k1 still load, new saves use k2
+	kr.Add("k2", bytes.Repeat([]byte{2}, 32))
+	kr.SetPrimary("k2")
+
+	var d ConfigSet
+	password, _ = AddOptionToSet(&d, "db.password", "")
+	d.EncryptField("db.password", kr)
+	if err := d.ParseFromData(data); err != nil {
+		t.Fatal(err)
+	}
+	if *password != "hunter2" {
+		t.Fatalf("Decrypted value mismatch, received: [%v]", *password)
+	}
+
+	rotated, _ := d.SaveTo()
+	// dotted names are saved nested
+	var saved map[string]map[string]string
+	json.Unmarshal(rotated, &saved)
+	if !IsEncrypted(saved["db"]["password"]) || saved["db"]["password"][:10] != "enc:v1:k2:" {
+		t.Fatalf("Value not re-encrypted with new primary: %v", saved["db"]["password"])
+	}
+
+	other := NewKeyring()
+	other.Add("k2", bytes.Repeat([]byte{3}, 32))
+	if _, err := other.Decrypt("db.password", saved["db"]["password"]); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("Decrypt accepted wrong key, err: %v", err)
+	}
+}
+
+func Test_encryptedFieldBinding(t *testing.T) {
+	kr := NewKeyring()
+	kr.Add("k1", bytes.Repeat([]byte{1}, 32))
+
+	var c ConfigSet
+	AddOptionToSet(&c, "admin_token", "")
+	guest, _ := AddOptionToSet(&c, "guest_token", "")
+	c.EncryptField("admin_token", kr)
+	c.EncryptField("guest_token", kr)
+
+	admin, _ := kr.Encrypt("admin_token", "root")
+	if _, err := kr.Decrypt("guest_token", admin); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("Envelope decrypted for another option, err: %v", err)
+	}
+	if err := c.Set("guest_token", admin); !errors.Is(err, ErrDecrypt) || *guest != "" {
+		t.Fatalf("Envelope moved to another option was accepted, err: %v", err)
+	}
+}
+
+func Test_encryptedFieldExport(t *testing.T) {
+	kr := NewKeyring()
+	kr.Add("k1", bytes.Repeat([]byte{1}, 32))
+
+	var c ConfigSet
+	AddOptionToSet(&c, "password", "")
+	c.EncryptField("password", kr)
+	c.Set("password", "hunter2")
+
+	data, err := c.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("hunter2")) {
+		t.Fatalf("Encrypted field exported in plaintext:\n%s", data)
+	}
+
+	var d ConfigSet
+	password, _ := AddOptionToSet(&d, "password", "")
+	d.EncryptField("password", kr)
+	if err := d.Import(data); err != nil {
+		t.Fatal(err)
+	}
+	if *password != "hunter2" {
+		t.Fatalf("Imported value mismatch, received: [%v]", *password)
+	}
+}
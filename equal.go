@@ -0,0 +1,136 @@
+package configManager
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Reports whether a and b hold semantically equal option values
+// Unlike == and reflect.DeepEqual, NaN equals NaN and -0 equals +0, recursively through
+// slices, arrays, maps, structs and pointers, so float options don't produce spurious changes
+func Equal(a, b any) bool {
+	return equalValues(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+func equalValues(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Float32, reflect.Float64:
+		x, y := a.Float(), b.Float()
+		if math.IsNaN(x) && math.IsNaN(y) {
+			return true
+		}
+		return x == y
+	case reflect.Complex64, reflect.Complex128:
+		x, y := a.Complex(), b.Complex()
+		return equalValues(reflect.ValueOf(real(x)), reflect.ValueOf(real(y))) &&
+			equalValues(reflect.ValueOf(imag(x)), reflect.ValueOf(imag(y)))
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equalValues(a.Elem(), b.Elem())
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() {
+			return false
+		}
+		fallthrough
+	case reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := range a.Len() {
+			if !equalValues(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		iter := a.MapRange()
+		for iter.Next() {
+			bv := b.MapIndex(iter.Key())
+			if !bv.IsValid() || !equalValues(iter.Value(), bv) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := range a.NumField() {
+			if !equalValues(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Func:
+		return a.IsNil() && b.IsNil()
+	}
+
+	if a.Comparable() {
+		return a.Equal(b)
+	}
+	return false
+}
+
+// Decides whether two values of an option are the same for change detection
+type EqualFunc func(a, b any) bool
+
+// Sets how changes of the named option are detected, replacing Equal
+// Reloads and schedule refreshes only report the option as changed when eq returns false,
+// so semantically equal rewrites of the file don't trigger OnReload or onChange
+func (c *ConfigSet) SetEqualFunc(name string, eq EqualFunc) error {
+	c, name = c.owner(name)
+	if _, ok := c.formal[name]; !ok {
+		return fmt.Errorf("No such option: %v", name)
+	}
+	if c.equals == nil {
+		c.equals = make(map[string]EqualFunc)
+	}
+	c.equals[name] = eq
+	return nil
+}
+
+// Sets how changes of the named option of the global set are detected
+func SetEqualFunc(name string, eq EqualFunc) error { return globalConfig.SetEqualFunc(name, eq) }
+
+// Reports whether a and b are equal values of the named option
+func (c *ConfigSet) optionEqual(name string, a, b any) bool {
+	if eq, ok := c.equals[name]; ok {
+		return eq(a, b)
+	}
+	return Equal(a, b)
+}
+
+// =-=-= Built in comparisons
+
+// Compares strings ignoring case, other values with Equal
+func EqualFold(a, b any) bool {
+	x, xok := a.(string)
+	y, yok := b.(string)
+	if xok && yok {
+		return strings.EqualFold(x, y)
+	}
+	return Equal(a, b)
+}
+
+// Compares numbers as equal when they differ by at most epsilon, other values with Equal
+func Within(epsilon float64) EqualFunc {
+	return func(a, b any) bool {
+		x, xok := toFloat(reflect.ValueOf(a))
+		y, yok := toFloat(reflect.ValueOf(b))
+		if xok && yok {
+			return Equal(x, y) || math.Abs(x-y) <= epsilon
+		}
+		return Equal(a, b)
+	}
+}
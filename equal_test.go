@@ -0,0 +1,77 @@
+package configManager
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_equal(t *testing.T) {
+	nan := math.NaN()
+	negZero := math.Copysign(0, -1)
+
+	cases := []struct {
+		a, b any
+		want bool
+	}{
+		{nan, nan, true},
+		{float32(nan), float32(nan), true},
+		{negZero, 0.0, true},
+		{1.5, 1.5, true},
+		{1.5, 2.5, false},
+		{float32(1), 1.0, false},
+		{[]float64{nan, 1}, []float64{nan, 1}, true},
+		{map[string]float64{"a": nan}, map[string]float64{"a": nan}, true},
+		{point{float32(nan), 1}, point{float32(nan), 1}, true},
+		{"foo", "foo", true},
+		{"foo", "bar", false},
+		{nil, nil, true},
+		{nil, 0, false},
+	}
+
+	for _, tc := range cases {
+		if got := Equal(tc.a, tc.b); got != tc.want {
+			t.Errorf("Equal(%v, %v) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func Test_isZeroNegativeZero(t *testing.T) {
+	var c ConfigSet
+
+	AddOptionToSet(&c, "offset", 1.0)
+	c.Set("offset", "-0")
+
+	iz, err := c.IsZeroValue("offset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !iz {
+		t.Fatal("-0 not reported as zero value")
+	}
+}
+
+func Test_setEqualFunc(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "mode", "auto")
+	AddOptionToSet(&c, "ratio", 0.5)
+	AddOptionToSet(&c, "workers", int64(1))
+	c.SetEqualFunc("mode", EqualFold)
+	c.SetEqualFunc("ratio", Within(0.01))
+	if err := c.SetEqualFunc("nope", EqualFold); err == nil {
+		t.Fatal("Comparison set on unknown option")
+	}
+
+	c.ParseFromData([]byte(`{"mode":"auto","ratio":0.5,"workers":1}`))
+	changed, err := c.reloadData([]byte(`{"mode":"AUTO","ratio":0.505,"workers":2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 1 || changed[0] != "workers" {
+		t.Fatalf("Unexpected changed options: %v", changed)
+	}
+
+	changed, _ = c.reloadData([]byte(`{"mode":"manual","ratio":0.6,"workers":2}`))
+	if len(changed) != 2 {
+		t.Fatalf("Unexpected changed options: %v", changed)
+	}
+}
@@ -0,0 +1,72 @@
+package configManager
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// What Parse does when it fails or an option rejects its value, see SetErrorHandling
+type ErrorHandling int
+
+const (
+	StopOnError    ErrorHandling = iota // Parse returns the error, the default
+	DefaultOnError                      // Options rejecting their value keep it, usually the default, and parsing carries on
+	ExitOnError                         // The program exits with status 2 after the error is reported
+	PanicOnError                        // Parse panics with the error
+)
+
+// Sets how Parse, ParseContext, ParseFromData, ParseWith and ParseFS handle errors
+// With DefaultOnError a rejected value is reported as a warning instead of failing, as most GUI applications want,
+// errors concerning the whole file such as a syntax error are still returned
+func (c *ConfigSet) SetErrorHandling(h ErrorHandling) { c.base().errorHandling = h }
+
+// Returns how Parse handles errors, StopOnError unless changed with SetErrorHandling
+func (c *ConfigSet) ErrorHandling() ErrorHandling { return c.base().errorHandling }
+
+// Sets how Parse handles errors for the global set
+func SetErrorHandling(h ErrorHandling) { globalConfig.SetErrorHandling(h) }
+
+// Applies the set's ErrorHandling to an error Parse is about to return
+func (c *ConfigSet) handle(err error) error {
+	if err == nil {
+		return nil
+	}
+	c.notify(err)
+	switch c.errorHandling {
+	case ExitOnError:
+		var w io.Writer = os.Stderr
+		if c.output != nil {
+			w = c.output
+		}
+		fmt.Fprintln(w, err)
+		exit(2)
+	case PanicOnError:
+		panic(err)
+	}
+	return err
+}
+
+// Hands err to OnError when it's set
+func (c *ConfigSet) notify(err error) {
+	if c.OnError != nil {
+		c.OnError(err)
+	}
+}
+
+// Ends the program for ExitOnError, replaced by tests
+var exit = os.Exit
+
+func (h ErrorHandling) String() string {
+	switch h {
+	case StopOnError:
+		return "stop on error"
+	case DefaultOnError:
+		return "default on error"
+	case ExitOnError:
+		return "exit on error"
+	case PanicOnError:
+		return "panic on error"
+	}
+	return fmt.Sprintf("ErrorHandling(%d)", int(h))
+}
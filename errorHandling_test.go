@@ -0,0 +1,109 @@
+package configManager
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+const badWorkers = `{"workers": "many", "ratio": 0.25, "name": "api"}`
+
+func errorHandlingSet(h ErrorHandling) (*ConfigSet, *int64, *float64, *string) {
+	var c ConfigSet
+	c.SetErrorHandling(h)
+	workers, _ := AddOptionToSet(&c, "workers", int64(4))
+	ratio, _ := AddOptionToSet(&c, "ratio", 0.5, Min(0.5))
+	name, _ := AddOptionToSet(&c, "name", "")
+	return &c, workers, ratio, name
+}
+
+func Test_defaultOnError(t *testing.T) {
+	c, workers, ratio, name := errorHandlingSet(DefaultOnError)
+	if err := c.ParseFromData([]byte(badWorkers)); err != nil {
+		t.Fatal(err)
+	}
+	if *workers != 4 || *ratio != 0.5 || *name != "api" {
+		t.Fatalf("Unexpected values: [%v] [%v] [%v]", *workers, *ratio, *name)
+	}
+	want := []string{
+		`data: ratio: invalid value "0.25": value outside allowed range: 0.25 is less than 0.5, keeping "0.5"`,
+		`data: workers: invalid value "many": parse error, keeping "4"`,
+	}
+	problems := c.ParseReport()
+	if len(problems) != len(want) {
+		t.Fatalf("Unexpected problems: %v", problems)
+	}
+	for i, p := range problems {
+		if !p.Warning || p.String() != want[i] {
+			t.Fatalf("Expected warning [%s] received [%s]", want[i], p)
+		}
+	}
+
+	if err := c.ParseFromData([]byte(`{"workers": `)); err == nil {
+		t.Fatal("Syntax error ignored")
+	}
+}
+
+func Test_stopOnError(t *testing.T) {
+	c, _, _, name := errorHandlingSet(StopOnError)
+	if err := c.ParseFromData([]byte(badWorkers)); !errors.Is(err, ErrParse) && !errors.Is(err, ErrRange) {
+		t.Fatalf("Rejected value accepted: %v", err)
+	}
+	if *name != "api" {
+		t.Fatalf("Other options not parsed: [%v]", *name)
+	}
+}
+
+func Test_panicOnError(t *testing.T) {
+	c, _, _, _ := errorHandlingSet(PanicOnError)
+	defer func() {
+		if e, ok := recover().(error); !ok || !errors.Is(e, ErrRange) && !errors.Is(e, ErrParse) {
+			t.Fatalf("Unexpected panic: %v", e)
+		}
+	}()
+	c.ParseFromData([]byte(badWorkers))
+	t.Fatal("Parse didn't panic")
+}
+
+func Test_exitOnError(t *testing.T) {
+	code := -1
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	c, _, _, _ := errorHandlingSet(ExitOnError)
+	var out bytes.Buffer
+	c.SetOutput(&out)
+	c.ParseFromData([]byte(badWorkers))
+	if code != 2 || !strings.Contains(out.String(), "workers") {
+		t.Fatalf("Didn't exit: [%d]\n%s", code, out.String())
+	}
+}
+
+func Test_onError(t *testing.T) {
+	var errs []error
+	c, _, _, _ := errorHandlingSet(StopOnError)
+	c.OnError = func(err error) { errs = append(errs, err) }
+
+	err := c.ParseFromData([]byte(badWorkers))
+	if err == nil || len(errs) != 1 || errs[0] != err {
+		t.Fatalf("Parse error not handed to OnError: %v %v", err, errs)
+	}
+	if c.ParseFromData([]byte(`{}`)); len(errs) != 1 {
+		t.Fatalf("OnError called without an error: %v", errs)
+	}
+
+	c.Lookup("workers").Required = true
+	if err := c.Validate(); err == nil || len(errs) != 2 || errs[1] != err {
+		t.Fatalf("Validation error not handed to OnError: %v %v", err, errs)
+	}
+
+	var mem memFS
+	mem.WriteFile("app.json", []byte(`{`), 0o644)
+	d := ConfigSet{FS: &mem, Location: "app.json", Failsafe: true}
+	d.OnError = func(err error) { errs = append(errs, err) }
+	if err := d.Parse(); err != nil || len(errs) != 3 || d.Degraded() != errs[2] {
+		t.Fatalf("Recovered error not handed to OnError: %v %v", err, errs)
+	}
+}
@@ -0,0 +1,90 @@
+package configManager
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// What happened to a set, see Events
+type EventKind int
+
+const (
+	Registered   EventKind = iota // An option was defined
+	Parsed                        // A source was parsed, Options holds the options it set
+	Changed                       // Options changed value through Set, a reload, a schedule or an expired TTL
+	Saved                         // The configuration was written to Source
+	ReloadFailed                  // A reload was rejected, Err holds why
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case Registered:
+		return "registered"
+	case Parsed:
+		return "parsed"
+	case Changed:
+		return "changed"
+	case Saved:
+		return "saved"
+	case ReloadFailed:
+		return "reload-failed"
+	}
+	return "unknown"
+}
+
+// A step in the lifecycle of a set's configuration
+type Event struct {
+	Kind    EventKind
+	Time    time.Time
+	Options []string // Options involved in lexicographical order, empty when the event concerns the whole set
+	Source  string   // File or source read or written, or who changed the options with SetBy, empty when not applicable
+	Err     error    // Why a reload failed
+}
+
+// Number of events buffered for each subscriber
+const eventBuffer = 64
+
+type eventHub struct {
+	mu   sync.Mutex
+	subs []chan Event
+}
+
+// Returns a channel receiving every lifecycle event of the set from now on, until stop is called
+// Each call subscribes a new channel, events are dropped for a subscriber whose buffer is full
+// so a slow reader never blocks the configuration
+// stop unsubscribes the channel and closes it, events already buffered can still be read
+func (c *ConfigSet) Events() (events <-chan Event, stop func()) {
+	c = c.base()
+	ch := make(chan Event, eventBuffer)
+	c.events.mu.Lock()
+	c.events.subs = append(c.events.subs, ch)
+	c.events.mu.Unlock()
+
+	return ch, sync.OnceFunc(func() {
+		c.events.mu.Lock()
+		defer c.events.mu.Unlock()
+		c.events.subs = slices.DeleteFunc(c.events.subs, func(s chan Event) bool { return s == ch })
+		close(ch)
+	})
+}
+
+// Sends an event to every subscriber
+func (c *ConfigSet) emit(kind EventKind, source string, err error, options ...string) {
+	c.events.mu.Lock()
+	defer c.events.mu.Unlock()
+	if len(c.events.subs) == 0 {
+		return
+	}
+
+	e := Event{Kind: kind, Time: c.now(), Options: options, Source: source, Err: err}
+	for _, ch := range c.events.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Returns a channel receiving every lifecycle event of the global set from now on, until stop is called
+func Events() (events <-chan Event, stop func()) { return globalConfig.Events() }
@@ -0,0 +1,97 @@
+package configManager
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func nextEvent(t *testing.T, ch <-chan Event) Event {
+	t.Helper()
+	select {
+	case e := <-ch:
+		return e
+	case <-time.After(2 * time.Second):
+		t.Fatal("No event received")
+	}
+	return Event{}
+}
+
+func Test_events(t *testing.T) {
+	var mem memFS
+	mem.WriteFile("config.json", []byte(`{"workers":2}`), 0644)
+	c := ConfigSet{FS: &mem, Location: "config.json"}
+	events, stop := c.Events()
+	defer stop()
+
+	AddOptionToSet(&c, "workers", int64(1), Max(10))
+	AddOptionToSet(&c, "name", "")
+	c.Parse()
+	c.Set("name", "api")
+	c.Save()
+
+	want := []struct {
+		kind    EventKind
+		source  string
+		options []string
+	}{
+		{Registered, "", []string{"workers"}},
+		{Registered, "", []string{"name"}},
+		{Parsed, "config.json", []string{"workers"}},
+		{Changed, "", []string{"name"}},
+		{Saved, "config.json", nil},
+	}
+	for _, w := range want {
+		e := nextEvent(t, events)
+		if e.Kind != w.kind || e.Source != w.source || !slices.Equal(e.Options, w.options) {
+			t.Fatalf("Unexpected event %v %q %v, expected %v %q %v", e.Kind, e.Source, e.Options, w.kind, w.source, w.options)
+		}
+	}
+
+	r := Reloader{Interval: 5 * time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	started := make(chan struct{}, 4)
+	go r.Run(ctx, &c, func(*Snapshot) error {
+		started <- struct{}{}
+		return nil
+	})
+	<-started
+
+	mem.WriteFile("config.json", []byte(`{"workers":11}`), 0644)
+	if e := nextEvent(t, events); e.Kind != ReloadFailed || !errors.Is(e.Err, ErrRange) {
+		t.Fatalf("Unexpected event %v, err: %v", e.Kind, e.Err)
+	}
+	mem.WriteFile("config.json", []byte(`{"workers":3,"name":"api"}`), 0644)
+	if e := nextEvent(t, events); e.Kind != Changed || !slices.Equal(e.Options, []string{"workers"}) {
+		t.Fatalf("Unexpected event %v %v", e.Kind, e.Options)
+	}
+}
+
+func Test_eventsStop(t *testing.T) {
+	var c ConfigSet
+	events, stop := c.Events()
+	other, stopOther := c.Events()
+	defer stopOther()
+
+	AddOptionToSet(&c, "workers", int64(1))
+	stop()
+	stop()
+	c.Set("workers", "2")
+
+	if e := nextEvent(t, events); e.Kind != Registered {
+		t.Fatalf("Buffered event lost: %v", e.Kind)
+	}
+	if _, ok := <-events; ok {
+		t.Fatal("Event received after stop")
+	}
+	if n := len(c.events.subs); n != 1 {
+		t.Fatalf("Stopped subscriber kept, %d subscribers", n)
+	}
+	nextEvent(t, other)
+	if e := nextEvent(t, other); e.Kind != Changed {
+		t.Fatalf("Remaining subscriber missed an event: %v", e.Kind)
+	}
+}
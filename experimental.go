@@ -0,0 +1,98 @@
+package configManager
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Returned by Parse when experimental options are given while ExperimentalSwitch is off
+var ErrExperimental = errors.New("experimental options are disabled")
+
+// Boolean option allowing experimental options, registered by the first MarkExperimental
+const ExperimentalSwitch = "enable_experimental"
+
+// Marks the named option experimental, so unstable knobs can ship without being usable by accident
+// Parse, reloads and ParseFromData reject a file setting it unless ExperimentalSwitch is true, everything they applied is undone
+// The switch may come from any file or source read by the same Parse, Set and ParseArgs are not restricted
+// WriteUsage marks the option (experimental)
+func (c *ConfigSet) MarkExperimental(name string) error {
+	if c.root != nil {
+		return c.root.MarkExperimental(c.prefix + name)
+	}
+	o, ok := c.formal[name]
+	if !ok {
+		return fmt.Errorf("No such option: %v", name)
+	}
+	if _, ok := c.formal[ExperimentalSwitch]; !ok {
+		if _, err := AddOptionToSet(c, ExperimentalSwitch, false); err != nil {
+			return err
+		}
+		c.formal[ExperimentalSwitch].Usage = "allow options marked (experimental)"
+	}
+	o.Experimental = true
+	c.hasExperimental = true
+	return nil
+}
+
+// Fails with ErrExperimental when experimental options were set from a file while the switch is off
+func (c *ConfigSet) checkExperimental() error {
+	if !c.hasExperimental {
+		return nil
+	}
+	if on, ok := c.formal[ExperimentalSwitch].Value.Get().(bool); ok && on {
+		return nil
+	}
+
+	var names []string
+	for _, o := range c.sortOptions(c.actual) {
+		if source := c.origins[o.Name]; o.Experimental && source != "Set" && source != "command line" {
+			names = append(names, o.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s, set %s to use them", ErrExperimental, strings.Join(names, ", "), ExperimentalSwitch)
+}
+
+// Runs parse and undoes everything it did if it set experimental options while they're disabled
+// or a policy rejects the result
+func (c *ConfigSet) gated(parse func() error) error {
+	if !c.hasExperimental && len(c.policies) == 0 {
+		return parse()
+	}
+	restore := c.checkpoint()
+	// the current configuration stays visible, a snapshot built during the parse would show the candidate
+	c.snapshot()
+	var held []func()
+	c.held = &held
+	err := parse()
+	c.held = nil
+	if err == nil {
+		err = c.checkExperimental()
+		if err == nil {
+			err = c.checkPolicies()
+		}
+		if err != nil {
+			restore()
+			return err
+		}
+	}
+	for _, fn := range held {
+		fn()
+	}
+	return err
+}
+
+// Runs fn now, or once gated accepted the parse it's checking, so a rejected configuration is never published
+func (c *ConfigSet) afterGate(fn func()) {
+	if c.held != nil {
+		*c.held = append(*c.held, fn)
+		return
+	}
+	fn()
+}
+
+// Marks the named option of the global set experimental
+func MarkExperimental(name string) error { return globalConfig.MarkExperimental(name) }
@@ -0,0 +1,66 @@
+package configManager
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_experimental(t *testing.T) {
+	var c ConfigSet
+	workers, _ := AddOptionToSet(&c, "workers", int64(4))
+	turbo, _ := AddOptionToSet(&c, "turbo", false)
+	if err := c.MarkExperimental("turbo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.MarkExperimental("missing"); err == nil {
+		t.Fatal("Unknown option marked")
+	}
+
+	err := c.ParseFromData([]byte(`{"workers": 8, "turbo": true}`))
+	if !errors.Is(err, ErrExperimental) || !strings.Contains(err.Error(), "turbo") {
+		t.Fatalf("Experimental option accepted with the switch off: %v", err)
+	}
+	if *workers != 4 || *turbo {
+		t.Fatalf("Rejected parse not undone: [%v] [%v]", *workers, *turbo)
+	}
+
+	if err := c.ParseFromData([]byte(`{"workers": 8, "turbo": true, "enable_experimental": true}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *workers != 8 || !*turbo {
+		t.Fatalf("Unexpected values: [%v] [%v]", *workers, *turbo)
+	}
+
+	var b bytes.Buffer
+	c.WriteUsage(&b)
+	if !strings.Contains(b.String(), "(experimental)") || !strings.Contains(b.String(), "-enable_experimental") {
+		t.Fatalf("Experimental option not labeled:\n%s", b.String())
+	}
+}
+
+func Test_experimentalLayers(t *testing.T) {
+	var mem memFS
+	mem.WriteFile("overlay.json", []byte(`{"turbo": true}`), 0o644)
+	mem.WriteFile("app.json", []byte(`{"enable_experimental": true}`), 0o644)
+
+	var c ConfigSet
+	c.FS = &mem
+	c.Location = "app.json"
+	c.OverlayLocation = "overlay.json"
+	turbo, _ := AddOptionToSet(&c, "turbo", false)
+	c.MarkExperimental("turbo")
+
+	if err := c.Parse(); err != nil || !*turbo {
+		t.Fatalf("Switch from another file not honored: [%v] %v", *turbo, err)
+	}
+
+	mem.WriteFile("app.json", []byte(`{}`), 0o644)
+	if _, err := c.reloadFile(); !errors.Is(err, ErrExperimental) || !*turbo {
+		t.Fatalf("Reload disabling the switch accepted: [%v] %v", *turbo, err)
+	}
+	if err := c.Set("turbo", "false"); err != nil {
+		t.Fatal(err)
+	}
+}
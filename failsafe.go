@@ -0,0 +1,32 @@
+package configManager
+
+import "fmt"
+
+// Runs parse, with Failsafe set a failure undoes everything parse did and marks the set degraded
+func (c *ConfigSet) failsafe(parse func() error) error {
+	if !c.Failsafe {
+		return parse()
+	}
+
+	restore := c.checkpoint()
+	err := parse()
+	if err == nil {
+		c.degraded = nil
+		return nil
+	}
+
+	restore()
+	c.degraded = err
+	c.notify(err)
+	r := report{source: c.Location}
+	r.error("", fmt.Errorf("starting with defaults: %w", err), "")
+	c.writeReport(&r)
+	return nil
+}
+
+// Returns why the set runs on defaults after a failed Parse with Failsafe set, nil when it doesn't
+// A later successful Parse or reload clears it
+func (c *ConfigSet) Degraded() error { return c.base().degraded }
+
+// Returns why the global set runs on defaults after a failed Parse, nil when it doesn't
+func Degraded() error { return globalConfig.Degraded() }
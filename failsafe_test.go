@@ -0,0 +1,54 @@
+package configManager
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_failsafe(t *testing.T) {
+	var mem memFS
+	mem.WriteFile("app.json", []byte(`{"workers": 8, "name": `), 0o644)
+
+	var c ConfigSet
+	c.FS = &mem
+	c.Location = "app.json"
+	workers, _ := AddOptionToSet(&c, "workers", int64(2))
+	var out bytes.Buffer
+	c.SetOutput(&out)
+
+	if err := c.Parse(); err == nil {
+		t.Fatal("Corrupt file accepted")
+	}
+
+	c.Failsafe = true
+	if err := c.Parse(); err != nil {
+		t.Fatalf("Failsafe Parse failed: %v", err)
+	}
+	if c.Degraded() == nil || *workers != 2 {
+		t.Fatalf("Set not degraded to defaults: [%v] %v", *workers, c.Degraded())
+	}
+	if !strings.Contains(out.String(), "starting with defaults") {
+		t.Fatalf("Fallback not reported:\n%s", out.String())
+	}
+
+	mem.WriteFile("app.json", []byte(`{"workers": 8}`), 0o644)
+	if _, err := c.Reload(); err != nil || c.Degraded() != nil || *workers != 8 {
+		t.Fatalf("Reload didn't recover: [%v] %v %v", *workers, err, c.Degraded())
+	}
+}
+
+func Test_failsafePartial(t *testing.T) {
+	var c ConfigSet
+	c.Failsafe = true
+	c.AddSource(DataSource(`{"workers": 8}`), DataSource(`{"ratio": "x"}`))
+	workers, _ := AddOptionToSet(&c, "workers", int64(2))
+	AddOptionToSet(&c, "ratio", float64(1))
+
+	if err := c.Parse(); err != nil || c.Degraded() == nil {
+		t.Fatalf("Failsafe Parse failed: %v", err)
+	}
+	if *workers != 2 || c.View().IsSet("workers") {
+		t.Fatal("Options parsed before the failure were kept")
+	}
+}
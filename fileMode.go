@@ -0,0 +1,37 @@
+package configManager
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// =-=-= fileModeValue
+
+// Holds the permission bits of an fs.FileMode, always written and read in octal
+// "0644", "644" and "0o644" are the same mode, a number in a file is read as octal digits too
+type fileModeValue fs.FileMode
+
+func newFileModeValue(p *fs.FileMode) *fileModeValue { return (*fileModeValue)(p) }
+
+func (m *fileModeValue) Set(s string) error {
+	s = strings.TrimSpace(s)
+	digits := strings.TrimPrefix(strings.TrimPrefix(s, "0o"), "0O")
+	n, err := strconv.ParseUint(digits, 8, 32)
+	if err != nil || digits == "" {
+		return fmt.Errorf("%w: invalid file mode %q, expected octal digits such as 0644", ErrParse, s)
+	}
+	if n > uint64(fs.ModePerm) {
+		return fmt.Errorf("%w: file mode %q has bits beyond permissions", ErrRange, s)
+	}
+	*m = fileModeValue(n)
+	return nil
+}
+
+func (m fileModeValue) Get() any { return fs.FileMode(m) }
+
+func (m fileModeValue) String() string { return fmt.Sprintf("%04o", uint32(m)) }
+
+// Saved as text, a number would lose the leading zero and read as decimal elsewhere
+func (m fileModeValue) MarshalConfig() (any, error) { return m.String(), nil }
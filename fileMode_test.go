@@ -0,0 +1,63 @@
+package configManager
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func Test_fileModeVal(t *testing.T) {
+	var m fs.FileMode
+	v := newFileModeValue(&m)
+
+	for in, want := range map[string]fs.FileMode{
+		"0644":  0o644,
+		"644":   0o644,
+		"0o600": 0o600,
+		"0":     0,
+		"0777":  0o777,
+	} {
+		if err := v.Set(in); err != nil {
+			t.Fatalf("Set(%q) rejected valid mode: %v", in, err)
+		}
+		if got := v.Get().(fs.FileMode); got != want || m != want {
+			t.Fatalf("Set(%q), expected: %o received: %o", in, want, got)
+		}
+	}
+	v.Set("640")
+	if v.String() != "0640" {
+		t.Fatalf("Unexpected String: %q", v.String())
+	}
+
+	for _, in := range []string{"", "0o", "rw-r--r--", "0648", "-644"} {
+		if err := v.Set(in); !errors.Is(err, ErrParse) {
+			t.Fatalf("Set(%q) accepted invalid mode: %v", in, err)
+		}
+	}
+	if err := v.Set("4755"); !errors.Is(err, ErrRange) {
+		t.Fatalf("Mode with setuid accepted: %v", err)
+	}
+}
+
+func Test_fileModeOption(t *testing.T) {
+	var c ConfigSet
+	mode, err := AddOptionToSet(&c, "log.mode", fs.FileMode(0o600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFromData([]byte(`{"log": {"mode": 640}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *mode != 0o640 {
+		t.Fatalf("Number read as decimal: %o", *mode)
+	}
+
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"0640"`) {
+		t.Fatalf("Mode not saved in octal:\n%s", data)
+	}
+}
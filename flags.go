@@ -0,0 +1,88 @@
+package configManager
+
+import (
+	"errors"
+	"flag"
+)
+
+// Lets a flag.FlagSet set an option through its set, so constraints, transformers and snapshots apply
+type flagValue struct {
+	c *ConfigSet
+	o *Option
+}
+
+func (f flagValue) String() string {
+	if f.o == nil {
+		return ""
+	}
+	return f.o.Value.String()
+}
+
+func (f flagValue) Set(s string) error {
+	f.c.mu.Lock()
+	defer f.c.mu.Unlock()
+	if err := f.c.checkLock(f.o.Name); err != nil {
+		return err
+	}
+	if err := f.c.setValue(f.o, s); err != nil {
+		return err
+	}
+	f.c.markSet(f.o, "command line")
+	f.c.publish()
+	return nil
+}
+
+func (f flagValue) Get() any { return f.o.Value.Get() }
+
+// Boolean options may be given as just -name on the command line
+func (f flagValue) IsBoolFlag() bool {
+	_, ok := f.o.Value.Get().(bool)
+	return ok
+}
+
+// Defines a flag on f for every option of the set, named like the option
+// Setting a flag sets the option as Set would, so parse the file before the command line for flags to win
+func (c *ConfigSet) AddToFlagSet(f *flag.FlagSet) {
+	c.VisitAll(func(o *Option) {
+		f.Var(flagValue{c.base(), o}, o.Name, o.Usage)
+		f.Lookup(o.Name).DefValue = o.DefValue
+	})
+}
+
+// Returns a new flag.FlagSet with a flag for every option of the set, see AddToFlagSet
+func (c *ConfigSet) ToFlagSet(name string, errorHandling flag.ErrorHandling) *flag.FlagSet {
+	f := flag.NewFlagSet(name, errorHandling)
+	c.AddToFlagSet(f)
+	return f
+}
+
+// Adapts a flag.Value lacking Get to the Value interface
+type flagOnlyValue struct{ flag.Value }
+
+func (f flagOnlyValue) Get() any { return f.String() }
+
+// Defines an option for every flag of f, named like the flag and sharing its value
+// Flags implementing flag.Getter keep their type, others are read as strings
+// Options already defined are left alone and reported in the returned error
+func (c *ConfigSet) FromFlagSet(f *flag.FlagSet) error {
+	var errs []error
+	f.VisitAll(func(fl *flag.Flag) {
+		var v Value = flagOnlyValue{fl.Value}
+		if g, ok := fl.Value.(flag.Getter); ok {
+			v = g
+		}
+		if err := c.Var(v, fl.Name); err != nil {
+			errs = append(errs, err)
+			return
+		}
+		// the flag may have been parsed already
+		c.Lookup(fl.Name).DefValue = intern(fl.DefValue)
+	})
+	return errors.Join(errs...)
+}
+
+// Defines a flag on f for every option of the global set, e.g. AddToFlagSet(flag.CommandLine)
+func AddToFlagSet(f *flag.FlagSet) { globalConfig.AddToFlagSet(f) }
+
+// Defines an option in the global set for every flag of f
+func FromFlagSet(f *flag.FlagSet) error { return globalConfig.FromFlagSet(f) }
@@ -0,0 +1,58 @@
+package configManager
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+)
+
+func Test_toFlagSet(t *testing.T) {
+	var c ConfigSet
+	workers, _ := AddOptionToSet(&c, "workers", int64(1), Max(10))
+	debug, _ := AddOptionToSet(&c, "debug", false)
+	c.ParseFromData([]byte(`{"workers":2}`))
+
+	f := c.ToFlagSet("app", flag.ContinueOnError)
+	f.SetOutput(io.Discard)
+	if err := f.Parse([]string{"-debug", "-workers=4", "rest"}); err != nil {
+		t.Fatal(err)
+	}
+	if *workers != 4 || !*debug || f.Arg(0) != "rest" {
+		t.Fatalf("Flags not applied: [%v] [%v] %v", *workers, *debug, f.Args())
+	}
+	if v, _ := SnapshotGet[int64](c.View(), "workers"); v != 4 || !c.View().IsSet("debug") {
+		t.Fatal("Flags not published to the snapshot")
+	}
+	if f.Lookup("workers").DefValue != "1" {
+		t.Fatalf("Flag default mismatch, expected: [1] received: [%v]", f.Lookup("workers").DefValue)
+	}
+
+	if err := f.Parse([]string{"-workers=11"}); err == nil || *workers != 4 {
+		t.Fatalf("Flag outside range accepted: [%v] %v", *workers, err)
+	}
+}
+
+func Test_fromFlagSet(t *testing.T) {
+	f := flag.NewFlagSet("app", flag.ContinueOnError)
+	port := f.Int("port", 80, "")
+	timeout := f.Duration("timeout", time.Second, "")
+	f.Parse([]string{"-port=8080"})
+
+	var c ConfigSet
+	AddOptionToSet(&c, "timeout", "")
+	if err := c.FromFlagSet(f); err == nil {
+		t.Fatal("Redefined option not reported")
+	}
+	if o := c.Lookup("port"); o == nil || o.DefValue != "80" {
+		t.Fatalf("Flag not imported: %+v", o)
+	}
+
+	c.ParseFromData([]byte(`{"port":9090}`))
+	if *port != 9090 || *timeout != time.Second {
+		t.Fatalf("Imported option does not share the flag's value: [%v] [%v]", *port, *timeout)
+	}
+	if v, _ := SnapshotGet[int](c.View(), "port"); v != 9090 {
+		t.Fatalf("Snapshot mismatch, expected: [9090] received: [%v]", v)
+	}
+}
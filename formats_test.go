@@ -0,0 +1,218 @@
+package configManager
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func Test_tomlFormat(t *testing.T) {
+	toParse := `
+greeting = "hello"
+
+[server]
+port = 8080
+tls = true
+
+[server.limits]
+rate = 2.5
+`
+	c := ConfigSet{Format: TOML}
+	greeting, _ := AddOptionToSet(&c, "greeting", "")
+	port, _ := AddOptionToSet(&c, "server.port", int64(0))
+	tls, _ := AddOptionToSet(&c, "server.tls", false)
+	rate, _ := AddOptionToSet(&c, "server.limits.rate", 0.0)
+
+	if err := c.ParseFromData([]byte(toParse)); err != nil {
+		t.Fatal(err)
+	}
+	if *greeting != "hello" || *port != 8080 || !*tls || *rate != 2.5 {
+		t.Fatalf("Unexpected values: [%v] [%v] [%v] [%v]", *greeting, *port, *tls, *rate)
+	}
+
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{"[server]", "port = 8080", "[server.limits]", "rate = 2.5"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("Saved TOML missing %q:\n%s", want, out)
+		}
+	}
+
+	d := ConfigSet{Format: TOML}
+	port, _ = AddOptionToSet(&d, "server.port", int64(0))
+	if err := d.ParseFromData(data); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 8080 {
+		t.Fatalf("Round trip mismatch, expected: [8080] received: [%v]", *port)
+	}
+}
+
+func Test_iniFormat(t *testing.T) {
+	toParse := `
+; legacy settings
+name = "my app"
+
+[server]
+port = 8080
+# tls is off in dev
+tls = false
+
+[server.limits]
+rate = 2.5
+`
+	c := ConfigSet{Format: INI}
+	name, _ := AddOptionToSet(&c, "name", "")
+	port, _ := AddOptionToSet(&c, "server.port", int64(0))
+	tls, _ := AddOptionToSet(&c, "server.tls", true)
+	rate, _ := AddOptionToSet(&c, "server.limits.rate", 0.0)
+
+	if err := c.ParseFromData([]byte(toParse)); err != nil {
+		t.Fatal(err)
+	}
+	if *name != "my app" || *port != 8080 || *tls || *rate != 2.5 {
+		t.Fatalf("Unexpected values: [%v] [%v] [%v] [%v]", *name, *port, *tls, *rate)
+	}
+
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "name = my app\n\n[server]\nport = 8080\ntls = false\n\n[server.limits]\nrate = 2.5\n"
+	if string(data) != want {
+		t.Fatalf("Saved INI mismatch:\n%s", data)
+	}
+
+	if err := c.ParseFromData([]byte("[server\nport = 1")); err == nil {
+		t.Fatal("Parse accepted unterminated section")
+	}
+}
+
+func Test_iniQuotedRoundTrip(t *testing.T) {
+	values := []string{` padded `, "two\nlines", `say "hi"`, `"quoted"`, `; not a comment`, `C:\path`, `it's`}
+
+	c := ConfigSet{Format: INI}
+	opts := make([]*string, len(values))
+	for i, v := range values {
+		opts[i], _ = AddOptionToSet(&c, fmt.Sprintf("v%d", i), v)
+	}
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := ConfigSet{Format: INI}
+	for i := range values {
+		opts[i], _ = AddOptionToSet(&d, fmt.Sprintf("v%d", i), "")
+	}
+	if err := d.ParseFromData(data); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range values {
+		if *opts[i] != want {
+			t.Fatalf("Value changed by a save and load, expected: %q received: %q\n%s", want, *opts[i], data)
+		}
+	}
+}
+
+func Test_hclFormat(t *testing.T) {
+	toParse := `
+name = "my app"
+tags = ["a", "b"]
+
+server {
+  port = 8080
+
+  limits {
+    rate = 2.5
+  }
+}
+
+service "web" {
+  port = 80
+}
+`
+	c := ConfigSet{Format: HCL}
+	name, _ := AddOptionToSet(&c, "name", "")
+	port, _ := AddOptionToSet(&c, "server.port", int64(0))
+	rate, _ := AddOptionToSet(&c, "server.limits.rate", 0.0)
+	web, _ := AddOptionToSet(&c, "service.web.port", int64(0))
+
+	if err := c.ParseFromData([]byte(toParse)); err != nil {
+		t.Fatal(err)
+	}
+	if *name != "my app" || *port != 8080 || *rate != 2.5 || *web != 80 {
+		t.Fatalf("Unexpected values: [%v] [%v] [%v] [%v]", *name, *port, *rate, *web)
+	}
+
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := ConfigSet{Format: HCL}
+	web, _ = AddOptionToSet(&d, "service.web.port", int64(0))
+	rate, _ = AddOptionToSet(&d, "server.limits.rate", 0.0)
+	if err := d.ParseFromData(data); err != nil {
+		t.Fatalf("%v\n%s", err, data)
+	}
+	if *web != 80 || *rate != 2.5 {
+		t.Fatalf("Round trip mismatch: [%v] [%v]\n%s", *web, *rate, data)
+	}
+}
+
+func Test_binaryFormats(t *testing.T) {
+	for _, format := range []fileFormat{MSGPACK, CBOR} {
+		src := ConfigSet{Format: format}
+		AddOptionToSet(&src, "greeting", "hello")
+		AddOptionToSet(&src, "repeats", int64(3))
+		AddOptionToSet(&src, "ratio", 0.5)
+		AddOptionToSet(&src, "middleware", OrderedMap{})
+		src.Set("greeting", "howdy")
+		src.Set("middleware", `{"b":1,"a":2}`)
+
+		data, err := src.SaveTo()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dst := ConfigSet{Format: format}
+		greeting, _ := AddOptionToSet(&dst, "greeting", "")
+		repeats, _ := AddOptionToSet(&dst, "repeats", int64(0))
+		ratio, _ := AddOptionToSet(&dst, "ratio", 0.0)
+		if err := dst.ParseFromData(data); err != nil {
+			t.Fatal(err)
+		}
+		if *greeting != "howdy" || *repeats != 3 || *ratio != 0.5 {
+			t.Fatalf("Round trip mismatch for format %v: [%v] [%v] [%v]", format, *greeting, *repeats, *ratio)
+		}
+	}
+}
+
+func Test_jsoncFormat(t *testing.T) {
+	toParse := `{
+		// greeting shown on login
+		"greeting": "hello // not a comment", /* inline */
+		"tags": ["a", "b",],
+		"middleware": {"z": 1, "a": 2,},
+	}`
+
+	c := ConfigSet{Format: JSONC}
+	greeting, _ := AddOptionToSet(&c, "greeting", "")
+	tags, _ := StringListSet(&c, "tags", nil, ",", true)
+	middleware, _ := AddOptionToSet(&c, "middleware", OrderedMap{})
+
+	if err := c.ParseFromData([]byte(toParse)); err != nil {
+		t.Fatal(err)
+	}
+	if *greeting != "hello // not a comment" || len(*tags) != 2 || middleware.Keys()[0] != "z" {
+		t.Fatalf("Unexpected values: [%v] %v %v", *greeting, *tags, *middleware)
+	}
+
+	if err := c.ParseFromData([]byte(`{"greeting": "a" "b"}`)); err == nil {
+		t.Fatal("Parse accepted invalid JSONC")
+	}
+}
@@ -0,0 +1,113 @@
+package configManager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// A file system Save can write to
+// ConfigSet.FS may hold any fs.FS, those not implementing WritableFS are treated as read only locations
+type WritableFS interface {
+	fs.FS
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	MkdirAll(name string, perm fs.FileMode) error
+}
+
+// Reads the named file from fsys, or the operating system's file system when fsys is nil
+func readFile(fsys fs.FS, name string) ([]byte, error) {
+	if fsys == nil {
+		return os.ReadFile(name)
+	}
+	return fs.ReadFile(fsys, name)
+}
+
+// Parses the named file of fsys, e.g. a default configuration embedded with embed.FS, and sets all options
+// Like Parse, options already set keep their value: parse user files first and embedded defaults last to layer them
+//
+//	//go:embed defaults.json
+//	var defaults embed.FS
+//
+//	err := c.Parse()
+//	if err == nil {
+//		err = c.ParseFS(defaults, "defaults.json")
+//	}
+func (c *ConfigSet) ParseFS(fsys fs.FS, name string) error {
+	c = c.base()
+	c.problems = nil
+	return c.handle(c.gated(func() error {
+		return c.parseDecoded(name, false, func() (map[string]any, map[string]json.RawMessage, error) {
+			data, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				return nil, nil, err
+			}
+			return c.decode(data)
+		})
+	}))
+}
+
+// Parses the named file of fsys and sets all options of the global set
+func ParseFS(fsys fs.FS, name string) error { return globalConfig.ParseFS(fsys, name) }
+
+// Stats the named file on fsys, or the operating system's file system when fsys is nil
+func statFile(fsys fs.FS, name string) (fs.FileInfo, error) {
+	if fsys == nil {
+		return os.Stat(name)
+	}
+	return fs.Stat(fsys, name)
+}
+
+// Returns the directory of name, fs.FS paths are always slash separated
+func dirOf(fsys fs.FS, name string) string {
+	if fsys == nil {
+		return filepath.Dir(name)
+	}
+	return path.Dir(name)
+}
+
+// Writes data to loc on the set's file system, creating missing directories
+// The previous content is kept as a backup first when Backups is set
+func (c *ConfigSet) writeFile(loc string, data []byte) error { return c.writeFileFS(c.FS, loc, data) }
+
+// Writes data to loc on fsys like writeFile, for files of a FileSource
+func (c *ConfigSet) writeFileFS(fsys fs.FS, loc string, data []byte) error {
+	if err := c.rotateBackups(fsys, loc, data); err != nil {
+		return err
+	}
+	return writeFS(fsys, loc, data, c.writeMode())
+}
+
+// Writes data to loc on fsys, or the operating system's file system when fsys is nil
+func writeFS(fsys fs.FS, loc string, data []byte, mode writeMode) error {
+	if fsys == nil {
+		return writeConfig(loc, data, mode)
+	}
+	w, ok := fsys.(WritableFS)
+	if !ok {
+		return fmt.Errorf("%w: %s: file system is not writable", ErrReadOnlyLocation, loc)
+	}
+
+	err := w.MkdirAll(path.Dir(loc), mode.dir)
+	if err == nil {
+		err = w.WriteFile(loc, data, mode.file)
+	}
+	if isReadOnly(err) {
+		return fmt.Errorf("%w: %s: %v", ErrReadOnlyLocation, loc, err)
+	}
+	return err
+}
+
+// Checks loc can be written on the set's file system without modifying it
+// A WritableFS is assumed to accept every write
+func (c *ConfigSet) checkWritable(loc string) error {
+	if c.FS == nil {
+		return checkWritable(loc)
+	}
+	if _, ok := c.FS.(WritableFS); !ok {
+		return fmt.Errorf("%w: %s: file system is not writable", ErrReadOnlyLocation, loc)
+	}
+	return nil
+}
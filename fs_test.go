@@ -0,0 +1,111 @@
+package configManager
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func Test_memFS(t *testing.T) {
+	var mem memFS
+	mem.WriteFile("etc/app/config.json", []byte(`{"greeting":"howdy"}`), 0644)
+
+	c := ConfigSet{FS: &mem, Location: "etc/app/config.json", Audit: &FileAudit{Enforce: true}}
+	greeting, _ := AddOptionToSet(&c, "greeting", "hello")
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *greeting != "howdy" {
+		t.Fatalf("Option value mismatch, expected: [howdy] received: [%v]", *greeting)
+	}
+
+	c.Set("greeting", "hi")
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	var d ConfigSet
+	d.FS, d.Location = &mem, c.Location
+	saved, _ := AddOptionToSet(&d, "greeting", "")
+	if err := d.Parse(); err != nil || *saved != "hi" {
+		t.Fatalf("Saved file not parsed: [%v] %v", *saved, err)
+	}
+
+	mem.WriteFile(c.Location, []byte(`{}`), 0666)
+	d.Audit = &FileAudit{Enforce: true}
+	if err := d.Parse(); !errors.Is(err, ErrInsecureFile) {
+		t.Fatalf("Parse accepted world writable file, err: %v", err)
+	}
+}
+
+func Test_readOnlyFS(t *testing.T) {
+	c := ConfigSet{FS: fstest.MapFS{"config.json": {Data: []byte(`{"greeting":"howdy"}`)}}, Location: "config.json"}
+	AddOptionToSet(&c, "greeting", "hello")
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CanSave(); !errors.Is(err, ErrReadOnlyLocation) {
+		t.Fatalf("CanSave did not report read only file system, err: %v", err)
+	}
+	if err := c.Save(); !errors.Is(err, ErrReadOnlyLocation) {
+		t.Fatalf("Save did not report read only file system, err: %v", err)
+	}
+}
+
+func Test_reloadMemFS(t *testing.T) {
+	var mem memFS
+	mem.WriteFile("config.json", []byte(`{"workers":1}`), 0644)
+
+	c := ConfigSet{FS: &mem, Location: "config.json"}
+	AddOptionToSet(&c, "workers", int64(0))
+	c.Parse()
+
+	applied := make(chan int64, 4)
+	r := Reloader{Interval: 5 * time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx, &c, func(s *Snapshot) error {
+		n, _ := SnapshotGet[int64](s, "workers")
+		applied <- n
+		return nil
+	})
+	<-applied
+
+	mem.WriteFile("config.json", []byte(`{"workers":4}`), 0644)
+	select {
+	case n := <-applied:
+		if n != 4 {
+			t.Fatalf("Reloaded value mismatch, expected: [4] received: [%v]", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("File change not reloaded")
+	}
+}
+
+func Test_parseFS(t *testing.T) {
+	defaults := fstest.MapFS{"defaults.json": {Data: []byte(`{"greeting": "howdy", "port": 8080}`)}}
+	var mem memFS
+	mem.WriteFile("app.json", []byte(`{"greeting": "hi"}`), 0o644)
+
+	c := ConfigSet{FS: &mem, Location: "app.json"}
+	greeting, _ := AddOptionToSet(&c, "greeting", "hello")
+	port, _ := AddOptionToSet(&c, "port", int64(80))
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFS(defaults, "defaults.json"); err != nil {
+		t.Fatal(err)
+	}
+	if *greeting != "hi" || *port != 8080 {
+		t.Fatalf("Defaults not layered under the file: [%v] [%v]", *greeting, *port)
+	}
+	if origin := c.origins["port"]; origin != "defaults.json" {
+		t.Fatalf("Unexpected origin: %v", origin)
+	}
+
+	if err := c.ParseFS(defaults, "missing.json"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Missing file not reported: %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package configManager
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Writes Go source declaring a constant and a typed accessor for every option, in package pkg
+// so code refers to options through checked identifiers instead of strings, e.g. for db.port:
+//
+//	const KeyDBPort = "db.port"
+//	func (o Options) DBPort() int64
+//
+// Options wraps a Snapshot, build it with OptionsOf(c.View()), accessors return the zero value
+// when the snapshot lacks the option. Rerun the generator whenever options change, e.g. from a go:generate program
+func (c *ConfigSet) GenerateGo(w io.Writer, pkg string) error {
+	g := generator{imports: map[string]string{"github.com/quollveth/configManager": "configManager"}}
+	idents := make(map[string]string)
+
+	var body bytes.Buffer
+	for o := range c.All() {
+		id := goIdent(o.Name)
+		if other, dup := idents[id]; dup {
+			return fmt.Errorf("%s and %s both generate %s", other, o.Name, id)
+		}
+		idents[id] = o.Name
+
+		typ := "any"
+		if v := o.Value.Get(); v != nil {
+			var err error
+			if typ, err = g.typeName(reflect.TypeOf(v)); err != nil {
+				return fmt.Errorf("%s: %w", o.Name, err)
+			}
+		}
+
+		fmt.Fprintf(&body, "\n// Name of the %s option\nconst Key%s = %q\n", o.Name, id, o.Name)
+		body.WriteString("\n")
+		for _, line := range optionDoc(o) {
+			fmt.Fprintf(&body, "// %s\n", line)
+		}
+		fmt.Fprintf(&body, "func (o Options) %s() %s {\n\tv, _ := configManager.SnapshotGet[%s](o.snapshot, Key%s)\n\treturn v\n}\n", id, typ, typ, id)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by configManager.GenerateGo; DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+	for _, path := range slices.Sorted(maps.Keys(g.imports)) {
+		fmt.Fprintf(&b, "\t%q\n", path)
+	}
+	b.WriteString(")\n\n")
+	b.WriteString("// Typed view of a configuration snapshot\ntype Options struct{ snapshot *configManager.Snapshot }\n\n")
+	b.WriteString("// Wraps a snapshot, e.g. OptionsOf(c.View())\nfunc OptionsOf(s *configManager.Snapshot) Options { return Options{s} }\n")
+	b.Write(body.Bytes())
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return fmt.Errorf("Could not format generated code: %v", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// Writes Go source for every option of the global set
+func GenerateGo(w io.Writer, pkg string) error { return globalConfig.GenerateGo(w, pkg) }
+
+// Doc comment lines of an option's accessor: usage, default, constraints and deprecation
+func optionDoc(o *Option) []string {
+	lines := []string{o.Name}
+	if o.Usage != "" {
+		lines[0] += ": " + strings.ReplaceAll(o.Usage, "\n", " ")
+	}
+	lines = append(lines, fmt.Sprintf("Default %q", o.DefValue))
+	for _, con := range o.constraints {
+		lines = append(lines, "Constraint: "+con.String())
+	}
+	if o.Required {
+		lines = append(lines, "Required")
+	}
+	if o.Experimental {
+		lines = append(lines, "Experimental")
+	}
+	if o.Deprecated != "" {
+		lines = append(lines, "", "Deprecated: "+o.Deprecated)
+	}
+	return lines
+}
+
+type generator struct {
+	imports map[string]string // package path to name
+}
+
+// Returns how t is written in the generated file, adding the imports it needs
+func (g *generator) typeName(t reflect.Type) (string, error) {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name(), nil
+		}
+		pkg, _, _ := strings.Cut(t.String(), ".")
+		if name, ok := g.imports[t.PkgPath()]; ok && name != pkg {
+			return "", fmt.Errorf("package %s imported as both %s and %s", t.PkgPath(), name, pkg)
+		}
+		for path, name := range g.imports {
+			if name == pkg && path != t.PkgPath() {
+				return "", fmt.Errorf("packages %s and %s are both named %s", path, t.PkgPath(), pkg)
+			}
+		}
+		if !isIdent(t.Name()) {
+			return "", fmt.Errorf("type %v can't be named in generated code", t)
+		}
+		g.imports[t.PkgPath()] = pkg
+		return pkg + "." + t.Name(), nil
+	}
+
+	var elem string
+	var err error
+	if t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		if elem, err = g.typeName(t.Elem()); err != nil {
+			return "", err
+		}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + elem, nil
+	case reflect.Slice:
+		return "[]" + elem, nil
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), elem), nil
+	case reflect.Map:
+		key, err := g.typeName(t.Key())
+		if err != nil {
+			return "", err
+		}
+		return "map[" + key + "]" + elem, nil
+	}
+	return "", fmt.Errorf("type %v can't be named in generated code", t)
+}
+
+// Reports whether s is a valid identifier
+func isIdent(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Words written in upper case in exported identifiers, following Go naming conventions
+var initialisms = map[string]bool{
+	"api": true, "cpu": true, "db": true, "dns": true, "http": true, "https": true, "id": true, "ip": true,
+	"json": true, "sql": true, "ssh": true, "tcp": true, "tls": true, "ttl": true, "udp": true, "ui": true,
+	"uri": true, "url": true, "uuid": true, "xml": true,
+}
+
+// Turns an option name into an exported identifier, e.g. db.max_conns becomes DBMaxConns
+func goIdent(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if initialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	id := b.String()
+	if id == "" || !unicode.IsLetter([]rune(id)[0]) {
+		id = "X" + id
+	}
+	return id
+}
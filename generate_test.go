@@ -0,0 +1,69 @@
+package configManager
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_generateGo(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "db.port", int64(5432), Min(1))
+	AddOptionToSet(&c, "db.host", "localhost")
+	RegisterTypeInSet(&c, func(p *time.Duration) Value { return (*durationValue)(p) })
+	AddOptionToSet(&c, "timeout", time.Second)
+	StringListSet(&c, "tags", []string{"a"}, ",", true)
+	AddOptionToSet(&c, "headers", HeaderMap{})
+	c.Describe("db.port", "port of the database")
+	c.Lookup("db.host").Deprecated = "use db.url"
+
+	var b strings.Builder
+	if err := c.GenerateGo(&b, "config"); err != nil {
+		t.Fatal(err)
+	}
+	src := b.String()
+	if _, err := parser.ParseFile(token.NewFileSet(), "options.go", src, 0); err != nil {
+		t.Fatalf("Generated code doesn't parse: %v\n%s", err, src)
+	}
+
+	for _, want := range []string{
+		"package config",
+		`"time"`,
+		`const KeyDBPort = "db.port"`,
+		"// db.port: port of the database",
+		"// Constraint: ",
+		"// Deprecated: use db.url",
+		"func (o Options) DBPort() int64 {",
+		"func (o Options) DBHost() string {",
+		"func (o Options) Timeout() time.Duration {",
+		"func (o Options) Tags() []string {",
+		"func (o Options) Headers() configManager.HeaderMap {",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Missing %q from generated code:\n%s", want, src)
+		}
+	}
+}
+
+func Test_generateGoCollision(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "max_conns", int64(1))
+	AddOptionToSet(&c, "max.conns", int64(1))
+
+	if err := c.GenerateGo(new(strings.Builder), "config"); err == nil {
+		t.Fatal("Options with the same identifier were accepted")
+	}
+}
+
+type durationValue time.Duration
+
+func (d *durationValue) Set(s string) error {
+	v, err := time.ParseDuration(s)
+	*d = durationValue(v)
+	return err
+}
+
+func (d durationValue) Get() any       { return time.Duration(d) }
+func (d durationValue) String() string { return time.Duration(d).String() }
@@ -0,0 +1,120 @@
+package configManager
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Returned when a dependency graph contains a cycle
+var ErrCycle = errors.New("dependency cycle")
+
+// Returned when a dependency graph references a node that isn't defined
+var ErrUnknownReference = errors.New("unknown reference")
+
+// Orders the nodes of graph so every node comes after the nodes it depends on
+// graph maps each node to its dependencies, nodes with equal rank keep lexicographical order
+// Fails with ErrUnknownReference or ErrCycle naming the offending nodes
+func TopoSort(graph map[string][]string) ([]string, error) {
+	nodes := slices.Sorted(maps.Keys(graph))
+	for _, n := range nodes {
+		for _, dep := range graph[n] {
+			if _, ok := graph[dep]; !ok {
+				return nil, fmt.Errorf("%w: %s depends on %s", ErrUnknownReference, n, dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make(map[string]int, len(nodes))
+	order := make([]string, 0, len(nodes))
+	var path []string
+
+	var visit func(n string) error
+	visit = func(n string) error {
+		switch state[n] {
+		case done:
+			return nil
+		case visiting:
+			start := slices.Index(path, n)
+			return fmt.Errorf("%w: %s", ErrCycle, strings.Join(append(path[start:], n), " -> "))
+		}
+
+		state[n] = visiting
+		path = append(path, n)
+		for _, dep := range graph[n] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[n] = done
+		order = append(order, n)
+		return nil
+	}
+
+	for _, n := range nodes {
+		if err := visit(n); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Converts an option value into a dependency graph
+// Accepts map[string][]string, and OrderedMap or map[string]any whose values are lists of strings
+func asGraph(v any) (map[string][]string, error) {
+	switch g := v.(type) {
+	case map[string][]string:
+		return g, nil
+	case OrderedMap:
+		m := make(map[string]any, len(g))
+		for _, e := range g {
+			m[e.Key] = e.Value
+		}
+		return asGraph(m)
+	case map[string]any:
+		out := make(map[string][]string, len(g))
+		for k, deps := range g {
+			list, ok := deps.([]any)
+			if deps != nil && !ok {
+				return nil, fmt.Errorf("dependencies of %s must be a list, got %T", k, deps)
+			}
+			for _, d := range list {
+				s, ok := d.(string)
+				if !ok {
+					return nil, fmt.Errorf("dependency of %s must be a string, got %T", k, d)
+				}
+				out[k] = append(out[k], s)
+			}
+			if _, ok := out[k]; !ok {
+				out[k] = nil
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("cannot read %T as a dependency graph", v)
+}
+
+type acyclicConstraint struct{}
+
+// Requires the value to be a dependency graph, e.g. {"a": ["b"], "b": []}, with no cycles or unknown references
+// The option's type must be OrderedMap, map[string][]string or a registered type whose Get returns map[string]any
+func Acyclic() Constraint { return acyclicConstraint{} }
+
+func (acyclicConstraint) Check(v any) error {
+	g, err := asGraph(v)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrParse, err)
+	}
+	_, err = TopoSort(g)
+	return err
+}
+
+func (acyclicConstraint) String() string { return "acyclic" }
@@ -0,0 +1,47 @@
+package configManager
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func Test_topoSort(t *testing.T) {
+	order, err := TopoSort(map[string][]string{
+		"deploy": {"build", "test"},
+		"test":   {"build"},
+		"build":  nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"build", "test", "deploy"}; !slices.Equal(order, want) {
+		t.Fatalf("Order mismatch, expected: %v received: %v", want, order)
+	}
+
+	if _, err := TopoSort(map[string][]string{"a": {"b"}, "b": {"a"}}); !errors.Is(err, ErrCycle) {
+		t.Fatalf("Cycle not detected, err: %v", err)
+	}
+	if _, err := TopoSort(map[string][]string{"a": {"c"}}); !errors.Is(err, ErrUnknownReference) {
+		t.Fatalf("Unknown reference not detected, err: %v", err)
+	}
+}
+
+func Test_acyclicOption(t *testing.T) {
+	var c ConfigSet
+	pipeline, err := AddOptionToSet(&c, "pipeline", OrderedMap{}, Acyclic())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ParseFromData([]byte(`{"pipeline": {"a": ["b"], "b": ["c"], "c": ["a"]}}`)); !errors.Is(err, ErrCycle) {
+		t.Fatalf("Parse accepted cyclic pipeline, err: %v", err)
+	}
+	if len(*pipeline) != 0 {
+		t.Fatal("Rejected pipeline was applied")
+	}
+
+	if err := c.ParseFromData([]byte(`{"pipeline": {"a": ["b"], "b": []}}`)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,107 @@
+package configManager
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Reports whether the named option is in group, the options named group or under "group."
+func inGroup(name, group string) bool {
+	return name == group || strings.HasPrefix(name, group+NamespaceSeparator)
+}
+
+// Marshals only the options in group, e.g. SaveGroup("editor.keys") exports every keybinding
+// Names are kept whole so the data can be read back with ParseGroup or Parse
+func (c *ConfigSet) SaveGroup(group string) ([]byte, error) {
+	c = c.base()
+	group = strings.TrimSuffix(group, NamespaceSeparator)
+	return c.marshal(func(o *Option) bool { return inGroup(o.Name, group) })
+}
+
+// Applies data to the options in group, all or nothing, e.g. importing keybindings shared by someone else
+// Unlike Parse options already set are overwritten, options of the group missing from data keep their value
+// Keys outside the group are ignored and reported, as are locked options
+func (c *ConfigSet) ParseGroup(group string, data []byte) error {
+	c = c.base()
+	group = strings.TrimSuffix(group, NamespaceSeparator)
+	source := "group " + group
+
+	c.mu.Lock()
+	set, err := c.parseGroup(group, source, data)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.emit(Parsed, source, nil, set...)
+	return nil
+}
+
+func (c *ConfigSet) parseGroup(group, source string, data []byte) (set []string, err error) {
+	c.problems = nil
+	r := report{source: source}
+	defer c.writeReport(&r)
+
+	d, raw, err := c.decode(data)
+	if err != nil {
+		r.error("", err, "")
+		return nil, err
+	}
+	c.parsing = parsing{d, raw, true}
+	defer func() { c.parsing = parsing{} }()
+
+	for key := range d {
+		if !inGroup(key, group) {
+			r.warning(key, fmt.Errorf("outside of group %s, ignored", group), "")
+			delete(d, key)
+		}
+	}
+
+	restore := c.checkpoint()
+	for _, o := range c.sortOptions(c.formal) {
+		v, ok := d[o.Name]
+		if !ok {
+			continue
+		}
+		if err := c.checkLock(o.Name); err != nil {
+			r.warning(o.Name, err, "")
+			continue
+		}
+
+		vs, ok, err := c.resolve(o, v, raw[o.Name])
+		if err == nil && ok {
+			err = c.setValue(o, vs)
+		}
+		if err != nil {
+			r.error(o.Name, err, "")
+			restore()
+			return nil, optionErr(o.Name, err)
+		}
+		if ok {
+			c.markSet(o, source)
+			set = append(set, o.Name)
+		}
+	}
+	c.reportUnknown(&r, d)
+	// overrides from arguments and the environment win over the group, as they do over a reload
+	err = c.checkExperimental()
+	if err == nil {
+		err = c.applyOverrides()
+	}
+	if err == nil {
+		err = c.checkPolicies()
+	}
+	if err != nil {
+		r.error("", err, "")
+		restore()
+		return nil, err
+	}
+
+	c.publish()
+	return set, nil
+}
+
+// Marshals only the options of the global set in group
+func SaveGroup(group string) ([]byte, error) { return globalConfig.SaveGroup(group) }
+
+// Applies data to the options of the global set in group
+func ParseGroup(group string, data []byte) error { return globalConfig.ParseGroup(group, data) }
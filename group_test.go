@@ -0,0 +1,84 @@
+package configManager
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func Test_saveGroup(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "editor.keys.save", "ctrl+s")
+	AddOptionToSet(&c, "editor.keys.quit", "ctrl+q")
+	AddOptionToSet(&c, "editor.keysets", int64(1))
+	AddOptionToSet(&c, "theme", "dark")
+
+	data, err := c.SaveGroup("editor.keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved map[string]any
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := saved["editor"].(map[string]any)["keys"].(map[string]any)
+	if len(saved) != 1 || len(keys) != 2 || keys["save"] != "ctrl+s" {
+		t.Fatalf("Unexpected group export:\n%s", data)
+	}
+}
+
+func Test_parseGroup(t *testing.T) {
+	var c ConfigSet
+	save, _ := AddOptionToSet(&c, "editor.keys.save", "ctrl+s")
+	quit, _ := AddOptionToSet(&c, "editor.keys.quit", "ctrl+q")
+	width, _ := AddOptionToSet(&c, "editor.width", int64(80), Min(10))
+	theme, _ := AddOptionToSet(&c, "theme", "dark")
+	if err := c.ParseFromData([]byte(`{"editor": {"keys": {"save": "cmd+s"}}, "theme": "light"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ParseGroup("editor", []byte(`{"editor": {"keys": {"save": "F2"}, "width": 120}, "theme": "solarized"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *save != "F2" || *quit != "ctrl+q" || *width != 120 || *theme != "light" {
+		t.Fatalf("Unexpected values after import: [%v] [%v] [%v] [%v]", *save, *quit, *width, *theme)
+	}
+
+	err := c.ParseGroup("editor.", []byte(`{"editor": {"keys": {"quit": "F10"}, "width": 1}}`))
+	if err == nil {
+		t.Fatal("Rejected value accepted")
+	}
+	if *quit != "ctrl+q" || *width != 120 {
+		t.Fatalf("Failed import not rolled back: [%v] [%v]", *quit, *width)
+	}
+}
+
+func Test_parseGroupOverrides(t *testing.T) {
+	var c ConfigSet
+	width, _ := AddOptionToSet(&c, "editor.width", int64(80))
+	tabs, _ := AddOptionToSet(&c, "editor.tabs", int64(4))
+	if err := c.ParseArgs([]string{"--editor.width=100"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := range 100 {
+			c.Set("editor.tabs", strconv.Itoa(i))
+		}
+	})
+	for range 100 {
+		if err := c.ParseGroup("editor", []byte(`{"editor": {"width": 120, "tabs": 2}}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+
+	if *width != 100 || c.View().String("editor.width") != "100" {
+		t.Fatalf("Group replaced the command line override: %v", *width)
+	}
+	if err := c.ParseGroup("editor", []byte(`{"editor": {"tabs": 8}}`)); err != nil || *tabs != 8 {
+		t.Fatalf("Group not applied: %v %v", *tabs, err)
+	}
+}
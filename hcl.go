@@ -0,0 +1,162 @@
+package configManager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+)
+
+// Decodes HCL data into a *map[string]any
+// Blocks become nested objects, labels included, so service "web" { port = 80 } reads as
+// {"service": {"web": {"port": 80}}}, repeated blocks of the same name are merged
+func hclUnmarshal(data []byte, v any) error {
+	d, ok := v.(*map[string]any)
+	if !ok {
+		return fmt.Errorf("hcl: cannot decode into %T", v)
+	}
+
+	var parsed map[string]any
+	if err := hcl.Unmarshal(data, &parsed); err != nil {
+		return err
+	}
+	if *d == nil {
+		*d = make(map[string]any)
+	}
+	for k, val := range parsed {
+		(*d)[k] = hclNormalize(val)
+	}
+	return nil
+}
+
+// Collapses the lists of objects the HCL decoder produces for blocks into plain objects
+func hclNormalize(v any) any {
+	switch t := v.(type) {
+	case []map[string]any:
+		merged := make(map[string]any)
+		for _, m := range t {
+			for k, val := range m {
+				merged[k] = hclNormalize(val)
+			}
+		}
+		return merged
+	case map[string]any:
+		for k, val := range t {
+			t[k] = hclNormalize(val)
+		}
+		return t
+	case []any:
+		for i, val := range t {
+			t[i] = hclNormalize(val)
+		}
+		return t
+	}
+	return v
+}
+
+// Encodes a map[string]any as HCL, nested objects are written as blocks
+func hclMarshal(v any) ([]byte, error) {
+	d, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("hcl: cannot encode %T", v)
+	}
+	var buf bytes.Buffer
+	if err := hclWriteBody(&buf, d, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func hclWriteBody(buf *bytes.Buffer, d map[string]any, depth int) error {
+	indent := strings.Repeat("  ", depth)
+	keys := slices.Sorted(maps.Keys(d))
+
+	// attributes first, then blocks, as HCL files are usually laid out
+	for _, k := range keys {
+		if _, block := d[k].(map[string]any); block {
+			continue
+		}
+		lit, err := hclLiteral(d[k])
+		if err != nil {
+			return fmt.Errorf("hcl: %s: %w", k, err)
+		}
+		fmt.Fprintf(buf, "%s%s = %s\n", indent, hclKey(k), lit)
+	}
+
+	for _, k := range keys {
+		m, block := d[k].(map[string]any)
+		if !block {
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintf(buf, "%s%s {\n", indent, hclKey(k))
+		if err := hclWriteBody(buf, m, depth+1); err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, "%s}\n", indent)
+	}
+	return nil
+}
+
+// Quotes keys that aren't valid identifiers
+func hclKey(k string) string {
+	for i, r := range k {
+		ident := r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9'
+		if !ident {
+			return strconv.Quote(k)
+		}
+	}
+	if k == "" {
+		return `""`
+	}
+	return k
+}
+
+// Formats a value as an HCL literal, going through JSON so any marshallable type is accepted
+func hclLiteral(v any) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return strconv.Quote(t), nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(t), nil
+	case []any:
+		parts := make([]string, len(t))
+		for i, e := range t {
+			lit, err := hclLiteral(e)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = lit
+		}
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	case map[string]any:
+		parts := make([]string, 0, len(t))
+		for _, k := range slices.Sorted(maps.Keys(t)) {
+			lit, err := hclLiteral(t[k])
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, hclKey(k)+" = "+lit)
+		}
+		return "{ " + strings.Join(parts, ", ") + " }", nil
+	case nil:
+		return `""`, nil
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	var generic any
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return "", err
+	}
+	return hclLiteral(generic)
+}
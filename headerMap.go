@@ -0,0 +1,109 @@
+package configManager
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// Largest total size of the names and values of a HeaderMap, the default request header limit of most servers
+const MaxHeaderMapSize = 8 << 10
+
+// Headers a HeaderMap can't set, they're managed by the HTTP implementation or describe the connection
+var forbiddenHeaders = map[string]bool{
+	"Connection":        true,
+	"Content-Length":    true,
+	"Host":              true,
+	"Keep-Alive":        true,
+	"Proxy-Connection":  true,
+	"Te":                true,
+	"Trailer":           true,
+	"Transfer-Encoding": true,
+	"Upgrade":           true,
+}
+
+// Extra HTTP headers to add to requests or responses, written as an object {"X-Frame-Options": "DENY"}
+// Names are canonicalized, values can't hold line breaks, connection level headers such as Host or
+// Content-Length are rejected, and the whole map is limited to MaxHeaderMapSize bytes
+type HeaderMap map[string]string
+
+// Sets every header of the map on h, replacing values already there
+func (m HeaderMap) Apply(h http.Header) {
+	for k, v := range m {
+		h.Set(k, v)
+	}
+}
+
+// Checks a header name is a token as defined by RFC 9110
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > '~' || r <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// =-=-= headerMapValue
+
+type headerMapValue HeaderMap
+
+func newHeaderMapValue(p *HeaderMap) *headerMapValue { return (*headerMapValue)(p) }
+
+func (h *headerMapValue) Set(s string) error {
+	var raw map[string]string
+	if t := strings.TrimSpace(s); t != "" {
+		if err := json.Unmarshal([]byte(t), &raw); err != nil {
+			return ErrParse
+		}
+	}
+
+	m := make(HeaderMap, len(raw))
+	size := 0
+	for name, value := range raw {
+		if !validHeaderName(name) {
+			return fmt.Errorf("%w: invalid header name %q", ErrParse, name)
+		}
+		name = textproto.CanonicalMIMEHeaderKey(name)
+		if forbiddenHeaders[name] {
+			return fmt.Errorf("%w: header %s can't be configured", ErrParse, name)
+		}
+		if strings.ContainsAny(value, "\r\n\x00") {
+			return fmt.Errorf("%w: value of header %s contains a line break", ErrParse, name)
+		}
+		if _, dup := m[name]; dup {
+			return fmt.Errorf("%w: header %s given twice", ErrParse, name)
+		}
+		m[name] = strings.TrimSpace(value)
+		size += len(name) + len(value)
+	}
+	if size > MaxHeaderMapSize {
+		return fmt.Errorf("%w: headers take %d bytes, at most %d allowed", ErrRange, size, MaxHeaderMapSize)
+	}
+
+	*h = headerMapValue(m)
+	return nil
+}
+
+func (h headerMapValue) Get() any { return maps.Clone(HeaderMap(h)) }
+
+func (h headerMapValue) String() string {
+	if h == nil {
+		return "{}"
+	}
+	b, _ := json.Marshal(map[string]string(h))
+	return string(b)
+}
+
+func (h headerMapValue) MarshalConfig() (any, error) {
+	if h == nil {
+		return map[string]string{}, nil
+	}
+	return map[string]string(h), nil
+}
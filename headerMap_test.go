@@ -0,0 +1,58 @@
+package configManager
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_headerMapVal(t *testing.T) {
+	var m HeaderMap
+	v := newHeaderMapValue(&m)
+
+	if err := v.Set(`{"x-frame-options": "DENY", "Cache-control": " no-store "}`); err != nil {
+		t.Fatal(err)
+	}
+	want := HeaderMap{"X-Frame-Options": "DENY", "Cache-Control": "no-store"}
+	if !maps.Equal(v.Get().(HeaderMap), want) {
+		t.Fatalf("Unexpected headers: %v", v.Get())
+	}
+
+	for _, in := range []string{
+		`{"Host": "example.com"}`,
+		`{"content-length": "1"}`,
+		`{"X-Bad": "a\r\nSet-Cookie: x"}`,
+		`{"X Bad": "a"}`,
+		`{"x-a": "1", "X-A": "2"}`,
+		`["X-A"]`,
+	} {
+		if err := v.Set(in); !errors.Is(err, ErrParse) {
+			t.Fatalf("Set(%q) accepted invalid headers: %v", in, err)
+		}
+	}
+	if err := v.Set(`{"X-Big": "` + strings.Repeat("a", MaxHeaderMapSize) + `"}`); !errors.Is(err, ErrRange) {
+		t.Fatalf("Oversized headers accepted: %v", err)
+	}
+	if !maps.Equal(m, want) {
+		t.Fatal("Rejected value replaced the headers")
+	}
+}
+
+func Test_headerMapOption(t *testing.T) {
+	var c ConfigSet
+	headers, err := AddOptionToSet(&c, "http.headers", HeaderMap(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFromData([]byte(`{"http": {"headers": {"x-content-type-options": "nosniff"}}}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	h := http.Header{}
+	headers.Apply(h)
+	if h.Get("X-Content-Type-Options") != "nosniff" {
+		t.Fatalf("Headers not applied: %v", h)
+	}
+}
@@ -0,0 +1,117 @@
+package configManager
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// A list of host patterns such as "example.com" or "*.internal.example.com"
+// A leading "*." matches any number of subdomain labels but not the bare domain, a lone "*" matches every host
+// Matching is case insensitive and ignores a trailing dot and any port
+type HostPatternList []string
+
+// Reports whether host matches any pattern in the list
+func (l HostPatternList) Match(host string) bool {
+	host = normalizeHost(host)
+	if host == "" {
+		return false
+	}
+	for _, p := range l {
+		if matchHostPattern(p, host) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeHost(h string) string {
+	h = strings.ToLower(strings.TrimSpace(h))
+	if strings.HasPrefix(h, "[") {
+		// [ipv6]:port
+		if i := strings.Index(h, "]"); i >= 0 {
+			return h[1:i]
+		}
+	}
+	if i := strings.LastIndex(h, ":"); i >= 0 && strings.Count(h, ":") == 1 {
+		h = h[:i]
+	}
+	return strings.TrimSuffix(h, ".")
+}
+
+func matchHostPattern(pattern, host string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+	return pattern == host
+}
+
+// Checks a pattern is well formed, wildcards are only allowed as a whole leading label
+func validHostPattern(p string) error {
+	if p == "" {
+		return fmt.Errorf("%w: empty host pattern", ErrParse)
+	}
+	if p == "*" {
+		return nil
+	}
+	rest := strings.TrimPrefix(p, "*.")
+	if rest == "" || strings.Contains(rest, "*") {
+		return fmt.Errorf("%w: invalid host pattern %q", ErrParse, p)
+	}
+	for _, label := range strings.Split(rest, ".") {
+		if label == "" {
+			return fmt.Errorf("%w: invalid host pattern %q", ErrParse, p)
+		}
+	}
+	return nil
+}
+
+// =-=-= hostPatternListValue
+
+type hostPatternListValue HostPatternList
+
+func newHostPatternListValue(p *HostPatternList) *hostPatternListValue {
+	return (*hostPatternListValue)(p)
+}
+
+// Accepts a JSON array or a comma separated string
+func (h *hostPatternListValue) Set(s string) error {
+	var raw []string
+	if t := strings.TrimSpace(s); strings.HasPrefix(t, "[") {
+		if err := json.Unmarshal([]byte(t), &raw); err != nil {
+			return ErrParse
+		}
+	} else if t != "" {
+		raw = strings.Split(t, ",")
+	}
+
+	list := make(HostPatternList, 0, len(raw))
+	for _, p := range raw {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p != "*." {
+			p = strings.TrimSuffix(p, ".")
+		}
+		if err := validHostPattern(p); err != nil {
+			return err
+		}
+		list = append(list, p)
+	}
+
+	*h = hostPatternListValue(list)
+	return nil
+}
+
+func (h hostPatternListValue) Get() any { return slices.Clone(HostPatternList(h)) }
+
+func (h hostPatternListValue) String() string { return strings.Join(h, ",") }
+
+func (h hostPatternListValue) MarshalConfig() (any, error) {
+	if h == nil {
+		return []string{}, nil
+	}
+	return []string(h), nil
+}
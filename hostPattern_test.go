@@ -0,0 +1,46 @@
+package configManager
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_hostPatternMatch(t *testing.T) {
+	l := HostPatternList{"example.com", "*.internal.example.com"}
+
+	cases := map[string]bool{
+		"example.com":               true,
+		"EXAMPLE.com.":              true,
+		"example.com:8080":          true,
+		"api.internal.example.com":  true,
+		"a.b.internal.example.com":  true,
+		"internal.example.com":      false,
+		"evil-internal.example.com": false,
+		"www.example.com":           false,
+		"example.com.evil.org":      false,
+		"":                          false,
+	}
+	for host, want := range cases {
+		if got := l.Match(host); got != want {
+			t.Errorf("Match(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func Test_hostPatternOption(t *testing.T) {
+	var c ConfigSet
+	allow, _ := AddOptionToSet(&c, "cors.allow", HostPatternList{"localhost"})
+
+	if err := c.ParseFromData([]byte(`{"cors.allow": ["*.example.com", "Example.org"]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if !allow.Match("app.example.com") || !allow.Match("example.org") || allow.Match("localhost") {
+		t.Fatalf("Unexpected patterns: %v", *allow)
+	}
+
+	for _, bad := range []string{"a.*.com", "*.", "foo..com", "*foo.com"} {
+		if err := c.Set("cors.allow", bad); !errors.Is(err, ErrParse) {
+			t.Errorf("Set(%q) accepted invalid pattern, err: %v", bad, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package configManager
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Checks s is a host, a host:port pair or a :port, as accepted by net.Listen and net.Dial
+// IPv6 hosts are written in brackets when followed by a port, e.g. "[::1]:8080"
+func validHostPort(s string, requirePort bool) error {
+	host, port, err := net.SplitHostPort(s)
+	if err == nil && port == "" {
+		return fmt.Errorf("%w: missing port after colon in %q", ErrParse, s)
+	}
+	if err != nil {
+		if requirePort || s == "" {
+			return fmt.Errorf("%w: %v", ErrParse, err)
+		}
+		// a host alone, bracketed or not when it's an IPv6 address
+		host, port = s, ""
+		if inner, ok := strings.CutPrefix(s, "["); ok {
+			host, ok = strings.CutSuffix(inner, "]")
+			if !ok || net.ParseIP(host) == nil {
+				return fmt.Errorf("%w: invalid host %q", ErrParse, s)
+			}
+		} else if strings.Contains(s, ":") && net.ParseIP(s) == nil {
+			return fmt.Errorf("%w: %v", ErrParse, err)
+		}
+	}
+	if port != "" {
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return fmt.Errorf("%w: invalid port %q", ErrParse, port)
+		}
+	} else if requirePort {
+		return fmt.Errorf("%w: missing port in %q", ErrParse, s)
+	}
+	if strings.ContainsFunc(host, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune(".-_:%", r))
+	}) {
+		return fmt.Errorf("%w: invalid host %q", ErrParse, host)
+	}
+	return nil
+}
+
+// =-=-= hostPortValue
+
+type hostPortValue struct {
+	ptr         *string
+	val         string
+	requirePort bool
+}
+
+func newHostPortValue(p *string, requirePort bool) *hostPortValue {
+	return &hostPortValue{
+		ptr:         p,
+		requirePort: requirePort,
+	}
+}
+
+func (h *hostPortValue) Set(s string) error {
+	s = strings.TrimSpace(s)
+	if err := validHostPort(s, h.requirePort); err != nil {
+		return err
+	}
+	h.val = s
+	*h.ptr = s
+	return nil
+}
+
+func (h hostPortValue) Get() any { return h.val }
+
+func (h hostPortValue) String() string { return h.val }
+
+// Defines a new address option on the set c such as ":8080", "0.0.0.0:9090" or "[::1]:443", invalid addresses result in ErrParse
+// With requirePort a host alone is rejected, otherwise "localhost" is as valid as "localhost:80"
+func HostPortVarSet(c *ConfigSet, p *string, key, defaultValue string, requirePort bool) error {
+	v := newHostPortValue(p, requirePort)
+	err := v.Set(defaultValue)
+	if err != nil {
+		return err
+	}
+	return c.Var(v, key)
+}
+
+// Defines a new address option on the set c, with requirePort a host alone is rejected
+func HostPortSet(c *ConfigSet, key, defaultValue string, requirePort bool) (*string, error) {
+	p := new(string)
+	err := HostPortVarSet(c, p, key, defaultValue, requirePort)
+	return p, err
+}
+
+// Defines a new address option on the global set, with requirePort a host alone is rejected
+func HostPortVar(p *string, key, defaultValue string, requirePort bool) error {
+	return HostPortVarSet(&globalConfig, p, key, defaultValue, requirePort)
+}
+
+// Defines a new address option on the global set, with requirePort a host alone is rejected
+func HostPort(key, defaultValue string, requirePort bool) (*string, error) {
+	return HostPortSet(&globalConfig, key, defaultValue, requirePort)
+}
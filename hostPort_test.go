@@ -0,0 +1,75 @@
+package configManager
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_hostPortVal(t *testing.T) {
+	var s string
+
+	v := newHostPortValue(&s, true)
+	if err := valueTester(
+		v,
+		[]string{
+			":8080",
+			"0.0.0.0:9090",
+			"localhost:80",
+			"[::1]:443",
+			"db.internal:5432",
+		},
+		[]string{
+			"",
+			"localhost",
+			"::1",
+			"host:http",
+			"host:70000",
+			"ho st:80",
+			"[::1]",
+		},
+		&s,
+		func(a string, b string) bool { return a == b },
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	v = newHostPortValue(&s, false)
+	if err := valueTester(
+		v,
+		[]string{
+			"localhost",
+			"::1",
+			"[::1]",
+			"10.0.0.1:22",
+		},
+		[]string{
+			"",
+			"host:",
+			"a:b:c",
+			"[nope]",
+		},
+		&s,
+		func(a string, b string) bool { return a == b },
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_hostPortOption(t *testing.T) {
+	var c ConfigSet
+
+	if _, err := HostPortSet(&c, "bad", "localhost", true); !errors.Is(err, ErrParse) {
+		t.Fatalf("Option accepted a default without port: %v", err)
+	}
+
+	listen, err := HostPortSet(&c, "server.listen", ":8080", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFromData([]byte(`{"server": {"listen": "0.0.0.0:9090"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *listen != "0.0.0.0:9090" {
+		t.Fatalf("Option set to unexpected value, expected [0.0.0.0:9090] got %v", *listen)
+	}
+}
@@ -0,0 +1,109 @@
+package configManager
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Decodes INI data into a *map[string]any
+// [section] headers prefix the keys below them, so port under [server] becomes "server.port"
+// Lines starting with ; or # are comments, values may be wrapped in double or single quotes
+// Double quoted values are read as Go string literals when they're valid ones, so escapes such as \n work
+func iniUnmarshal(data []byte, v any) error {
+	d, ok := v.(*map[string]any)
+	if !ok {
+		return fmt.Errorf("ini: cannot decode into %T", v)
+	}
+	if *d == nil {
+		*d = make(map[string]any)
+	}
+
+	section := ""
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return fmt.Errorf("ini: line %d: unterminated section header", n)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		key, val, found := strings.Cut(line, "=")
+		if !found {
+			return fmt.Errorf("ini: line %d: expected key = value", n)
+		}
+		key = strings.TrimSpace(key)
+		if section != "" {
+			key = section + NamespaceSeparator + key
+		}
+
+		val = strings.TrimSpace(val)
+		// double quoted values may hold escapes, as Save writes them
+		if unquoted, err := strconv.Unquote(val); err == nil && strings.HasPrefix(val, `"`) {
+			val = unquoted
+		} else {
+			val, _ = StripQuotes("", val)
+		}
+		(*d)[key] = val
+	}
+	return sc.Err()
+}
+
+// Encodes a map[string]any as INI
+// Dotted keys are grouped in sections named after everything before the last separator
+func iniMarshal(v any) ([]byte, error) {
+	d, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("ini: cannot encode %T", v)
+	}
+
+	sections := make(map[string][]string)
+	for key := range d {
+		section, name := "", key
+		if i := strings.LastIndex(key, NamespaceSeparator); i >= 0 {
+			section, name = key[:i], key[i+1:]
+		}
+		sections[section] = append(sections[section], name)
+	}
+
+	var buf bytes.Buffer
+	for _, section := range slices.Sorted(maps.Keys(sections)) {
+		if section != "" {
+			if buf.Len() > 0 {
+				buf.WriteByte('\n')
+			}
+			fmt.Fprintf(&buf, "[%s]\n", section)
+		}
+
+		names := sections[section]
+		slices.Sort(names)
+		for _, name := range names {
+			key := name
+			if section != "" {
+				key = section + NamespaceSeparator + name
+			}
+			fmt.Fprintf(&buf, "%s = %s\n", name, iniValue(d[key]))
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func iniValue(v any) string {
+	s := stringify(v, nil)
+	// quote values that would otherwise lose surrounding space, span lines, lose their quotes or read as a comment
+	if s != strings.TrimSpace(s) || strings.ContainsAny(s, "\r\n") || s != "" && strings.ContainsRune(`;#"'`, rune(s[0])) {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,104 @@
+package configManager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Returned by Interpolate when a reference names neither an option nor an environment variable
+var ErrUndefinedReference = errors.New("undefined reference")
+
+// Data being parsed, lets Interpolate see values of options that haven't been set yet
+type parsing struct {
+	d         map[string]any
+	raw       map[string]json.RawMessage
+	overwrite bool // set options take their value from d as well, as in a reload
+}
+
+// Transformer expanding ${name} references in string options, add it with c.AddTransformer(c.Interpolate)
+// name is looked up as an option first, using the value it gets from the data being parsed, then as an environment variable
+// $$ stands for a literal dollar, so $${name} is kept as ${name}
+// LazyString options are left alone, they resolve their references when read
+func (c *ConfigSet) Interpolate(name, value string) (string, error) {
+	c = c.base()
+	o, ok := c.formal[name]
+	if !ok || !isPlainString(o) {
+		return value, nil
+	}
+	return c.interpolate(value, map[string]bool{name: true})
+}
+
+func isPlainString(o *Option) bool {
+	if _, lazy := o.Value.(*lazyStringValue); lazy {
+		return false
+	}
+	_, ok := o.Value.Get().(string)
+	return ok
+}
+
+// Expands references in s, seen holds the options being expanded to catch cycles
+func (c *ConfigSet) interpolate(s string, seen map[string]bool) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				b.WriteString(s[i:])
+				return b.String(), nil
+			}
+			ref := s[i+2 : i+2+end]
+			v, err := c.lookupReference(ref, seen)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			i += 2 + end
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String(), nil
+}
+
+// Returns the value ref stands for, see Interpolate
+func (c *ConfigSet) lookupReference(ref string, seen map[string]bool) (string, error) {
+	o, ok := c.formal[ref]
+	if !ok {
+		if v, ok := os.LookupEnv(ref); ok {
+			return v, nil
+		}
+		return "", fmt.Errorf("%w: ${%s}", ErrUndefinedReference, ref)
+	}
+	if seen[ref] {
+		return "", fmt.Errorf("%w: ${%s} refers to itself", ErrUndefinedReference, ref)
+	}
+
+	// options set from a previous source keep their value, otherwise the data being parsed will set them
+	v, pending := c.parsing.d[ref]
+	if _, set := c.actual[ref]; !pending || set && !c.parsing.overwrite {
+		return o.Value.String(), nil
+	}
+	s := stringify(v, c.parsing.raw[ref])
+	if !isPlainString(o) {
+		return s, nil
+	}
+
+	seen[ref] = true
+	defer delete(seen, ref)
+	return c.interpolate(s, seen)
+}
@@ -0,0 +1,54 @@
+package configManager
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_interpolate(t *testing.T) {
+	t.Setenv("CM_TEST_USER", "admin")
+
+	var c ConfigSet
+	c.AddTransformer(c.Interpolate)
+	dsn, _ := AddOptionToSet(&c, "a.dsn", "")
+	host, _ := AddOptionToSet(&c, "db.host", "localhost")
+	port, _ := AddOptionToSet(&c, "db.port", int64(5432))
+	price, _ := AddOptionToSet(&c, "price", "")
+	lazy, _ := LazyStringSet(&c, "lazy", "")
+
+	err := c.ParseFromData([]byte(`{
+		"a.dsn": "postgres://${CM_TEST_USER}@${db.host}:${db.port}",
+		"db.host": "db.${CM_TEST_USER}.internal",
+		"price": "$$5 or $${literal}",
+		"lazy": "${db.host}"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *dsn != "postgres://admin@db.admin.internal:5432" || *host != "db.admin.internal" || *port != 5432 {
+		t.Fatalf("References not expanded: [%v] [%v]", *dsn, *host)
+	}
+	if *price != "$5 or ${literal}" {
+		t.Fatalf("Escaped dollars not kept: [%v]", *price)
+	}
+	if lazy.Template() != "${db.host}" {
+		t.Fatalf("LazyString expanded at parse time: [%v]", lazy.Template())
+	}
+
+	if err := c.Set("price", "${nope}"); !errors.Is(err, ErrUndefinedReference) {
+		t.Fatalf("Undefined reference accepted, err: %v", err)
+	}
+	if err := c.Set("price", "${price}"); !errors.Is(err, ErrUndefinedReference) {
+		t.Fatalf("Self reference accepted, err: %v", err)
+	}
+	if err := c.Set("price", "${db.host}/x"); err != nil || *price != "db.admin.internal/x" {
+		t.Fatalf("Reference to set option not expanded: [%v] %v", *price, err)
+	}
+
+	if _, err := c.reloadData([]byte(`{"a.dsn":"${db.host}","db.host":"replica"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *dsn != "replica" {
+		t.Fatalf("Reference not expanded from reloaded data: [%v]", *dsn)
+	}
+}
@@ -0,0 +1,50 @@
+package configManager
+
+import (
+	"iter"
+	"strings"
+)
+
+// Iterates over all options in lexicographical order, for o := range c.All() is VisitAll as a loop
+// Options must not be defined while iterating
+func (c *ConfigSet) All() iter.Seq[*Option] {
+	if c.root != nil {
+		return c.root.ByPrefix(c.prefix)
+	}
+	return c.options(c.formal, "")
+}
+
+// Iterates over set options in lexicographical order, like Visit
+func (c *ConfigSet) SetOptions() iter.Seq[*Option] {
+	if c.root != nil {
+		return c.root.options(c.root.actual, c.prefix)
+	}
+	return c.options(c.actual, "")
+}
+
+// Iterates over the options whose name starts with prefix in lexicographical order, e.g. ByPrefix("db.")
+func (c *ConfigSet) ByPrefix(prefix string) iter.Seq[*Option] {
+	if c.root != nil {
+		return c.root.ByPrefix(c.prefix + prefix)
+	}
+	return c.options(c.formal, prefix)
+}
+
+func (c *ConfigSet) options(opts map[string]*Option, prefix string) iter.Seq[*Option] {
+	return func(yield func(*Option) bool) {
+		for _, o := range c.sortOptions(opts) {
+			if strings.HasPrefix(o.Name, prefix) && !yield(o) {
+				return
+			}
+		}
+	}
+}
+
+// Iterates over all options of the global set in lexicographical order
+func All() iter.Seq[*Option] { return globalConfig.All() }
+
+// Iterates over set options of the global set in lexicographical order
+func SetOptions() iter.Seq[*Option] { return globalConfig.SetOptions() }
+
+// Iterates over the options of the global set whose name starts with prefix
+func ByPrefix(prefix string) iter.Seq[*Option] { return globalConfig.ByPrefix(prefix) }
@@ -0,0 +1,48 @@
+package configManager
+
+import (
+	"slices"
+	"testing"
+)
+
+func names(seq func(func(*Option) bool)) []string {
+	var n []string
+	for o := range seq {
+		n = append(n, o.Name)
+	}
+	return n
+}
+
+func Test_iterators(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "db.host", "localhost")
+	AddOptionToSet(&c, "db.port", int64(5432))
+	AddOptionToSet(&c, "dbx", "")
+	AddOptionToSet(&c, "debug", false)
+	c.Set("db.port", "6543")
+	c.Set("debug", "true")
+
+	if n := names(c.All()); !slices.Equal(n, []string{"db.host", "db.port", "dbx", "debug"}) {
+		t.Fatalf("Unexpected All: %v", n)
+	}
+	if n := names(c.SetOptions()); !slices.Equal(n, []string{"db.port", "debug"}) {
+		t.Fatalf("Unexpected SetOptions: %v", n)
+	}
+	if n := names(c.ByPrefix("db.")); !slices.Equal(n, []string{"db.host", "db.port"}) {
+		t.Fatalf("Unexpected ByPrefix: %v", n)
+	}
+	if n := names(c.Sub("db").SetOptions()); !slices.Equal(n, []string{"db.port"}) {
+		t.Fatalf("Unexpected SetOptions of Sub: %v", n)
+	}
+
+	count := 0
+	for range c.All() {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Fatal("Iteration didn't stop")
+	}
+}
@@ -0,0 +1,63 @@
+package configManager
+
+import "encoding/json"
+
+// Turns JSON with comments and trailing commas into plain JSON
+// Comments and trailing commas are replaced by spaces rather than removed so offsets in
+// decoder errors still point at the right place in the original file
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, len(data))
+	copy(out, data)
+
+	inString := false
+	lastComma := -1 // offset of a comma that may turn out to be trailing
+	for i := 0; i < len(out); i++ {
+		ch := out[i]
+
+		if inString {
+			switch ch {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case ch == '"':
+			inString = true
+			lastComma = -1
+		case ch == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case ch == '/' && i+1 < len(out) && out[i+1] == '*':
+			out[i], out[i+1] = ' ', ' '
+			for i += 2; i < len(out); i++ {
+				if out[i] == '*' && i+1 < len(out) && out[i+1] == '/' {
+					out[i], out[i+1] = ' ', ' '
+					i++
+					break
+				}
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+		case ch == ',':
+			lastComma = i
+		case ch == '}' || ch == ']':
+			if lastComma >= 0 {
+				out[lastComma] = ' '
+			}
+			lastComma = -1
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+		default:
+			lastComma = -1
+		}
+	}
+	return out
+}
+
+// Decodes JSON that may contain // and /* */ comments and trailing commas
+func jsoncUnmarshal(data []byte, v any) error { return json.Unmarshal(stripJSONC(data), v) }
@@ -0,0 +1,44 @@
+package configManager
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Guards against stale drafts landing next to the package, such as a second package clause
+// or a file whose name only differs by case, which breaks builds on case insensitive file systems
+func Test_packageLayout(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]string)
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if other, ok := seen[strings.ToLower(name)]; ok {
+			t.Errorf("%s and %s only differ by case", name, other)
+		}
+		seen[strings.ToLower(name)] = name
+
+		src, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := parser.ParseFile(fset, name, src, parser.PackageClauseOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "configManager"
+		if strings.HasSuffix(name, "_test.go") && f.Name.Name == want+"_test" {
+			want += "_test"
+		}
+		if f.Name.Name != want {
+			t.Errorf("%s declares package %s, expected %s", name, f.Name.Name, want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package configManager
+
+import "strings"
+
+// A string option holding a template whose ${key} references to other options are resolved every time it's read
+// Derived strings such as "http://${server.host}:${server.port}/api" stay correct after reloads of their inputs
+// References to unknown options or cycles are left in the result as written
+type LazyString struct {
+	c        *ConfigSet
+	template string
+}
+
+// Returns the template as written in the configuration
+func (l *LazyString) Template() string { return l.template }
+
+// Returns the template with every reference replaced by the current value of the option it names
+func (l *LazyString) Resolve() string {
+	if l.c == nil {
+		return l.template
+	}
+	return l.c.resolveTemplate(l.template, map[*LazyString]bool{l: true})
+}
+
+// Returns the resolved value, see Resolve
+func (l *LazyString) String() string { return l.Resolve() }
+
+// Replaces ${key} references in s, seen holds the templates being resolved to break cycles
+func (c *ConfigSet) resolveTemplate(s string, seen map[*LazyString]bool) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		b.WriteString(s[:start])
+		ref, ok := c.resolveRef(s[start+2:end], seen)
+		if !ok {
+			ref = s[start : end+1]
+		}
+		b.WriteString(ref)
+		s = s[end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// Returns the current value of the named option as a string, resolving it first if it's a LazyString
+func (c *ConfigSet) resolveRef(name string, seen map[*LazyString]bool) (string, bool) {
+	o, ok := c.formal[name]
+	if !ok {
+		return "", false
+	}
+	lv, lazy := o.Value.(*lazyStringValue)
+	if !lazy {
+		return o.Value.String(), true
+	}
+	if seen[lv.ptr] {
+		return "", false
+	}
+	seen[lv.ptr] = true
+	defer delete(seen, lv.ptr)
+	return c.resolveTemplate(lv.ptr.template, seen), true
+}
+
+// =-=-= lazyStringValue
+
+type lazyStringValue struct{ ptr *LazyString }
+
+func newLazyStringValue(p *LazyString) *lazyStringValue { return &lazyStringValue{p} }
+
+func (l *lazyStringValue) Set(s string) error {
+	l.ptr.template = s
+	return nil
+}
+
+// Returns the resolved string
+func (l lazyStringValue) Get() any {
+	if l.ptr == nil {
+		return ""
+	}
+	return l.ptr.Resolve()
+}
+
+// Returns the template so it survives being set again
+func (l lazyStringValue) String() string {
+	if l.ptr == nil {
+		return ""
+	}
+	return l.ptr.template
+}
+
+// Templates are saved unresolved
+func (l lazyStringValue) MarshalConfig() (any, error) { return l.String(), nil }
+
+// Defines a new template option on the set c, see LazyString
+// Constraints are checked against the resolved value
+func LazyStringVarSet(c *ConfigSet, p *LazyString, key, defaultValue string, constraints ...Constraint) error {
+	*p = LazyString{c: c.base(), template: defaultValue}
+	return c.Var(newLazyStringValue(p), key, constraints...)
+}
+
+// Defines a new template option on the set c, see LazyString
+// Constraints are checked against the resolved value
+func LazyStringSet(c *ConfigSet, key, defaultValue string, constraints ...Constraint) (*LazyString, error) {
+	p := new(LazyString)
+	err := LazyStringVarSet(c, p, key, defaultValue, constraints...)
+	return p, err
+}
+
+// Defines a new template option, see LazyString
+func LazyStringVar(p *LazyString, key, defaultValue string, constraints ...Constraint) error {
+	return LazyStringVarSet(&globalConfig, p, key, defaultValue, constraints...)
+}
+
+// Defines a new template option, see LazyString
+func LazyStringOption(key, defaultValue string, constraints ...Constraint) (*LazyString, error) {
+	return LazyStringSet(&globalConfig, key, defaultValue, constraints...)
+}
@@ -0,0 +1,39 @@
+package configManager
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_lazyString(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "server.host", "localhost")
+	AddOptionToSet(&c, "server.port", int64(80))
+	base, _ := LazyStringSet(&c, "base", "http://${server.host}:${server.port}")
+	api, _ := LazyStringSet(&c, "api", "${base}/api?missing=${nope}")
+	loop, _ := LazyStringSet(&c, "loop", "x${loop}")
+
+	if got := api.Resolve(); got != "http://localhost:80/api?missing=${nope}" {
+		t.Fatalf("Unexpected resolved value: %v", got)
+	}
+	if got := loop.Resolve(); got != "x${loop}" {
+		t.Fatalf("Cycle not left unresolved: %v", got)
+	}
+
+	if _, err := c.reloadData([]byte(`{"server.host":"example.com","server.port":8080}`)); err != nil {
+		t.Fatal(err)
+	}
+	if got := base.Resolve(); got != "http://example.com:8080" {
+		t.Fatalf("Reference not resolved against reloaded value: %v", got)
+	}
+	if v, _ := SnapshotGet[string](c.View(), "api"); v != "http://example.com:8080/api?missing=${nope}" {
+		t.Fatalf("Snapshot holds unresolved value: %v", v)
+	}
+
+	data, _ := c.SaveTo()
+	var saved map[string]any
+	json.Unmarshal(data, &saved)
+	if saved["base"] != "http://${server.host}:${server.port}" {
+		t.Fatalf("Template not saved unresolved: %v", saved["base"])
+	}
+}
@@ -0,0 +1,69 @@
+package configManager
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Adds paths searched by Parse when Location is empty, after the ones already added
+func (c *ConfigSet) AddLocation(paths ...string) {
+	c = c.base()
+	c.Locations = append(c.Locations, paths...)
+}
+
+// Returns the first of Locations that exists, a leading ~/ is replaced by the user's home directory
+// The error wraps fs.ErrNotExist when none of them exist
+func (c *ConfigSet) FindLocation() (string, error) {
+	c = c.base()
+	for _, loc := range c.Locations {
+		loc = c.expandHome(loc)
+		_, err := statFile(c.FS, loc)
+		if err == nil {
+			return loc, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no configuration file found in %s: %w", strings.Join(c.Locations, ", "), fs.ErrNotExist)
+}
+
+// Replaces a leading ~/ by the user's home directory, paths on FS are left alone
+func (c *ConfigSet) expandHome(loc string) string {
+	rest, ok := strings.CutPrefix(loc, "~/")
+	if !ok || c.FS != nil {
+		return loc
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return loc
+	}
+	return filepath.Join(home, rest)
+}
+
+// Sets Location from Locations when it's empty
+// With save set and no existing file the first of Locations is used, so Save can create it
+func (c *ConfigSet) searchLocations(save bool) error {
+	if c.Location != "" || len(c.Locations) == 0 {
+		return nil
+	}
+	loc, err := c.FindLocation()
+	switch {
+	case err == nil:
+		c.Location = loc
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	case save:
+		c.Location = c.expandHome(c.Locations[0])
+	case len(c.Sources) == 0:
+		return err
+	}
+	return nil
+}
+
+// Adds paths searched by Parse of the global set when no location is set
+func AddLocation(paths ...string) { globalConfig.AddLocation(paths...) }
@@ -0,0 +1,35 @@
+package configManager
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func Test_locations(t *testing.T) {
+	var mem memFS
+	mem.WriteFile("home/app.json", []byte(`{"greeting":"home"}`), 0644)
+	mem.WriteFile("etc/app.json", []byte(`{"greeting":"etc"}`), 0644)
+
+	c := ConfigSet{FS: &mem}
+	c.AddLocation("app.json", "home/app.json", "etc/app.json")
+	greeting, _ := AddOptionToSet(&c, "greeting", "")
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *greeting != "home" || c.Location != "home/app.json" {
+		t.Fatalf("First existing location not used: [%v] [%v]", *greeting, c.Location)
+	}
+
+	d := ConfigSet{FS: &mem, Locations: []string{"app.json", "other.json"}}
+	AddOptionToSet(&d, "greeting", "hello")
+	if err := d.Parse(); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Missing locations not reported, err: %v", err)
+	}
+	if err := d.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Stat(&mem, "app.json"); err != nil || d.Location != "app.json" {
+		t.Fatalf("Save did not create the first location: [%v] %v", d.Location, err)
+	}
+}
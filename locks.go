@@ -0,0 +1,144 @@
+package configManager
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Returned when changing an option a source has locked
+var ErrLocked = errors.New("option is locked")
+
+// Top level key a source uses to lock options, its value is a list of option names
+//
+//	{"telemetry": false, "update.channel": "stable", "locked": ["telemetry", "update.channel"]}
+//
+// A locked option keeps the value of the source that locked it, OverlayLocation, Tiers, Location,
+// other sources, ParseArgs and Set can't override it, meant for policies pushed by a control plane
+// Only Sources that aren't files can lock options, the key is ignored in files and ParseFromData
+// The first source locking an option wins, an option listed without a value in the same source isn't locked
+// If an option is registered with this name it's treated as a regular option instead
+const LockedKey = "locked"
+
+// Removes the locked list from d, returning the option names in it
+func (c *ConfigSet) takeLocks(d map[string]any) ([]string, error) {
+	raw, ok := d[LockedKey]
+	if !ok {
+		return nil, nil
+	}
+	if _, registered := c.formal[LockedKey]; registered {
+		return nil, nil
+	}
+	delete(d, LockedKey)
+
+	switch l := raw.(type) {
+	case string:
+		return []string{l}, nil
+	case []any:
+		names := make([]string, 0, len(l))
+		for _, n := range l {
+			s, ok := n.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a list of option names, got %T", LockedKey, n)
+			}
+			names = append(names, s)
+		}
+		return names, nil
+	}
+	return nil, fmt.Errorf("%s must be a list of option names, got %T", LockedKey, raw)
+}
+
+// Records the options source locks, those already locked by another source are left alone
+func (c *ConfigSet) lock(source string, names []string, d map[string]any, r *report) {
+	for _, name := range names {
+		if _, ok := c.formal[name]; !ok {
+			r.warning(name, fmt.Errorf("cannot lock unknown option"), "")
+			continue
+		}
+		if _, ok := d[name]; !ok {
+			r.warning(name, fmt.Errorf("locked without a value"), "")
+			continue
+		}
+		if _, ok := c.locks[name]; ok {
+			continue
+		}
+		if c.locks == nil {
+			c.locks = make(map[string]string)
+		}
+		c.locks[name] = source
+	}
+}
+
+// Fails with ErrLocked when the named option is locked
+func (c *ConfigSet) checkLock(name string) error {
+	if source, ok := c.locks[name]; ok {
+		return fmt.Errorf("%w: %v by %s", ErrLocked, name, source)
+	}
+	return nil
+}
+
+// Returns the source that locked the named option and whether it's locked
+func (c *ConfigSet) LockedBy(name string) (string, bool) {
+	c, name = c.owner(name)
+	source, ok := c.locks[name]
+	return source, ok
+}
+
+// Describes where an option's value comes from, see Explain
+type Explanation struct {
+	Name     string
+	Value    string
+	Source   string // "default", "Set", "command line", the file or source that set it, or who set it with SetBy
+	LockedBy string // Source that locked the option, empty when it isn't locked
+
+	ModifiedAt time.Time // When the option was last set, zero when it holds its default
+	Stale      bool      // The value was set longer than its TTL ago, see SetTTL
+
+	// Values every source read by the last Parse or reload provided, highest precedence first
+	// Includes the ones overridden by a source taking precedence, see Sources
+	Contributions []Contribution
+}
+
+func (e Explanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s = %q from %s", e.Name, e.Value, e.Source)
+	if !e.ModifiedAt.IsZero() {
+		fmt.Fprintf(&b, " at %s", e.ModifiedAt.UTC().Format(time.RFC3339))
+	}
+	if e.Stale {
+		b.WriteString(", stale")
+	}
+	if e.LockedBy != "" {
+		fmt.Fprintf(&b, ", locked by %s", e.LockedBy)
+	}
+	for _, con := range e.Contributions {
+		if con.Source != e.Source {
+			fmt.Fprintf(&b, ", overrides %q from %s", con.Value, con.Source)
+		}
+	}
+	return b.String()
+}
+
+// Explains where the named option's current value comes from and whether it's locked
+func (c *ConfigSet) Explain(name string) (Explanation, error) {
+	if c.root != nil {
+		return c.root.Explain(c.prefix + name)
+	}
+	o, ok := c.formal[name]
+	if !ok {
+		return Explanation{}, fmt.Errorf("No such option: %v", name)
+	}
+
+	e := Explanation{Name: name, Value: o.Value.String(), Source: "default", LockedBy: c.locks[name], Contributions: slices.Clone(c.contributions[name])}
+	if _, set := c.actual[name]; set {
+		e.Source = c.origins[name]
+		e.ModifiedAt = c.setAt[name]
+		e.Stale = c.isStale(name)
+	}
+	return e, nil
+}
+
+// Explains where the named option of the global set gets its value from
+func Explain(name string) (Explanation, error) { return globalConfig.Explain(name) }
@@ -0,0 +1,117 @@
+package configManager
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_lockedOptions(t *testing.T) {
+	fsys := &memFS{}
+	fsys.WriteFile("app.json", []byte(`{"telemetry": true, "workers": 8}`), 0o644)
+	fsys.WriteFile("overlay.json", []byte(`{"telemetry": true, "locked": ["workers"]}`), 0o644)
+
+	var c ConfigSet
+	c.FS = fsys
+	c.Location = "app.json"
+	c.OverlayLocation = "overlay.json"
+	c.AddSource(DataSource(`{"telemetry": false, "channel": "stable", "locked": ["telemetry", "channel"]}`))
+	telemetry, _ := AddOptionToSet(&c, "telemetry", true)
+	workers, _ := AddOptionToSet(&c, "workers", int64(1))
+	AddOptionToSet(&c, "channel", "beta")
+
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *telemetry || *workers != 8 {
+		t.Fatalf("Locked value not applied: [%v] [%v]", *telemetry, *workers)
+	}
+	if by, ok := c.LockedBy("telemetry"); !ok || by != "source 1" {
+		t.Fatalf("Lock not recorded: [%v]", by)
+	}
+	if _, ok := c.LockedBy("workers"); ok {
+		t.Fatal("File locked an option")
+	}
+
+	if err := c.Set("telemetry", "true"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("Set overrode locked option: %v", err)
+	}
+	if err := c.ParseArgs([]string{"--channel=beta"}); !errors.Is(err, ErrLocked) {
+		t.Fatalf("ParseArgs overrode locked option: %v", err)
+	}
+
+	e, err := c.Explain("telemetry")
+	if err != nil || e.Source != "source 1" || e.LockedBy != "source 1" || e.Value != "false" {
+		t.Fatalf("Unexpected explanation: %+v %v", e, err)
+	}
+	if s := e.String(); !strings.Contains(s, "locked by source 1") {
+		t.Fatalf("Lock missing from explanation: %s", s)
+	}
+	if e, _ := c.Explain("workers"); e.Source != "app.json" || e.LockedBy != "" {
+		t.Fatalf("Unexpected explanation: %+v", e)
+	}
+}
+
+func Test_lockedReload(t *testing.T) {
+	fsys := &memFS{}
+	fsys.WriteFile("app.json", []byte(`{"port": 80}`), 0o644)
+	policy := DataSource(`{"port": 443, "locked": ["port"]}`)
+
+	var c ConfigSet
+	c.FS = fsys
+	c.Location = "app.json"
+	c.Sources = []Source{DataSource(`{}`), &policy}
+	port, _ := AddOptionToSet(&c, "port", int64(0))
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 443 {
+		t.Fatalf("Locked value not applied: [%v]", *port)
+	}
+
+	c.Sources[0] = DataSource(`{"port": 8080}`)
+	if _, err := c.reloadFile(); err != nil || *port != 443 {
+		t.Fatalf("Reload overrode locked option: [%v] %v", *port, err)
+	}
+
+	policy = DataSource(`{}`)
+	if _, err := c.reloadFile(); err != nil || *port != 8080 {
+		t.Fatalf("Lifted lock still applied: [%v] %v", *port, err)
+	}
+	if err := c.Set("port", "9000"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_setBy(t *testing.T) {
+	var c ConfigSet
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	c.clock = func() time.Time { return now }
+	AddOptionToSet(&c, "port", int64(80))
+	AddOptionToSet(&c, "debug", false)
+	events, stop := c.Events()
+	defer stop()
+
+	if e, _ := c.Explain("port"); e.Source != "default" || !e.ModifiedAt.IsZero() {
+		t.Fatalf("Unexpected explanation: %+v", e)
+	}
+	if err := c.SetBy("port", "8080", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	e, _ := c.Explain("port")
+	if e.Source != "alice" || !e.ModifiedAt.Equal(now) {
+		t.Fatalf("Modification not recorded: %+v", e)
+	}
+	if s := e.String(); s != `port = "8080" from alice at 2025-01-02T15:04:05Z` {
+		t.Fatalf("Unexpected explanation: %s", s)
+	}
+	if ev := <-events; ev.Source != "alice" {
+		t.Fatalf("Unexpected event source: %+v", ev)
+	}
+
+	c.Set("debug", "true")
+	if e, _ := c.Explain("debug"); e.Source != "Set" {
+		t.Fatalf("Unexpected explanation: %+v", e)
+	}
+}
@@ -0,0 +1,39 @@
+package configManager
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// =-=-= logLevelValue
+
+// Holds a slog.Level, set from "debug", "info", "warn" or "error" in any case
+// "warning" is accepted for "warn", offsets such as "info+2" and plain numbers as slog allows them
+type logLevelValue slog.Level
+
+func newLogLevelValue(p *slog.Level) *logLevelValue { return (*logLevelValue)(p) }
+
+func (l *logLevelValue) Set(s string) error {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil {
+		*l = logLevelValue(n)
+		return nil
+	}
+	if rest, ok := strings.CutPrefix(strings.ToLower(s), "warning"); ok {
+		s = "warn" + rest
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return fmt.Errorf("%w: unknown log level %q, expected debug, info, warn or error", ErrParse, s)
+	}
+	*l = logLevelValue(level)
+	return nil
+}
+
+func (l logLevelValue) Get() any { return slog.Level(l) }
+
+func (l logLevelValue) String() string { return strings.ToLower(slog.Level(l).String()) }
+
+func (l logLevelValue) MarshalConfig() (any, error) { return l.String(), nil }
@@ -0,0 +1,58 @@
+package configManager
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func Test_logLevelVal(t *testing.T) {
+	var l slog.Level
+	v := newLogLevelValue(&l)
+
+	for in, want := range map[string]slog.Level{
+		"debug":   slog.LevelDebug,
+		"INFO":    slog.LevelInfo,
+		"Warn":    slog.LevelWarn,
+		"warning": slog.LevelWarn,
+		"error":   slog.LevelError,
+		"info+2":  slog.LevelInfo + 2,
+		"-4":      slog.LevelDebug,
+	} {
+		if err := v.Set(in); err != nil {
+			t.Fatalf("Set(%q) rejected valid level: %v", in, err)
+		}
+		if got := v.Get().(slog.Level); got != want || l != want {
+			t.Fatalf("Set(%q), expected: %v received: %v", in, want, got)
+		}
+	}
+
+	for _, in := range []string{"", "verbose", "fatal", "info+"} {
+		if err := v.Set(in); !errors.Is(err, ErrParse) {
+			t.Fatalf("Set(%q) accepted invalid level: %v", in, err)
+		}
+	}
+}
+
+func Test_logLevelOption(t *testing.T) {
+	var c ConfigSet
+	level, err := AddOptionToSet(&c, "log.level", slog.LevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFromData([]byte(`{"log": {"level": "DEBUG"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *level != slog.LevelDebug {
+		t.Fatalf("Unexpected level: %v", *level)
+	}
+
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"debug"`) {
+		t.Fatalf("Level not saved by name:\n%s", data)
+	}
+}
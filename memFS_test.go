@@ -0,0 +1,45 @@
+package configManager
+
+import (
+	"bytes"
+	"io/fs"
+	"sync"
+	"testing/fstest"
+	"time"
+)
+
+// A writable in-memory file system for the package tests, the exported one is configtest.MemFS
+// The zero value is an empty file system ready to use
+type memFS struct {
+	mu    sync.RWMutex
+	files fstest.MapFS
+}
+
+func (m *memFS) Open(name string) (fs.File, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.files.Open(name)
+}
+
+// Creates or replaces the named file, directories are implicit
+func (m *memFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.files == nil {
+		m.files = make(fstest.MapFS)
+	}
+	m.files[name] = &fstest.MapFile{Data: bytes.Clone(data), Mode: perm, ModTime: time.Now()}
+	return nil
+}
+
+// Directories are implicit in a memFS, only the path is checked
+func (m *memFS) MkdirAll(name string, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrInvalid}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package configManager
+
+import (
+	"strconv"
+	"testing"
+	"unsafe"
+)
+
+// Defines n options the way generated device models do, many sharing the same few defaults
+func defineMany(c *ConfigSet, n int) {
+	for i := range n {
+		name := "device.port" + strconv.Itoa(i)
+		switch i % 3 {
+		case 0:
+			AddOptionToSet(c, name+".enabled", false)
+		case 1:
+			AddOptionToSet(c, name+".speed", int64(1000))
+		default:
+			AddOptionToSet(c, name+".mode", "auto")
+		}
+	}
+}
+
+func Benchmark_defineOptions(b *testing.B) {
+	for _, n := range []int{1000, 20000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				var c ConfigSet
+				defineMany(&c, n)
+			}
+		})
+	}
+}
+
+func Benchmark_defineOptionsReserved(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		var c ConfigSet
+		c.Reserve(20000)
+		defineMany(&c, 20000)
+	}
+}
+
+func Test_defineManyOptions(t *testing.T) {
+	var c ConfigSet
+	c.Reserve(10)
+	defineMany(&c, 600)
+
+	if n := len(c.View().Names()); n != 600 {
+		t.Fatalf("snapshot has %d options, want 600", n)
+	}
+
+	a, b := c.Lookup("device.port2.mode"), c.Lookup("device.port5.mode")
+	if unsafe.StringData(a.DefValue) != unsafe.StringData(b.DefValue) {
+		t.Errorf("equal default values not interned")
+	}
+
+	if err := c.Set("device.port1.speed", "10"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := SnapshotGet[int64](c.View(), "device.port1.speed"); v != 10 {
+		t.Errorf("snapshot holds %v after Set, want 10", v)
+	}
+	if c.Lookup("device.port4.speed").DefValue != "1000" {
+		t.Errorf("pooled option overwritten")
+	}
+}
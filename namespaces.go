@@ -0,0 +1,54 @@
+package configManager
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Separates namespaces in option names, e.g. "db.port" is in namespace "db"
+const NamespaceSeparator = "."
+
+// Returns the distinct namespaces of registered options in lexicographical order
+// Options without a separator are their own namespace
+// Meant for sources that can filter server side, so they fetch only what this set uses
+func (c *ConfigSet) Namespaces() []string {
+	ns := make(map[string]struct{})
+	for name := range c.base().formal {
+		name, ok := strings.CutPrefix(name, c.prefix)
+		if !ok {
+			continue
+		}
+		n, _, _ := strings.Cut(name, NamespaceSeparator)
+		ns[n] = struct{}{}
+	}
+	return slices.Sorted(maps.Keys(ns))
+}
+
+// Reports whether key names a registered option or lies inside one of the set's namespaces
+// Sources can use it to drop unrelated keys before handing data to the set
+func (c *ConfigSet) Wants(key string) bool {
+	n, _, _ := strings.Cut(key, NamespaceSeparator)
+	_, n = c.owner(n)
+	root, key := c.owner(key)
+	if _, ok := root.formal[key]; ok {
+		return true
+	}
+	for name := range root.formal {
+		if name == n || strings.HasPrefix(name, n+NamespaceSeparator) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns a copy of data holding only the keys the set Wants
+func (c *ConfigSet) FilterData(data map[string]any) map[string]any {
+	out := make(map[string]any)
+	for k, v := range data {
+		if c.Wants(k) {
+			out[k] = v
+		}
+	}
+	return out
+}
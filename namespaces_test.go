@@ -0,0 +1,26 @@
+package configManager
+
+import (
+	"slices"
+	"testing"
+)
+
+func Test_namespaces(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "db.host", "")
+	AddOptionToSet(&c, "db.port", int64(5432))
+	AddOptionToSet(&c, "debug", false)
+
+	if got, want := c.Namespaces(), []string{"db", "debug"}; !slices.Equal(got, want) {
+		t.Fatalf("Namespaces mismatch, expected: %v received: %v", want, got)
+	}
+
+	data := map[string]any{"db.host": "x", "db.user": "y", "debug": true, "billing.rate": 3}
+	got := c.FilterData(data)
+	if len(got) != 3 {
+		t.Fatalf("Unexpected filtered keys: %v", got)
+	}
+	if _, ok := got["billing.rate"]; ok {
+		t.Fatal("Unrelated namespace was kept")
+	}
+}
@@ -0,0 +1,104 @@
+package configManager
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Flattens nested objects into dotted keys, {"server": {"port": 80}} becomes {"server.port": 80}
+// Objects stored under a registered option's name are kept whole so map typed options still work
+func (c *ConfigSet) flatten(d map[string]any) map[string]any {
+	out := make(map[string]any, len(d))
+	c.flattenInto(out, "", d)
+	return out
+}
+
+func (c *ConfigSet) flattenInto(out map[string]any, prefix string, d map[string]any) {
+	for k, v := range d {
+		key := prefix + k
+		if m, ok := v.(map[string]any); ok && c.formal[key] == nil && key != ConditionalKey {
+			c.flattenInto(out, key+NamespaceSeparator, m)
+			continue
+		}
+		out[key] = v
+	}
+}
+
+// Reports whether the set's format nests dotted option names into objects
+// Parse flattens them and Save rebuilds the nesting, other formats keep dotted names as written
+func (c *ConfigSet) nestedFormat() bool {
+	switch c.Format {
+	case JSON, JSONC, XML, TOML, HCL:
+		return true
+	}
+	return false
+}
+
+// Flattens the original text of nested JSON objects the same way as flatten
+func (c *ConfigSet) flattenRaw(raw map[string]json.RawMessage) map[string]json.RawMessage {
+	if raw == nil {
+		return nil
+	}
+	out := make(map[string]json.RawMessage, len(raw))
+	c.flattenRawInto(out, "", raw)
+	return out
+}
+
+func (c *ConfigSet) flattenRawInto(out map[string]json.RawMessage, prefix string, raw map[string]json.RawMessage) {
+	for k, v := range raw {
+		key := prefix + k
+		var m map[string]json.RawMessage
+		if c.formal[key] == nil && key != ConditionalKey && json.Unmarshal(v, &m) == nil && m != nil {
+			c.flattenRawInto(out, key+NamespaceSeparator, m)
+			continue
+		}
+		out[key] = v
+	}
+}
+
+// Rebuilds nested objects from dotted keys, the inverse of flatten
+// When a key is both a value and a prefix of other keys, such as "a" and "a.b", the dotted keys stay flat
+func unflatten(d map[string]any) map[string]any {
+	out := make(map[string]any)
+	// shorter keys first so a value at "a" is seen before "a.b" tries to nest under it
+	keys := slices.SortedFunc(maps.Keys(d), func(a, b string) int {
+		return strings.Count(a, NamespaceSeparator) - strings.Count(b, NamespaceSeparator)
+	})
+
+	// only maps built here may be nested into, never a map that is an option's value
+	built := map[uintptr]bool{reflect.ValueOf(out).Pointer(): true}
+
+	for _, k := range keys {
+		parts := strings.Split(k, NamespaceSeparator)
+		node := out
+		ok := true
+		for _, p := range parts[:len(parts)-1] {
+			next, exists := node[p]
+			if !exists {
+				m := make(map[string]any)
+				built[reflect.ValueOf(m).Pointer()] = true
+				node[p] = m
+				node = m
+				continue
+			}
+			m, isMap := next.(map[string]any)
+			if !isMap || !built[reflect.ValueOf(m).Pointer()] {
+				ok = false
+				break
+			}
+			node = m
+		}
+
+		if ok {
+			if _, taken := node[parts[len(parts)-1]]; !taken {
+				node[parts[len(parts)-1]] = d[k]
+				continue
+			}
+		}
+		out[k] = d[k]
+	}
+	return out
+}
@@ -0,0 +1,109 @@
+package configManager
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_nestedJSON(t *testing.T) {
+	var c ConfigSet
+	port, _ := AddOptionToSet(&c, "server.port", int64(0))
+	host, _ := AddOptionToSet(&c, "server.host", "")
+	name, _ := AddOptionToSet(&c, "name", "")
+	headers, _ := AddOptionToSet(&c, "server.headers", OrderedMap{})
+
+	err := c.ParseFromData([]byte(`{"name":"api","server":{"port":80,"host":"localhost","headers":{"z":"1","a":"2"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *port != 80 || *host != "localhost" || *name != "api" {
+		t.Fatalf("Nested keys not flattened: [%v] [%v] [%v]", *port, *host, *name)
+	}
+	if keys := headers.Keys(); len(keys) != 2 || keys[0] != "z" {
+		t.Fatalf("Key order of nested object lost: %v", keys)
+	}
+
+	data, _ := c.SaveTo()
+	var d ConfigSet
+	port2, _ := AddOptionToSet(&d, "server.port", int64(0))
+	if err := d.ParseFromData(data); err != nil || *port2 != 80 {
+		t.Fatalf("Saved nesting not parsed back: [%v] %v", *port2, err)
+	}
+	if !strings.Contains(string(data), `"server": {`) {
+		t.Fatalf("Nesting not rebuilt on save:\n%s", data)
+	}
+}
+
+func Test_nestedXML(t *testing.T) {
+	c := ConfigSet{Format: XML}
+	port, _ := AddOptionToSet(&c, "server.port", int64(0))
+	hosts, _ := StringListSet(&c, "server.hosts", nil, ",", true)
+	name, _ := AddOptionToSet(&c, "name", "")
+
+	err := c.ParseFromData([]byte(`<?xml version="1.0"?>
+<app>
+  <name>api</name>
+  <server><port>80</port><hosts>a, b</hosts></server>
+</app>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *port != 80 || *name != "api" || len(*hosts) != 2 {
+		t.Fatalf("XML elements not flattened: [%v] [%v] %v", *port, *name, *hosts)
+	}
+
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<config>\n  <name>api</name>\n  <server>\n    <hosts>a</hosts>\n    <hosts>b</hosts>\n    <port>80</port>\n  </server>\n</config>"
+	if string(data) != want {
+		t.Fatalf("Unexpected XML:\n%s", data)
+	}
+
+	AddOptionToSet(&c, "bad name", "")
+	if _, err := c.SaveTo(); err == nil {
+		t.Fatal("Invalid element name saved")
+	}
+}
+
+func Test_xmlAttributes(t *testing.T) {
+	data := []byte(`<app env="prod">
+  <server port="80" host="a"><port>81</port></server>
+  <timeout unit="s">30</timeout>
+  <peer zone="x">p1</peer><peer zone="y">p2</peer>
+</app>`)
+
+	for _, tc := range []struct {
+		pref XMLPreference
+		port int64
+		env  string
+		unit string
+	}{
+		{PreferElements, 81, "prod", "s"},
+		{PreferAttributes, 80, "prod", "s"},
+		{IgnoreAttributes, 81, "", ""},
+	} {
+		c := ConfigSet{Format: XML, XMLAttributes: tc.pref}
+		port, _ := AddOptionToSet(&c, "server.port", int64(0))
+		host, _ := AddOptionToSet(&c, "server.host", "")
+		env, _ := AddOptionToSet(&c, "env", "")
+		timeout, _ := AddOptionToSet(&c, "timeout", int64(0))
+		unit, _ := AddOptionToSet(&c, "timeout.unit", "")
+		peers, _ := StringListSet(&c, "peer", nil, ",", true)
+		zone, _ := AddOptionToSet(&c, "peer.zone", "")
+
+		if err := c.ParseFromData(data); err != nil {
+			t.Fatal(err)
+		}
+		if *port != tc.port || *env != tc.env || *unit != tc.unit || *timeout != 30 {
+			t.Fatalf("%v: unexpected values port=%v env=%q unit=%q timeout=%v", tc.pref, *port, *env, *unit, *timeout)
+		}
+		if tc.pref != IgnoreAttributes && *host != "a" {
+			t.Fatalf("%v: attribute without matching element not bound: %q", tc.pref, *host)
+		}
+		if len(*peers) != 2 || *zone != "" {
+			t.Fatalf("%v: repeated elements: %v zone=%q", tc.pref, *peers, *zone)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Package opa checks configManager configurations against policies served by Open Policy Agent
+// It talks to OPA's REST data API so applications need no OPA dependency, run OPA as a sidecar or central service
+//
+//	c.AddPolicy("org", &opa.Policy{URL: "http://localhost:8181", Path: "config/deny"})
+//
+// The candidate configuration is sent as input with nested objects, e.g. input.server.port,
+// and the rule at Path returns the violations, usually a set of messages:
+//
+//	package config
+//
+//	deny contains msg if {
+//		input.server.tls == false
+//		msg := "server.tls must be enabled"
+//	}
+//
+// A rule may also return objects with "option" and "msg" or "reason" members,
+// or a boolean allow rule, false rejecting the configuration
+package opa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/quollveth/configManager"
+)
+
+// A configManager.Policy evaluating a rule of an OPA server
+type Policy struct {
+	URL    string       // Address of the OPA server, e.g. http://localhost:8181
+	Path   string       // Rule under /v1/data, e.g. config/deny
+	Client *http.Client // Defaults to a client with a 10 second timeout
+
+	// Limits each evaluation, defaults to context.Background
+	Context func() context.Context
+}
+
+var defaultClient = &http.Client{Timeout: 10 * time.Second}
+
+// Sends the candidate to the server and converts the rule's result into violations
+// A rule that's undefined for the input allows it
+func (p *Policy) Evaluate(candidate *configManager.Snapshot) ([]configManager.Violation, error) {
+	body, err := json.Marshal(map[string]any{"input": Input(candidate)})
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if p.Context != nil {
+		ctx = p.Context()
+	}
+	url := strings.TrimSuffix(p.URL, "/") + "/v1/data/" + strings.Trim(p.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := p.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+
+	var out struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+	return violations(out.Result)
+}
+
+// Returns the configuration of s with nested objects, as sent to OPA
+func Input(s *configManager.Snapshot) map[string]any {
+	input := make(map[string]any)
+	for _, name := range s.Names() {
+		v, _ := s.Get(name)
+		node := input
+		parts := strings.Split(name, configManager.NamespaceSeparator)
+		for i, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]any)
+			if !ok {
+				if _, taken := node[part]; taken {
+					// an option is named after this namespace, the rest of the name stays whole
+					parts = []string{strings.Join(parts[i:], configManager.NamespaceSeparator)}
+					break
+				}
+				child = make(map[string]any)
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = v
+	}
+	return input
+}
+
+// Converts a rule's result: a set of messages, a set of objects, or an allow boolean
+func violations(result json.RawMessage) ([]configManager.Violation, error) {
+	if len(result) == 0 {
+		return nil, nil
+	}
+	var allow bool
+	if json.Unmarshal(result, &allow) == nil {
+		if allow {
+			return nil, nil
+		}
+		return []configManager.Violation{{Reason: "denied"}}, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(result, &items); err != nil {
+		return nil, fmt.Errorf("unexpected result %s", result)
+	}
+	var vs []configManager.Violation
+	for _, item := range items {
+		var msg string
+		if json.Unmarshal(item, &msg) == nil {
+			vs = append(vs, configManager.Violation{Reason: msg})
+			continue
+		}
+		var obj struct {
+			Option string `json:"option"`
+			Msg    string `json:"msg"`
+			Reason string `json:"reason"`
+		}
+		if err := json.Unmarshal(item, &obj); err != nil {
+			return nil, fmt.Errorf("unexpected violation %s", item)
+		}
+		if obj.Reason == "" {
+			obj.Reason = obj.Msg
+		}
+		vs = append(vs, configManager.Violation{Option: obj.Option, Reason: obj.Reason})
+	}
+	return vs, nil
+}
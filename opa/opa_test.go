@@ -0,0 +1,106 @@
+package opa
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/quollveth/configManager"
+)
+
+// Serves a deny rule rejecting configurations without TLS
+func server(t *testing.T, result func(input map[string]any) any) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/data/config/deny" {
+			http.NotFound(w, r)
+			return
+		}
+		var body struct {
+			Input map[string]any `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"result": result(body.Input)})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func Test_policy(t *testing.T) {
+	srv := server(t, func(input map[string]any) any {
+		if input["server"].(map[string]any)["tls"] == false {
+			return []any{"server.tls must be enabled", map[string]any{"option": "server.port", "msg": "use 443"}}
+		}
+		return []any{}
+	})
+
+	var c configManager.ConfigSet
+	tls, _ := configManager.AddOptionToSet(&c, "server.tls", true)
+	configManager.AddOptionToSet(&c, "server.port", int64(443))
+	c.AddPolicy("org", &Policy{URL: srv.URL, Path: "/config/deny"})
+
+	err := c.ParseFromData([]byte(`{"server": {"tls": false}}`))
+	var pe *configManager.PolicyError
+	if !errors.As(err, &pe) || len(pe.Violations) != 2 || !*tls {
+		t.Fatalf("Violations not reported: [%v] %v", *tls, err)
+	}
+	want := []configManager.Violation{
+		{Policy: "org", Reason: "server.tls must be enabled"},
+		{Policy: "org", Option: "server.port", Reason: "use 443"},
+	}
+	for i, v := range pe.Violations {
+		if v != want[i] {
+			t.Fatalf("Expected %+v received %+v", want[i], v)
+		}
+	}
+
+	if err := c.ParseFromData([]byte(`{"server": {"port": 8443}}`)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_policyResults(t *testing.T) {
+	cases := []struct {
+		result string
+		want   int
+		fails  bool
+	}{
+		{``, 0, false},
+		{`true`, 0, false},
+		{`false`, 1, false},
+		{`["a", "b"]`, 2, false},
+		{`{"a": 1}`, 0, true},
+	}
+	for _, tc := range cases {
+		vs, err := violations(json.RawMessage(tc.result))
+		if (err != nil) != tc.fails || len(vs) != tc.want {
+			t.Fatalf("%s: expected %d violations received %v %v", tc.result, tc.want, vs, err)
+		}
+	}
+}
+
+func Test_policyUnavailable(t *testing.T) {
+	srv := server(t, nil)
+	var c configManager.ConfigSet
+	configManager.AddOptionToSet(&c, "port", int64(80))
+	c.AddPolicy("org", &Policy{URL: srv.URL, Path: "missing"})
+
+	if err := c.ParseFromData([]byte(`{"port": 8080}`)); !errors.Is(err, configManager.ErrPolicy) {
+		t.Fatalf("Unreachable rule didn't reject: %v", err)
+	}
+}
+
+func Test_input(t *testing.T) {
+	var c configManager.ConfigSet
+	configManager.AddOptionToSet(&c, "log", "info")
+	configManager.AddOptionToSet(&c, "log.file", "app.log")
+	configManager.AddOptionToSet(&c, "server.port", int64(80))
+
+	input := Input(c.View())
+	if input["log"] != "info" || input["log.file"] != "app.log" || input["server"].(map[string]any)["port"] != int64(80) {
+		t.Fatalf("Unexpected input: %v", input)
+	}
+}
@@ -0,0 +1,37 @@
+package configManager
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_optionError(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "workers", int64(4), Min(1))
+	AddOptionToSet(&c, "labels", OrderedMap{{"env", "dev"}})
+
+	cases := []struct {
+		name, value string
+		cause       error
+		run         func() error
+	}{
+		{"workers", "many", ErrParse, func() error { return c.Set("workers", "many") }},
+		{"workers", "0", ErrRange, func() error { return c.Set("workers", "0") }},
+		{"workers", "0", ErrRange, func() error { return c.ParseFromData([]byte(`{"workers": 0}`)) }},
+		{"workers", "-1", ErrRange, func() error { return c.ApplyMergePatch([]byte(`{"workers": -1}`)) }},
+		{"workers", "x", ErrParse, func() error { return c.ParseArgs([]string{"--workers=x"}) }},
+		{"labels[0]", "1", ErrParse, func() error { return c.Set("labels[0]", "1") }},
+	}
+	for _, tc := range cases {
+		err := tc.run()
+		var oe *OptionError
+		if !errors.As(err, &oe) || oe.Name != tc.name || oe.Raw != tc.value || !errors.Is(err, tc.cause) {
+			t.Fatalf("%s=%s: unexpected error %#v", tc.name, tc.value, err)
+		}
+	}
+
+	err := c.Set("workers", "many")
+	if want := `workers: invalid value "many": parse error`; err.Error() != want {
+		t.Fatalf("Expected [%s] received [%v]", want, err)
+	}
+}
@@ -0,0 +1,222 @@
+package configManager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// A key value pair of an OrderedMap
+type MapEntry struct {
+	Key   string
+	Value any
+}
+
+// A map that keeps its keys in the order they appear in the file
+// Use it for options where order matters, such as middleware chains or rule lists written as objects
+// Values are decoded as JSON would decode them into an any
+type OrderedMap []MapEntry
+
+// Returns the value of key and whether it's present
+func (m OrderedMap) Get(key string) (any, bool) {
+	for _, e := range m {
+		if e.Key == key {
+			return e.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Returns the keys in order
+func (m OrderedMap) Keys() []string {
+	keys := make([]string, len(m))
+	for i, e := range m {
+		keys[i] = e.Key
+	}
+	return keys
+}
+
+// Returns the map as a compact JSON object
+func (m OrderedMap) String() string { return orderedMapValue(m).String() }
+
+// Encodes the map as a JSON object with keys in order
+func (m OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, e := range m {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Decodes a JSON object keeping its key order, later duplicates replace earlier ones in place
+func (m *OrderedMap) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return fmt.Errorf("expected a JSON object, got %v", tok)
+	}
+
+	var out OrderedMap
+	index := make(map[string]int)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+
+		if i, dup := index[key]; dup {
+			out[i].Value = v
+			continue
+		}
+		index[key] = len(out)
+		out = append(out, MapEntry{key, v})
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	*m = out
+	return nil
+}
+
+// =-=-= orderedMapValue
+
+type orderedMapValue OrderedMap
+
+func newOrderedMapValue(p *OrderedMap) *orderedMapValue { return (*orderedMapValue)(p) }
+
+func (o *orderedMapValue) Set(s string) error {
+	var m OrderedMap
+	if err := m.UnmarshalJSON([]byte(s)); err != nil {
+		return ErrParse
+	}
+	*o = orderedMapValue(m)
+	return nil
+}
+
+// Returns a copy, snapshots must not share entries with the option
+func (o orderedMapValue) Get() any {
+	if o == nil {
+		return OrderedMap(nil)
+	}
+	out := make(OrderedMap, len(o))
+	for i, e := range o {
+		out[i] = MapEntry{e.Key, cloneDecoded(e.Value)}
+	}
+	return out
+}
+
+func (o orderedMapValue) String() string {
+	b, _ := OrderedMap(o).MarshalJSON()
+	return string(b)
+}
+
+func (o orderedMapValue) MarshalConfig() (any, error) { return OrderedMap(o), nil }
+
+// =-=-= composite values
+
+// Reports whether v is a map or slice, which can't be passed to Set through fmt.Sprint
+func isComposite(v any) bool {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// Returns a deep copy of a value decoded from JSON, maps and slices included
+func cloneDecoded(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[k] = cloneDecoded(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = cloneDecoded(e)
+		}
+		return out
+	}
+	return v
+}
+
+// Returns the string handed to Set for a parsed value
+// Maps and slices are passed as JSON, using the original file text raw when it's
+// available and still matches v so key order is kept
+func stringify(v any, raw json.RawMessage) string {
+	if !isComposite(v) {
+		if text, ok := exactNumber(v, raw); ok {
+			return text
+		}
+		// whole numbers are written without an exponent, integer options can't parse 1e+06
+		if f, ok := v.(float64); ok && f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		return fmt.Sprint(v)
+	}
+
+	if raw != nil {
+		var decoded any
+		if json.Unmarshal(raw, &decoded) == nil && Equal(decoded, v) {
+			var buf bytes.Buffer
+			if json.Compact(&buf, raw) == nil {
+				return buf.String()
+			}
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// Returns the original text of member key of the object raw, nil when raw isn't an object holding it
+func rawMember(raw json.RawMessage, key string) json.RawMessage {
+	var m map[string]json.RawMessage
+	if raw == nil || json.Unmarshal(raw, &m) != nil {
+		return nil
+	}
+	return m[key]
+}
+
+// Returns the original text of item i of the array raw, nil when raw isn't an array holding it
+func rawItem(raw json.RawMessage, i int) json.RawMessage {
+	var items []json.RawMessage
+	if raw == nil || json.Unmarshal(raw, &items) != nil || i >= len(items) {
+		return nil
+	}
+	return items[i]
+}
@@ -0,0 +1,59 @@
+package configManager
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_orderedMapVal(t *testing.T) {
+	var m OrderedMap
+	v := newOrderedMapValue(&m)
+
+	if err := valueTester(
+		v,
+		[]string{
+			`{"z":1,"a":2}`,
+			`{}`,
+			`{"b":"x","a":[1,2]}`,
+		},
+		[]string{
+			"",
+			"[1,2]",
+			`{"a":`,
+		},
+		&m,
+		func(s string, m OrderedMap) bool { return m.String() == s },
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_orderedMapParseSave(t *testing.T) {
+	var c ConfigSet
+	chain, _ := AddOptionToSet(&c, "middleware", OrderedMap{})
+
+	toParse := `{"middleware": {"recover": true, "logger": {"level": "info"}, "auth": false, "gzip": 5}}`
+	if err := c.ParseFromData([]byte(toParse)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"recover", "logger", "auth", "gzip"}
+	if got := chain.Keys(); !slices.Equal(got, want) {
+		t.Fatalf("Key order mismatch, expected: %v received: %v", want, got)
+	}
+
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	last := -1
+	for _, k := range want {
+		i := strings.Index(out, `"`+k+`"`)
+		if i < last {
+			t.Fatalf("Saved key order mismatch:\n%s", out)
+		}
+		last = i
+	}
+}
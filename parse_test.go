@@ -70,3 +70,39 @@ func Test_saveTo(t *testing.T) {
 
 	c.Save()
 }
+
+func Test_saveChangedOnly(t *testing.T) {
+	c := ConfigSet{SaveChangedOnly: true}
+	AddOptionToSet(&c, "greeting", "hello")
+	AddOptionToSet(&c, "server.port", int64(80))
+	AddOptionToSet(&c, "server.host", "localhost")
+	c.Set("server.port", "8080")
+	c.Set("greeting", "hello")
+
+	data, err := c.SaveTo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  \"greeting\": \"hello\",\n  \"server\": {\n    \"port\": 8080\n  }\n}"
+	if string(data) != want {
+		t.Fatalf("Defaults were saved:\n%s", data)
+	}
+}
+
+func Test_globalParseErrors(t *testing.T) {
+	AddOption("global.port", int64(80))
+
+	if err := ParseFromData([]byte(`{"global": {"port": "eighty"}}`)); !errors.Is(err, ErrParse) {
+		t.Fatalf("Global ParseFromData swallowed the error: %v", err)
+	}
+	if err := Parse(); err == nil {
+		t.Fatal("Global Parse without a location succeeded")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("MustParse didn't panic")
+		}
+	}()
+	MustParse()
+}
@@ -0,0 +1,447 @@
+package configManager
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Returned when a patch document is malformed or one of its operations can't be applied
+var ErrPatch = errors.New("patch failed")
+
+// Applies an RFC 6902 JSON Patch to the effective configuration, as SaveTo would write it as JSON with nested objects
+//
+//	[{"op": "replace", "path": "/server/port", "value": 8080}, {"op": "remove", "path": "/debug"}]
+//
+// Options the patch changes are set, options it removes go back to their default, all or nothing:
+// if an operation fails, a test doesn't match or an option rejects its new value nothing changes
+// Locked options can't be changed and unknown options are rejected
+func (c *ConfigSet) ApplyJSONPatch(patch []byte) error {
+	var ops []patchOp
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return fmt.Errorf("%w: %v", ErrPatch, err)
+	}
+	return c.applyPatch("JSON patch", func(doc any) (any, error) {
+		for i, op := range ops {
+			var err error
+			if doc, err = op.apply(doc); err != nil {
+				return nil, fmt.Errorf("%w: operation %d (%s %s): %v", ErrPatch, i, op.Op, op.Path, err)
+			}
+		}
+		return doc, nil
+	})
+}
+
+// Applies an RFC 7386 JSON Merge Patch to the effective configuration, see ApplyJSONPatch
+//
+//	{"server": {"port": 8080}, "debug": null}
+//
+// Members set to null go back to their default
+func (c *ConfigSet) ApplyMergePatch(patch []byte) error {
+	var p any
+	if err := decodeJSON(patch, &p); err != nil {
+		return fmt.Errorf("%w: %v", ErrPatch, err)
+	}
+	return c.applyPatch("merge patch", func(doc any) (any, error) { return mergePatch(doc, p), nil })
+}
+
+// Applies the global set's configuration patched with an RFC 6902 JSON Patch
+func ApplyJSONPatch(patch []byte) error { return globalConfig.ApplyJSONPatch(patch) }
+
+// Applies the global set's configuration patched with an RFC 7386 JSON Merge Patch
+func ApplyMergePatch(patch []byte) error { return globalConfig.ApplyMergePatch(patch) }
+
+// Patches the effective configuration and applies the result in two phases:
+// every changed option is validated and set, then the new configuration is published, or everything is restored
+func (c *ConfigSet) applyPatch(source string, patch func(doc any) (any, error)) error {
+	if c.root != nil {
+		return c.root.applyPatch(source, patch)
+	}
+	c.mu.Lock()
+	changed, err := c.patchDocument(source, patch)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if len(changed) > 0 {
+		c.emit(Changed, source, nil, changed...)
+	}
+	return nil
+}
+
+func (c *ConfigSet) patchDocument(source string, patch func(doc any) (any, error)) (changed []string, err error) {
+	doc, err := c.document()
+	if err != nil {
+		return nil, err
+	}
+	before := c.flatten(doc)
+
+	// the patch works on its own copy so before stays untouched
+	working, _ := c.document()
+	result, err := patch(working)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := result.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: the configuration must remain an object", ErrPatch)
+	}
+	after := c.flatten(m)
+	for name := range after {
+		if _, ok := c.formal[name]; !ok {
+			return nil, fmt.Errorf("No such option: %v", name)
+		}
+	}
+
+	restore := c.checkpoint()
+	for _, o := range c.sortOptions(c.formal) {
+		v, present := after[o.Name]
+		if present && jsonEqual(v, before[o.Name]) {
+			continue
+		}
+		if err = c.checkLock(o.Name); err != nil {
+			restore()
+			return nil, err
+		}
+
+		delete(c.schedules, o.Name)
+		if !present {
+			o.set(o.DefValue)
+			delete(c.actual, o.Name)
+			delete(c.setAt, o.Name)
+			delete(c.origins, o.Name)
+		} else {
+			v, raw := patchNumber(v)
+			err = c.checkCoercion(o, v, raw)
+			if err == nil {
+				err = c.setValue(o, stringify(v, raw))
+			}
+			if err != nil {
+				restore()
+				return nil, optionErr(o.Name, err)
+			}
+			c.markSet(o, source)
+		}
+		changed = append(changed, o.Name)
+	}
+
+	if err = c.checkExperimental(); err == nil {
+		err = c.applyOverrides()
+	}
+	if err == nil {
+		err = c.checkPolicies()
+	}
+	if err != nil {
+		restore()
+		return nil, err
+	}
+	c.publish()
+	return changed, nil
+}
+
+// Returns the effective configuration as decoded JSON with nested objects
+func (c *ConfigSet) document() (map[string]any, error) {
+	values := make(map[string]any, len(c.formal))
+	for name, o := range c.formal {
+		v, err := marshalValue(o.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		values[name] = v
+	}
+	data, err := json.Marshal(unflatten(values))
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	err = decodeJSON(data, &doc)
+	return doc, err
+}
+
+// Decodes a single JSON value keeping numbers as json.Number, so large integers and decimals aren't rounded through float64
+func decodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected data after the JSON value")
+	}
+	return nil
+}
+
+// Returns a patched number as the float64 and original text parsing works with, so the text reaches the option unchanged
+func patchNumber(v any) (any, json.RawMessage) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v, nil
+	}
+	f, _ := n.Float64()
+	return f, json.RawMessage(n)
+}
+
+// Reports whether two decoded JSON values are equal, numbers compare by value rather than by their text
+func jsonEqual(a, b any) bool {
+	switch x := a.(type) {
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		if x == y {
+			return true
+		}
+		fx, _, errx := big.ParseFloat(string(x), 10, 1024, big.ToNearestEven)
+		fy, _, erry := big.ParseFloat(string(y), 10, 1024, big.ToNearestEven)
+		return errx == nil && erry == nil && fx.Cmp(fy) == 0
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			if w, ok := y[k]; !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return Equal(a, b)
+}
+
+// =-=-= Merge patch
+
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any)
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+	return t
+}
+
+// =-=-= JSON patch
+
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+func (op patchOp) apply(doc any) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("missing value")
+		}
+		if err := decodeJSON(op.Value, &value); err != nil {
+			return nil, err
+		}
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if value, err = pointerGet(doc, from); err != nil {
+			return nil, err
+		}
+		if op.Op == "move" {
+			if op.Path != op.From && strings.HasPrefix(op.Path, op.From+"/") {
+				return nil, fmt.Errorf("can't move %s into itself", op.From)
+			}
+			if doc, err = pointerRemove(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			// copies must not share maps or slices with the original
+			data, _ := json.Marshal(value)
+			decodeJSON(data, &value)
+		}
+	}
+
+	switch op.Op {
+	case "add", "move", "copy":
+		return pointerAdd(doc, path, value, false)
+	case "replace":
+		return pointerAdd(doc, path, value, true)
+	case "remove":
+		return pointerRemove(doc, path)
+	case "test":
+		current, err := pointerGet(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(current, value) {
+			return nil, fmt.Errorf("test failed, value is %s", stringify(current, nil))
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// Splits an RFC 6901 JSON pointer into its reference tokens, "" points at the whole document
+func parsePointer(p string) ([]string, error) {
+	if p == "" {
+		return nil, nil
+	}
+	if p[0] != '/' {
+		return nil, fmt.Errorf("pointer %q doesn't start with /", p)
+	}
+	tokens := strings.Split(p[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// Returns the index token names in a, with "-" naming the end when end is set
+func arrayIndex(a []any, token string, end bool) (int, error) {
+	if token == "-" && end {
+		return len(a), nil
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || token != strconv.Itoa(i) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	limit := len(a)
+	if end {
+		limit++
+	}
+	if i >= limit {
+		return 0, fmt.Errorf("index %d out of range", i)
+	}
+	return i, nil
+}
+
+func pointerGet(doc any, path []string) (any, error) {
+	for _, t := range path {
+		switch n := doc.(type) {
+		case map[string]any:
+			v, ok := n[t]
+			if !ok {
+				return nil, fmt.Errorf("no member %q", t)
+			}
+			doc = v
+		case []any:
+			i, err := arrayIndex(n, t, false)
+			if err != nil {
+				return nil, err
+			}
+			doc = n[i]
+		default:
+			return nil, fmt.Errorf("%q is not in an object or array", t)
+		}
+	}
+	return doc, nil
+}
+
+// Adds value at path, inserting into arrays, or replaces the existing value at path when replace is set
+func pointerAdd(doc any, path []string, value any, replace bool) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	t := path[0]
+	switch n := doc.(type) {
+	case map[string]any:
+		if len(path) > 1 {
+			child, ok := n[t]
+			if !ok {
+				return nil, fmt.Errorf("no member %q", t)
+			}
+			v, err := pointerAdd(child, path[1:], value, replace)
+			n[t] = v
+			return n, err
+		}
+		if _, ok := n[t]; replace && !ok {
+			return nil, fmt.Errorf("no member %q", t)
+		}
+		n[t] = value
+		return n, nil
+	case []any:
+		i, err := arrayIndex(n, t, len(path) == 1 && !replace)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) > 1 || replace {
+			if len(path) == 1 {
+				n[i] = value
+				return n, nil
+			}
+			v, err := pointerAdd(n[i], path[1:], value, replace)
+			n[i] = v
+			return n, err
+		}
+		n = append(n, nil)
+		copy(n[i+1:], n[i:])
+		n[i] = value
+		return n, nil
+	}
+	return nil, fmt.Errorf("%q is not in an object or array", t)
+}
+
+func pointerRemove(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("can't remove the whole configuration")
+	}
+	t := path[0]
+	switch n := doc.(type) {
+	case map[string]any:
+		child, ok := n[t]
+		if !ok {
+			return nil, fmt.Errorf("no member %q", t)
+		}
+		if len(path) == 1 {
+			delete(n, t)
+			return n, nil
+		}
+		v, err := pointerRemove(child, path[1:])
+		n[t] = v
+		return n, err
+	case []any:
+		i, err := arrayIndex(n, t, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(path) == 1 {
+			return append(n[:i], n[i+1:]...), nil
+		}
+		v, err := pointerRemove(n[i], path[1:])
+		n[i] = v
+		return n, err
+	}
+	return nil, fmt.Errorf("%q is not in an object or array", t)
+}
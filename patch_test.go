@@ -0,0 +1,98 @@
+package configManager
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func patchSet() (*ConfigSet, *int64, *string, *bool) {
+	var c ConfigSet
+	port, _ := AddOptionToSet(&c, "server.port", int64(80), Max(9000))
+	host, _ := AddOptionToSet(&c, "server.host", "localhost")
+	debug, _ := AddOptionToSet(&c, "debug", false)
+	c.Set("debug", "true")
+	return &c, port, host, debug
+}
+
+func Test_applyJSONPatch(t *testing.T) {
+	c, port, host, debug := patchSet()
+
+	err := c.ApplyJSONPatch([]byte(`[
+		{"op": "test", "path": "/server/host", "value": "localhost"},
+		{"op": "replace", "path": "/server/port", "value": 8080},
+		{"op": "copy", "from": "/server/port", "path": "/server/port"},
+		{"op": "remove", "path": "/debug"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *port != 8080 || *host != "localhost" || *debug {
+		t.Fatalf("Patch not applied: %v %v %v", *port, *host, *debug)
+	}
+	if c.View().IsSet("debug") || !c.View().IsSet("server.port") {
+		t.Fatal("Removed option should be back to its default, replaced one set")
+	}
+
+	for _, tc := range []struct {
+		patch string
+		err   error
+	}{
+		{`[{"op": "test", "path": "/server/host", "value": "example.com"}, {"op": "replace", "path": "/server/port", "value": 1}]`, ErrPatch},
+		{`[{"op": "replace", "path": "/server/port", "value": 1}, {"op": "replace", "path": "/server/port", "value": 10000}]`, ErrRange},
+		{`[{"op": "replace", "path": "/server/port", "value": 1}, {"op": "replace", "path": "/missing", "value": 1}]`, ErrPatch},
+		{`[{"op": "replace", "path": "/server/port", "value": 1}, {"op": "frobnicate", "path": "/debug"}]`, ErrPatch},
+		{`{"op": "remove"}`, ErrPatch},
+	} {
+		if err := c.ApplyJSONPatch([]byte(tc.patch)); !errors.Is(err, tc.err) {
+			t.Fatalf("%s: expected %v, got %v", tc.patch, tc.err, err)
+		}
+		if *port != 8080 {
+			t.Fatalf("%s: failed patch left changes behind", tc.patch)
+		}
+	}
+
+	if err := c.ApplyJSONPatch([]byte(`[{"op": "add", "path": "/unknown", "value": 1}]`)); err == nil {
+		t.Fatal("Unknown option added")
+	}
+}
+
+func Test_applyMergePatch(t *testing.T) {
+	c, port, host, debug := patchSet()
+
+	events, stop := c.Events()
+	defer stop()
+
+	if err := c.ApplyMergePatch([]byte(`{"server": {"port": 8080}, "debug": null}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 8080 || *host != "localhost" || *debug {
+		t.Fatalf("Merge patch not applied: %v %v %v", *port, *host, *debug)
+	}
+	if e := <-events; e.Kind != Changed || len(e.Options) != 2 {
+		t.Fatalf("Expected 2 changed options, got %+v", e)
+	}
+
+	if err := c.ApplyMergePatch([]byte(`{"server": {"port": "many"}, "debug": true}`)); err == nil || *debug {
+		t.Fatal("Invalid merge patch partially applied")
+	}
+}
+
+func Test_patchLargeNumbers(t *testing.T) {
+	var c ConfigSet
+	c.SetCoercion(Strict)
+	id, _ := AddOptionToSet(&c, "id", int64(0))
+
+	if err := c.ApplyMergePatch([]byte(`{"id": 9223372036854775806}`)); err != nil || *id != math.MaxInt64-1 {
+		t.Fatalf("Merge patch rounded the value: %v %v", *id, err)
+	}
+	if err := c.ApplyJSONPatch([]byte(`[{"op": "replace", "path": "/id", "value": 9223372036854775805}]`)); err != nil || *id != math.MaxInt64-2 {
+		t.Fatalf("JSON patch rounded the value: %v %v", *id, err)
+	}
+	if err := c.ApplyJSONPatch([]byte(`[{"op": "test", "path": "/id", "value": 9223372036854775807}]`)); err == nil {
+		t.Fatal("Test matched a rounded value")
+	}
+	if err := c.ApplyMergePatch([]byte(`{"id": 1.5}`)); !errors.Is(err, ErrParse) {
+		t.Fatalf("Float accepted under Strict: %v", err)
+	}
+}
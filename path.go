@@ -0,0 +1,69 @@
+package configManager
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Expands a leading ~ to the user's home directory, then $VAR and ${VAR} to environment variables
+func expandPath(s string) string {
+	if s == "~" || strings.HasPrefix(s, "~/") || strings.HasPrefix(s, "~"+string(filepath.Separator)) {
+		if home, err := os.UserHomeDir(); err == nil {
+			s = home + s[1:]
+		}
+	}
+	return os.ExpandEnv(s)
+}
+
+// =-=-= pathValue
+
+type pathValue struct {
+	ptr *string
+	raw string // as written, kept so saved files still read ~/data
+}
+
+func newPathValue(p *string) *pathValue { return &pathValue{p, *p} }
+
+func (v *pathValue) Set(s string) error {
+	v.raw = s
+	*v.ptr = expandPath(s)
+	return nil
+}
+
+func (v pathValue) Get() any {
+	if v.ptr == nil {
+		return ""
+	}
+	return *v.ptr
+}
+
+func (v pathValue) String() string { return v.raw }
+
+// Paths are saved as written, unexpanded
+func (v pathValue) MarshalConfig() (any, error) { return v.raw, nil }
+
+// Defines a new path option on the set c, ~, $HOME and other environment variables are expanded when it's set
+// p holds the expanded path, the default is expanded as well
+func PathVarSet(c *ConfigSet, p *string, key, defaultValue string, constraints ...Constraint) error {
+	v := newPathValue(p)
+	v.Set(defaultValue)
+	return c.Var(v, key, constraints...)
+}
+
+// Defines a new path option on the set c, ~, $HOME and other environment variables are expanded when it's set
+func PathSet(c *ConfigSet, key, defaultValue string, constraints ...Constraint) (*string, error) {
+	p := new(string)
+	err := PathVarSet(c, p, key, defaultValue, constraints...)
+	return p, err
+}
+
+// Defines a new path option, ~, $HOME and other environment variables are expanded when it's set
+func PathVar(p *string, key, defaultValue string, constraints ...Constraint) error {
+	return PathVarSet(&globalConfig, p, key, defaultValue, constraints...)
+}
+
+// Defines a new path option, ~, $HOME and other environment variables are expanded when it's set
+func Path(key, defaultValue string, constraints ...Constraint) (*string, error) {
+	return PathSet(&globalConfig, key, defaultValue, constraints...)
+}
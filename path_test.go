@@ -0,0 +1,35 @@
+package configManager
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+func Test_pathOption(t *testing.T) {
+	t.Setenv("HOME", "/home/quoll")
+	t.Setenv("CM_TEST_APP", "app")
+
+	var c ConfigSet
+	data, _ := PathSet(&c, "data", "~/data")
+	cache, _ := PathSet(&c, "cache", "")
+	logs, _ := PathSet(&c, "logs", "")
+	if *data != filepath.Join("/home/quoll", "data") {
+		t.Fatalf("Default not expanded: [%v]", *data)
+	}
+
+	err := c.ParseFromData([]byte(`{"cache":"$HOME/.cache/${CM_TEST_APP}","logs":"/var/log/~x"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *cache != "/home/quoll/.cache/app" || *logs != "/var/log/~x" {
+		t.Fatalf("Paths not expanded: [%v] [%v]", *cache, *logs)
+	}
+
+	saved, _ := c.SaveTo()
+	var m map[string]any
+	json.Unmarshal(saved, &m)
+	if m["data"] != "~/data" || m["cache"] != "$HOME/.cache/${CM_TEST_APP}" {
+		t.Fatalf("Paths not saved as written: %v", m)
+	}
+}
@@ -0,0 +1,51 @@
+package configManager
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// How Save creates files, see ConfigSet.FileMode
+type writeMode struct {
+	file, dir fs.FileMode
+	restrict  bool // Existing files lose the permissions file doesn't grant
+}
+
+var defaultWriteMode = writeMode{file: 0644, dir: 0755}
+
+// Marks the named option as holding a credential
+// Save then creates files readable by their owner only and removes group and other permissions from existing ones,
+// unless FileMode and DirMode say otherwise
+func (c *ConfigSet) MarkSecret(name string) error {
+	if c.root != nil {
+		return c.root.MarkSecret(c.prefix + name)
+	}
+	o, ok := c.formal[name]
+	if !ok {
+		return fmt.Errorf("No such option: %v", name)
+	}
+	o.Secret = true
+	c.hasSecrets = true
+	return nil
+}
+
+// Returns the permissions Save writes with, the secure defaults apply once options are secret or encrypted
+func (c *ConfigSet) writeMode() writeMode {
+	if c.root != nil {
+		return c.root.writeMode()
+	}
+	mode := defaultWriteMode
+	if c.hasSecrets || len(c.encrypted) > 0 {
+		mode = writeMode{file: 0600, dir: 0700, restrict: true}
+	}
+	if c.FileMode != 0 {
+		mode.file = c.FileMode.Perm()
+	}
+	if c.DirMode != 0 {
+		mode.dir = c.DirMode.Perm()
+	}
+	return mode
+}
+
+// Marks the named option of the global set as holding a credential
+func MarkSecret(name string) error { return globalConfig.MarkSecret(name) }
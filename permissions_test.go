@@ -0,0 +1,74 @@
+package configManager
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_saveFileMode(t *testing.T) {
+	var mem memFS
+	var c ConfigSet
+	c.FS = &mem
+	c.Location = "app.json"
+	AddOptionToSet(&c, "name", "app")
+
+	c.Save()
+	if info, err := fs.Stat(&mem, "app.json"); err != nil || info.Mode().Perm() != 0644 {
+		t.Fatalf("Expected default permissions 0644: %v %v", info, err)
+	}
+
+	c.FileMode = 0640
+	c.Save()
+	if info, _ := fs.Stat(&mem, "app.json"); info.Mode().Perm() != 0640 {
+		t.Fatalf("FileMode ignored: %v", info.Mode())
+	}
+}
+
+func Test_saveSecretPermissions(t *testing.T) {
+	dir := t.TempDir()
+	var c ConfigSet
+	c.Location = filepath.Join(dir, "conf", "app.json")
+	AddOptionToSet(&c, "db.password", "hunter2")
+	if err := c.MarkSecret("db.password"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(c.Location); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("File holding secrets is readable by others: %v %v", info, err)
+	}
+	if info, err := os.Stat(filepath.Dir(c.Location)); err != nil || info.Mode().Perm() != 0700 {
+		t.Fatalf("Directory holding secrets is readable by others: %v %v", info, err)
+	}
+
+	os.Chmod(c.Location, 0644)
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(c.Location); info.Mode().Perm() != 0600 {
+		t.Fatalf("Existing file permissions not restricted: %v", info.Mode())
+	}
+
+	if err := c.MarkSecret("missing"); err == nil {
+		t.Fatal("Unknown option marked secret")
+	}
+}
+
+func Test_saveKeepsPermissions(t *testing.T) {
+	var c ConfigSet
+	c.Location = filepath.Join(t.TempDir(), "app.json")
+	AddOptionToSet(&c, "name", "app")
+	os.WriteFile(c.Location, []byte(`{}`), 0664)
+	os.Chmod(c.Location, 0664)
+
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(c.Location); info.Mode().Perm() != 0664 {
+		t.Fatalf("Existing permissions not kept: %v", info.Mode())
+	}
+}
@@ -0,0 +1,133 @@
+package configManager
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Returned when a policy rejects a configuration, wrapped by a *PolicyError listing the violations
+var ErrPolicy = errors.New("rejected by policy")
+
+// A rule a candidate configuration breaks
+type Violation struct {
+	Policy string // Name the policy was added under
+	Option string // Option at fault, empty when the rule concerns the configuration as a whole
+	Reason string
+}
+
+func (v Violation) String() string {
+	if v.Option == "" {
+		return fmt.Sprintf("%s: %s", v.Policy, v.Reason)
+	}
+	return fmt.Sprintf("%s: %s: %s", v.Policy, v.Option, v.Reason)
+}
+
+// Lists every violation of a rejected configuration
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	reasons := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		reasons[i] = v.String()
+	}
+	return fmt.Sprintf("%v: %s", ErrPolicy, strings.Join(reasons, "; "))
+}
+
+func (e *PolicyError) Unwrap() error { return ErrPolicy }
+
+// Checks a candidate configuration before it replaces the current one
+// An error means the policy couldn't be evaluated, the configuration is rejected then too
+type Policy interface {
+	Evaluate(candidate *Snapshot) ([]Violation, error)
+}
+
+// Adapts a function to a Policy
+type PolicyFunc func(candidate *Snapshot) ([]Violation, error)
+
+func (f PolicyFunc) Evaluate(candidate *Snapshot) ([]Violation, error) { return f(candidate) }
+
+type namedPolicy struct {
+	name string
+	Policy
+}
+
+// Adds a policy evaluated whenever Parse, ParseFromData, ParseWith, ParseGroup, a reload or a patch changes the configuration
+// The candidate is evaluated before it's published, if any policy reports a violation nothing changes and a *PolicyError is returned
+// Set and ParseArgs aren't checked
+func (c *ConfigSet) AddPolicy(name string, p Policy) {
+	if c.root != nil {
+		c.root.AddPolicy(name, p)
+		return
+	}
+	c.policies = append(c.policies, namedPolicy{name, p})
+}
+
+// Adds a policy to the global set
+func AddPolicy(name string, p Policy) { globalConfig.AddPolicy(name, p) }
+
+// Evaluates every policy against the configuration as it would be published
+func (c *ConfigSet) checkPolicies() error {
+	if len(c.policies) == 0 {
+		return nil
+	}
+	candidate := c.buildSnapshot()
+	var violations []Violation
+	for _, p := range c.policies {
+		vs, err := p.Evaluate(candidate)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrPolicy, p.name, err)
+		}
+		for _, v := range vs {
+			v.Policy = p.name
+			violations = append(violations, v)
+		}
+	}
+	if len(violations) > 0 {
+		return &PolicyError{violations}
+	}
+	return nil
+}
+
+// =-=-= Built in policies
+
+// Requires the named options to be set together or not at all, e.g. AllOrNone("tls", "tls_cert", "tls_key")
+// Checked like a policy added under group, the violation names the members that are missing
+func (c *ConfigSet) AllOrNone(group string, names ...string) error {
+	for _, name := range names {
+		if c.Lookup(name) == nil {
+			return fmt.Errorf("No such option: %v", name)
+		}
+	}
+	members := make(allOrNone, len(names))
+	for i, name := range names {
+		members[i] = c.prefix + name
+	}
+	c.AddPolicy(group, members)
+	return nil
+}
+
+// Requires the named options of the global set to be set together or not at all
+func AllOrNone(group string, names ...string) error { return globalConfig.AllOrNone(group, names...) }
+
+type allOrNone []string
+
+func (a allOrNone) Evaluate(s *Snapshot) ([]Violation, error) {
+	var set, missing []string
+	for _, name := range a {
+		if s.IsSet(name) {
+			set = append(set, name)
+		} else {
+			missing = append(missing, name)
+		}
+	}
+	if len(set) == 0 || len(missing) == 0 {
+		return nil, nil
+	}
+	return []Violation{{
+		Option: missing[0],
+		Reason: fmt.Sprintf("%s must be set together, missing %s", strings.Join(a, ", "), strings.Join(missing, ", ")),
+	}}, nil
+}
@@ -0,0 +1,144 @@
+package configManager
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Rejects production configurations without TLS
+func requireTLS(s *Snapshot) ([]Violation, error) {
+	env, _ := SnapshotGet[string](s, "env")
+	tls, _ := SnapshotGet[bool](s, "server.tls")
+	if env == "prod" && !tls {
+		return []Violation{{Option: "server.tls", Reason: "must be enabled in prod"}}, nil
+	}
+	return nil, nil
+}
+
+func Test_policy(t *testing.T) {
+	var c ConfigSet
+	env, _ := AddOptionToSet(&c, "env", "dev")
+	tls, _ := AddOptionToSet(&c, "server.tls", false)
+	c.AddPolicy("tls", PolicyFunc(requireTLS))
+
+	err := c.ParseFromData([]byte(`{"env": "prod"}`))
+	var pe *PolicyError
+	if !errors.Is(err, ErrPolicy) || !errors.As(err, &pe) || len(pe.Violations) != 1 {
+		t.Fatalf("Violation not reported: %v", err)
+	}
+	if v := pe.Violations[0]; v.Policy != "tls" || v.Option != "server.tls" {
+		t.Fatalf("Unexpected violation: %+v", v)
+	}
+	if !strings.Contains(err.Error(), "tls: server.tls: must be enabled in prod") {
+		t.Fatalf("Unexpected message: %v", err)
+	}
+	if *env != "dev" || c.View().IsSet("env") || c.View().String("env") != "dev" {
+		t.Fatalf("Rejected parse not undone: [%v]", *env)
+	}
+
+	if err := c.ParseFromData([]byte(`{"env": "prod", "server": {"tls": true}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *env != "prod" || !*tls {
+		t.Fatalf("Unexpected values: [%v] [%v]", *env, *tls)
+	}
+
+	if err := c.ApplyMergePatch([]byte(`{"server": {"tls": false}}`)); !errors.Is(err, ErrPolicy) || !*tls {
+		t.Fatalf("Patch not checked: [%v] %v", *tls, err)
+	}
+	if _, err := c.reloadData([]byte(`{"env": "prod"}`)); !errors.Is(err, ErrPolicy) || !*tls {
+		t.Fatalf("Reload not checked: [%v] %v", *tls, err)
+	}
+	if err := c.ParseGroup("server", []byte(`{"server.tls": false}`)); !errors.Is(err, ErrPolicy) || !*tls {
+		t.Fatalf("Group not checked: [%v] %v", *tls, err)
+	}
+}
+
+func Test_policyError(t *testing.T) {
+	var c ConfigSet
+	port, _ := AddOptionToSet(&c, "port", int64(80))
+	c.AddPolicy("remote", PolicyFunc(func(*Snapshot) ([]Violation, error) {
+		return nil, fmt.Errorf("unreachable")
+	}))
+
+	err := c.ParseFromData([]byte(`{"port": 8080}`))
+	if !errors.Is(err, ErrPolicy) || !strings.Contains(err.Error(), "remote: unreachable") || *port != 80 {
+		t.Fatalf("Failing policy didn't reject: [%v] %v", *port, err)
+	}
+}
+
+func Test_allOrNone(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "tls.cert", "")
+	AddOptionToSet(&c, "tls.key", "")
+	AddOptionToSet(&c, "tls.ca", "")
+	if err := c.AllOrNone("tls", "tls.cert", "tls.key", "tls.ca"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AllOrNone("missing", "tls.cert", "nope"); err == nil {
+		t.Fatal("Unknown option accepted")
+	}
+
+	if err := c.ParseFromData([]byte(`{}`)); err != nil {
+		t.Fatalf("Empty group rejected: %v", err)
+	}
+	err := c.ParseFromData([]byte(`{"tls": {"cert": "a.pem"}}`))
+	var pe *PolicyError
+	if !errors.As(err, &pe) || pe.Violations[0].Option != "tls.key" ||
+		!strings.Contains(err.Error(), "tls: tls.key: tls.cert, tls.key, tls.ca must be set together, missing tls.key, tls.ca") {
+		t.Fatalf("Partial group accepted: %v", err)
+	}
+	if err := c.ParseFromData([]byte(`{"tls": {"cert": "a.pem", "key": "a.key", "ca": "ca.pem"}}`)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_allOrNoneSub(t *testing.T) {
+	var c ConfigSet
+	s := c.Sub("db")
+	AddOptionToSet(s, "user", "")
+	AddOptionToSet(s, "password", "")
+	if err := s.AllOrNone("credentials", "user", "password"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFromData([]byte(`{"db": {"user": "admin"}}`)); !errors.Is(err, ErrPolicy) || !strings.Contains(err.Error(), "missing db.password") {
+		t.Fatalf("Partial group accepted: %v", err)
+	}
+}
+
+func Test_policyRejectedUnpublished(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "a", int64(1))
+	events, stop := c.Events()
+	defer stop()
+
+	var seen int64
+	c.AddPolicy("max", PolicyFunc(func(s *Snapshot) ([]Violation, error) {
+		seen, _ = SnapshotGet[int64](c.View(), "a")
+		if a, _ := SnapshotGet[int64](s, "a"); a > 3 {
+			return []Violation{{Option: "a", Reason: "too large"}}, nil
+		}
+		return nil, nil
+	}))
+
+	if err := c.ParseFromData([]byte(`{"a": 5}`)); !errors.Is(err, ErrPolicy) {
+		t.Fatalf("Violation not reported: %v", err)
+	}
+	if seen != 1 || c.View().String("a") != "1" {
+		t.Fatalf("Rejected value published: [%v] [%v]", seen, c.View().String("a"))
+	}
+	select {
+	case e := <-events:
+		t.Fatalf("Event sent for a rejected parse: %+v", e)
+	default:
+	}
+
+	if err := c.ParseFromData([]byte(`{"a": 2}`)); err != nil {
+		t.Fatal(err)
+	}
+	if e := <-events; e.Kind != Parsed || c.View().String("a") != "2" {
+		t.Fatalf("Accepted parse not published: %+v", e)
+	}
+}
@@ -0,0 +1,82 @@
+package configManager
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// =-=-= Profile
+
+type profileConstraint struct {
+	when        map[string]string
+	constraints []Constraint
+}
+
+// Replaces the option's other constraints while the set's selectors match when, e.g. a wider port range in dev
+//
+//	AddOption("port", 8080, Min(1024), Profile(map[string]string{"env": "dev"}, Min(1)))
+//
+// Selector values are patterns matched the same way as in conditional sections, the first matching profile wins
+// Profiles are picked when the option is defined and again whenever SetSelectors is called
+func Profile(when map[string]string, constraints ...Constraint) Constraint {
+	return profileConstraint{maps.Clone(when), constraints}
+}
+
+func (p profileConstraint) Check(v any) error { return checkConstraints(v, p.constraints) }
+
+func (p profileConstraint) String() string {
+	conds := make([]string, 0, len(p.when))
+	for _, k := range slices.Sorted(maps.Keys(p.when)) {
+		conds = append(conds, k+"="+p.when[k])
+	}
+	rules := make([]string, len(p.constraints))
+	for i, c := range p.constraints {
+		rules[i] = c.String()
+	}
+	return fmt.Sprintf("when %s: %s", strings.Join(conds, ","), strings.Join(rules, ", "))
+}
+
+func (p profileConstraint) matches(selectors map[string]string) bool {
+	when := make(map[string]any, len(p.when))
+	for k, v := range p.when {
+		when[k] = v
+	}
+	ok, err := sectionMatches(when, selectors)
+	return ok && err == nil
+}
+
+// Reports whether any of constraints is a Profile
+func hasProfiles(constraints []Constraint) bool {
+	return slices.ContainsFunc(constraints, func(c Constraint) bool {
+		_, ok := c.(profileConstraint)
+		return ok
+	})
+}
+
+// Returns the constraints of the first profile matching selectors, otherwise the ones outside profiles
+func activeConstraints(constraints []Constraint, selectors map[string]string) []Constraint {
+	var base []Constraint
+	for _, c := range constraints {
+		p, ok := c.(profileConstraint)
+		if !ok {
+			base = append(base, c)
+			continue
+		}
+		if p.matches(selectors) {
+			return p.constraints
+		}
+	}
+	return base
+}
+
+// Picks the active constraints of every option declared with profiles
+func (c *ConfigSet) activateProfiles() {
+	selectors := c.Selectors()
+	for _, o := range c.formal {
+		if o.declared != nil {
+			o.constraints = activeConstraints(o.declared, selectors)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package configManager
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_profiles(t *testing.T) {
+	var c ConfigSet
+	c.SetSelectors(map[string]string{"env": "prod"})
+	port, err := AddOptionToSet(&c, "port", int64(8080), Min(1024), Max(65535),
+		Profile(map[string]string{"env": "dev*"}, Min(1), Max(65535)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Set("port", "80"); !errors.Is(err, ErrRange) {
+		t.Fatalf("Base constraints not applied, err: %v", err)
+	}
+
+	c.SetSelectors(map[string]string{"env": "development"})
+	if err := c.Set("port", "80"); err != nil || *port != 80 {
+		t.Fatalf("Profile constraints not applied: [%v] %v", *port, err)
+	}
+	if cs := c.Lookup("port").Constraints(); len(cs) != 2 || cs[0].String() != Min(1).String() {
+		t.Fatalf("Unexpected active constraints: %v", cs)
+	}
+
+	if _, err := AddOptionToSet(&c, "workers", int64(0), Min(1), Profile(map[string]string{"env": "prod"}, Min(4))); err == nil {
+		t.Fatal("Default accepted outside base constraints")
+	}
+}
@@ -1,6 +1,7 @@
 package configManager
 
 import (
+	"errors"
 	"strconv"
 	"strings"
 	"testing"
@@ -165,3 +166,53 @@ func Test_float64RangeVal(t *testing.T) {
 }
 
 
+
+func Test_uint16RangeVal(t *testing.T) {
+	var n uint16
+
+	v := newUint16RangeValue(&n, 10, 20)
+
+	if err := valueTester(
+		v,
+		[]string{
+			"10",
+			"20",
+			"15",
+		},
+		[]string{
+			"9",
+			"21",
+			"-1",
+			"70000",
+		},
+		&n,
+		func(a string, b uint16) bool { return strconv.FormatUint(uint64(b), 10) == a },
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_portOption(t *testing.T) {
+	var c ConfigSet
+
+	if _, err := PortSet(&c, "bad", 0); !errors.Is(err, ErrRange) {
+		t.Fatalf("Option accepted port 0 as default: %v", err)
+	}
+
+	port, err := PortSet(&c, "server.port", 8080)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFromData([]byte(`{"server": {"port": 443}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 443 {
+		t.Fatalf("Option set to unexpected value, expected [443] got %v", *port)
+	}
+
+	var other ConfigSet
+	PortSet(&other, "server.port", 8080)
+	if err := other.ParseFromData([]byte(`{"server": {"port": 65536}}`)); err == nil {
+		t.Fatal("Option accepted port 65536")
+	}
+}
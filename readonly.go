@@ -0,0 +1,172 @@
+package configManager
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"syscall"
+)
+
+// Returned by Save and CanSave when the configuration can't be written because its location is read only
+var ErrReadOnlyLocation = errors.New("configuration location is read only")
+
+func isReadOnly(err error) bool {
+	return errors.Is(err, syscall.EROFS) || errors.Is(err, fs.ErrPermission)
+}
+
+// Writes data to loc, creating missing directories
+// The file is replaced atomically so a crash mid-write leaves either the old or the new configuration, never a truncated one
+// A symbolic link is followed and the file it points to replaced, an existing file keeps its permissions
+// unless mode restricts them, a new one gets mode's permissions filtered by the umask
+func writeConfig(loc string, data []byte, mode writeMode) error {
+	err := os.MkdirAll(path.Dir(loc), mode.dir)
+	if err != nil {
+		if isReadOnly(err) {
+			return fmt.Errorf("%w: %s: %v", ErrReadOnlyLocation, loc, err)
+		}
+		return fmt.Errorf("Could not save configuration: %v", err)
+	}
+
+	target := loc
+	if resolved, err := filepath.EvalSymlinks(loc); err == nil {
+		target = resolved
+	}
+
+	perm, exists := mode.file, false
+	if info, err := os.Stat(target); err == nil {
+		perm, exists = info.Mode().Perm(), true
+		if mode.restrict {
+			perm &= mode.file
+		}
+		// renaming would replace a file the user made read only, refuse like writing it would
+		f, err := os.OpenFile(target, os.O_WRONLY, 0)
+		if err != nil {
+			if isReadOnly(err) {
+				return fmt.Errorf("%w: %s: %v", ErrReadOnlyLocation, loc, err)
+			}
+			return err
+		}
+		f.Close()
+	}
+
+	err = replaceFile(target, data, perm, exists)
+	if isReadOnly(err) {
+		return fmt.Errorf("%w: %s: %v", ErrReadOnlyLocation, loc, err)
+	}
+	return err
+}
+
+// Writes data to a temporary file next to name, flushes it to disk and renames it over name
+// exact applies perm as is, otherwise the umask filters it like for any new file
+func replaceFile(name string, data []byte, perm fs.FileMode, exact bool) (err error) {
+	dir := filepath.Dir(name)
+	f, err := createTemp(dir, "."+filepath.Base(name)+".tmp-", perm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if err == nil && exact {
+		err = f.Chmod(perm)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(f.Name(), name); err != nil {
+		return err
+	}
+
+	// makes the rename itself durable, not every platform can sync a directory so failures are ignored
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// Creates a new file in dir whose name starts with prefix, unlike os.CreateTemp which always uses 0600
+// the file gets perm filtered by the umask
+func createTemp(dir, prefix string, perm fs.FileMode) (*os.File, error) {
+	for range 100 {
+		name := filepath.Join(dir, prefix+strconv.FormatUint(uint64(rand.Uint32()), 10))
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+		if !errors.Is(err, fs.ErrExist) {
+			return f, err
+		}
+	}
+	return nil, &fs.PathError{Op: "createtemp", Path: filepath.Join(dir, prefix+"*"), Err: fs.ErrExist}
+}
+
+// Checks loc can be written without modifying it
+func checkWritable(loc string) error {
+	if resolved, err := filepath.EvalSymlinks(loc); err == nil {
+		loc = resolved
+	}
+	if _, err := os.Stat(loc); err == nil {
+		f, err := os.OpenFile(loc, os.O_WRONLY, 0)
+		if err != nil {
+			if isReadOnly(err) {
+				return fmt.Errorf("%w: %s: %v", ErrReadOnlyLocation, loc, err)
+			}
+			return err
+		}
+		f.Close()
+	}
+
+	// Save creates a temporary file next to loc, or in the nearest existing directory when loc's is missing
+	dir := path.Dir(loc)
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := path.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	f, err := os.CreateTemp(dir, ".cansave-*")
+	if err != nil {
+		if isReadOnly(err) {
+			return fmt.Errorf("%w: %s: %v", ErrReadOnlyLocation, loc, err)
+		}
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// Reports whether Save would be able to write the configuration, without writing it
+// Returns nil if Location is writable, or if it's read only but OverlayLocation is writable
+// Errors wrap ErrReadOnlyLocation when the cause is a read only location
+func (c *ConfigSet) CanSave() error {
+	c = c.base()
+	if c.Location == "" {
+		return fmt.Errorf("No file location provided")
+	}
+	err := c.checkWritable(c.Location)
+	if errors.Is(err, ErrReadOnlyLocation) && c.OverlayLocation != "" {
+		return c.checkWritable(c.OverlayLocation)
+	}
+	return err
+}
+
+// Reports whether Save would be able to write the configuration, without writing it
+func CanSave() error { return globalConfig.CanSave() }
@@ -0,0 +1,109 @@
+package configManager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Returns a location that can't be written, skipping the test when there is none
+func readOnlyLocation(t *testing.T) string {
+	loc := "/sys/configManager-test/config.json"
+	if err := checkWritable(loc); !errors.Is(err, ErrReadOnlyLocation) {
+		t.Skip("no read only location available")
+	}
+	return loc
+}
+
+func Test_saveReadOnly(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "greeting", "hello")
+
+	c.Location = filepath.Join(t.TempDir(), "config.json")
+	if err := c.CanSave(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.Location = readOnlyLocation(t)
+	if err := c.CanSave(); !errors.Is(err, ErrReadOnlyLocation) {
+		t.Fatalf("CanSave did not report read only location, err: %v", err)
+	}
+	if err := c.Save(); !errors.Is(err, ErrReadOnlyLocation) {
+		t.Fatalf("Save did not report read only location, err: %v", err)
+	}
+
+	c.OverlayLocation = filepath.Join(t.TempDir(), "overlay", "config.json")
+	if err := c.CanSave(); err != nil {
+		t.Fatal(err)
+	}
+	c.Set("greeting", "howdy")
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(c.OverlayLocation); err != nil {
+		t.Fatal("Overlay was not written")
+	}
+}
+
+func Test_parseOverlay(t *testing.T) {
+	dir := t.TempDir()
+	c := ConfigSet{
+		Location:        filepath.Join(dir, "config.json"),
+		OverlayLocation: filepath.Join(dir, "overlay.json"),
+	}
+	greeting, _ := AddOptionToSet(&c, "greeting", "")
+	name, _ := AddOptionToSet(&c, "name", "")
+
+	os.WriteFile(c.Location, []byte(`{"greeting":"hello","name":"base"}`), 0644)
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *greeting != "hello" {
+		t.Fatal("Missing overlay prevented parse")
+	}
+
+	var d ConfigSet
+	d.Location, d.OverlayLocation = c.Location, c.OverlayLocation
+	greeting, _ = AddOptionToSet(&d, "greeting", "")
+	name, _ = AddOptionToSet(&d, "name", "")
+	os.WriteFile(d.OverlayLocation, []byte(`{"greeting":"howdy"}`), 0644)
+	if err := d.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *greeting != "howdy" || *name != "base" {
+		t.Fatalf("Overlay not applied over base: [%v] [%v]", *greeting, *name)
+	}
+}
+
+func Test_saveAtomic(t *testing.T) {
+	dir := t.TempDir()
+	real := filepath.Join(dir, "real.json")
+	os.WriteFile(real, []byte(`{"greeting": "old"}`), 0o600)
+	link := filepath.Join(dir, "config.json")
+	if err := os.Symlink(real, link); err != nil {
+		t.Skip("symbolic links not supported")
+	}
+
+	var c ConfigSet
+	c.Location = link
+	AddOptionToSet(&c, "greeting", "hello")
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatal("Symbolic link replaced by a file")
+	}
+	info, err := os.Stat(real)
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("Permissions not kept: %v %v", info.Mode(), err)
+	}
+	if data, _ := os.ReadFile(real); !strings.Contains(string(data), "hello") {
+		t.Fatalf("File not replaced: %s", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Fatalf("Temporary files left behind: %v", entries)
+	}
+}
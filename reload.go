@@ -0,0 +1,362 @@
+package configManager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Captures the state of every option, calling restore puts it back exactly
+func (c *ConfigSet) checkpoint() (restore func()) {
+	type saved struct {
+		value string
+		set   bool
+		at    time.Time
+	}
+	state := make(map[string]saved, len(c.formal))
+	for name, o := range c.formal {
+		_, set := c.actual[name]
+		state[name] = saved{o.Value.String(), set, c.setAt[name]}
+	}
+	locks, origins, contributions := maps.Clone(c.locks), maps.Clone(c.origins), maps.Clone(c.contributions)
+	schedules := maps.Clone(c.schedules)
+
+	return func() {
+		c.locks, c.origins, c.contributions = locks, origins, contributions
+		c.schedules = schedules
+		for name, s := range state {
+			o := c.formal[name]
+			o.Value.Set(s.value)
+			if s.set {
+				c.actual[name] = o
+				c.setAt[name] = s.at
+			} else {
+				delete(c.actual, name)
+				delete(c.setAt, name)
+			}
+		}
+		c.publish()
+	}
+}
+
+// Applies data as the complete configuration, all or nothing
+// Unlike ParseFromData options that are already set are overwritten, and options missing
+// from data go back to their default, if anything is rejected every option is restored
+// Returns the names of the options whose value changed
+func (c *ConfigSet) reloadData(data []byte) (changed []string, err error) {
+	d, raw, err := c.decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.reloadDecoded(d, raw, nil)
+}
+
+// Applies decoded data as the complete configuration, see reloadData
+// from names the source of each value, "data" when it's missing
+func (c *ConfigSet) reloadDecoded(d map[string]any, raw map[string]json.RawMessage, from map[string]string) (changed []string, err error) {
+	c.parsing = parsing{d, raw, true}
+	defer func() { c.parsing = parsing{} }()
+
+	before := c.snapshot()
+	restore := c.checkpoint()
+
+	for _, o := range c.sortOptions(c.formal) {
+		v, present := d[o.Name]
+
+		vs, ok := "", false
+		if present {
+			vs, ok, err = c.resolve(o, v, raw[o.Name])
+			if err != nil {
+				restore()
+				return nil, err
+			}
+		}
+
+		if !ok {
+			o.set(o.DefValue)
+			delete(c.actual, o.Name)
+			delete(c.setAt, o.Name)
+			if !present {
+				delete(c.schedules, o.Name)
+			}
+			continue
+		}
+
+		if err = c.setValue(o, vs); err != nil {
+			restore()
+			return nil, optionErr(o.Name, err)
+		}
+		source, ok := from[o.Name]
+		if !ok {
+			source = "data"
+		}
+		c.markSet(o, source)
+	}
+
+	if err = c.checkExperimental(); err != nil {
+		restore()
+		return nil, err
+	}
+	if err = c.applyOverrides(); err == nil {
+		err = c.checkPolicies()
+	}
+	if err != nil {
+		restore()
+		return nil, err
+	}
+
+	c.publish()
+	after := c.snapshot()
+	for _, name := range after.Names() {
+		prev, _ := before.Get(name)
+		cur, _ := after.Get(name)
+		if !c.optionEqual(name, prev, cur) {
+			changed = append(changed, name)
+		}
+	}
+	return changed, nil
+}
+
+// Re-reads every source and applies them all or nothing, see reloadData
+// Sources are merged the same way as by Parse, earlier ones take precedence unless a later one locks an option
+func (c *ConfigSet) reloadFile() ([]string, error) {
+	layers, err := c.layers()
+	if err != nil {
+		return nil, err
+	}
+
+	d := make(map[string]any)
+	raw := make(map[string]json.RawMessage)
+	from := make(map[string]string)
+	prev, prevContributions := c.locks, c.contributions
+	c.locks, c.contributions = nil, nil
+	fail := func(err error) ([]string, error) {
+		c.locks, c.contributions = prev, prevContributions
+		return nil, err
+	}
+	for _, l := range layers {
+		var ld map[string]any
+		var lraw map[string]json.RawMessage
+		if vs, ok := l.src.(ValueSource); ok {
+			ld, lraw, err = c.loadValues(context.Background(), l.name, vs)
+		} else {
+			var data []byte
+			data, err = l.load()
+			if err == nil && data == nil {
+				continue
+			}
+			if err == nil {
+				ld, lraw, err = c.decode(data)
+			}
+		}
+		if err == nil {
+			var locks []string
+			if locks, err = c.takeLocks(ld); l.canLock {
+				c.lock(l.name, locks, ld, &report{})
+			}
+		}
+		if err != nil {
+			return fail(err)
+		}
+		for k, v := range ld {
+			if c.formal[k] != nil {
+				c.contribute(k, l.name, v, lraw[k])
+			}
+			_, ok := d[k]
+			if by, locked := c.locks[k]; locked && by == l.name || !ok && !locked {
+				d[k] = v
+				raw[k] = lraw[k]
+				from[k] = l.name
+			}
+		}
+	}
+
+	changed, err := c.reloadDecoded(d, raw, from)
+	if err != nil {
+		return fail(err)
+	}
+	c.degraded = nil
+	return changed, nil
+}
+
+// Keeps an application's configuration up to date with its file
+// The zero value reloads on SIGHUP and polls the file for changes every two seconds
+// Sources implementing WatchableSource trigger a reload whenever they report a change
+type Reloader struct {
+	Signals  []os.Signal   // Signals that trigger a reload, SIGHUP if empty
+	Interval time.Duration // How often the file is checked for changes, 2s if zero, never if negative
+
+	OnError  func(error)           // Called when a reload is rejected, the previous configuration stays active
+	OnReload func(changed []string) // Called after a reload was applied
+}
+
+// Applies the current configuration and keeps reapplying it until ctx is done
+// Every reload goes through two phases: the file is parsed and validated into the set, all or nothing,
+// then apply receives the new snapshot, if apply fails the set is rolled back to the previous configuration
+// apply should leave the application untouched when it returns an error
+// Returns the error of the first apply, otherwise runs until ctx is done
+func (r *Reloader) Run(ctx context.Context, c *ConfigSet, apply func(*Snapshot) error) error {
+	// taken before the first apply so changes made while it runs are noticed
+	last := c.fileStamp()
+	if err := apply(c.View()); err != nil {
+		return err
+	}
+
+	watched := make(chan struct{}, 1)
+	for _, src := range c.Sources {
+		if w, ok := src.(WatchableSource); ok {
+			go w.Watch(ctx, func() {
+				select {
+				case watched <- struct{}{}:
+				default:
+				}
+			})
+		}
+	}
+
+	signals := r.Signals
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, signals...)
+	defer signal.Stop(sig)
+
+	interval := r.Interval
+	if interval == 0 {
+		interval = 2 * time.Second
+	}
+	var tick <-chan time.Time
+	if interval > 0 {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		tick = t.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sig:
+		case <-watched:
+		case <-tick:
+			stamp := c.fileStamp()
+			if stamp == last {
+				continue
+			}
+		}
+		last = c.fileStamp()
+		r.reload(c, apply)
+	}
+}
+
+func (r *Reloader) reload(c *ConfigSet, apply func(*Snapshot) error) {
+	c.mu.Lock()
+	restore := c.checkpoint()
+	changed, err := c.reloadFile()
+	c.mu.Unlock()
+
+	// apply runs unlocked so it may call Set or Save
+	if err == nil && len(changed) > 0 {
+		if err = apply(c.View()); err != nil {
+			c.mu.Lock()
+			restore()
+			c.mu.Unlock()
+			err = fmt.Errorf("configuration rejected by application: %w", err)
+		}
+	}
+
+	if err != nil {
+		c.emit(ReloadFailed, c.Location, err)
+		if r.OnError != nil {
+			r.OnError(err)
+		}
+		return
+	}
+	if len(changed) > 0 {
+		c.emit(Changed, c.Location, nil, changed...)
+	}
+	if r.OnReload != nil && len(changed) > 0 {
+		r.OnReload(changed)
+	}
+}
+
+// Identifies a version of the files read by Parse, by content since timestamps may be too coarse to notice quick edits
+// Only file sources are polled, other sources report changes through Watch
+type stamp struct {
+	size int64
+	sum  uint64
+}
+
+func (c *ConfigSet) fileStamp() stamp {
+	layers, err := c.layers()
+	if err != nil {
+		return stamp{-1, 0}
+	}
+
+	h := fnv.New64a()
+	var size int64
+	for _, l := range layers {
+		if _, ok := l.src.(FileSource); !ok {
+			continue
+		}
+		data, err := l.src.Load()
+		if err != nil {
+			data = nil
+		}
+		// separates files so moving content between them is noticed
+		h.Write([]byte{0})
+		h.Write(data)
+		size += int64(len(data))
+	}
+	return stamp{size, h.Sum64()}
+}
+
+// Reloads the configuration whenever one of signals arrives, SIGHUP if none are given, until stop is called
+// Every source is read again and applied all or nothing like a Reloader does, rejected reloads are passed to onError when it's not nil
+// The handler is installed before returning so a signal sent right after is never missed
+func (c *ConfigSet) ReloadOnSignal(onError func(error), signals ...os.Signal) (stop func()) {
+	c = c.base()
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, signals...)
+
+	r := Reloader{OnError: onError}
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-sig:
+				r.reload(c, func(*Snapshot) error { return nil })
+			}
+		}
+	}()
+
+	return sync.OnceFunc(func() {
+		signal.Stop(sig)
+		close(done)
+	})
+}
+
+// Reloads the global set whenever one of signals arrives, SIGHUP if none are given, until stop is called
+func ReloadOnSignal(onError func(error), signals ...os.Signal) (stop func()) {
+	return globalConfig.ReloadOnSignal(onError, signals...)
+}
+
+// Runs a default Reloader, applying the configuration now and after every SIGHUP or file change
+// Rejected reloads are dropped, use a Reloader with OnError to observe them
+func RunWithReload(ctx context.Context, c *ConfigSet, apply func(*Snapshot) error) error {
+	var r Reloader
+	return r.Run(ctx, c, apply)
+}
@@ -0,0 +1,132 @@
+package configManager
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func Test_reloadData(t *testing.T) {
+	var c ConfigSet
+	greeting, _ := AddOptionToSet(&c, "greeting", "hello")
+	repeats, _ := AddOptionToSet(&c, "repeats", int64(1), Max(10))
+	c.ParseFromData([]byte(`{"greeting":"howdy","repeats":2}`))
+
+	changed, err := c.reloadData([]byte(`{"repeats":3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 2 || *greeting != "hello" || *repeats != 3 {
+		t.Fatalf("Unexpected reload result: %v [%v] [%v]", changed, *greeting, *repeats)
+	}
+
+	if _, err := c.reloadData([]byte(`{"greeting":"hi","repeats":11}`)); !errors.Is(err, ErrRange) {
+		t.Fatalf("Reload accepted invalid value, err: %v", err)
+	}
+	if *greeting != "hello" || *repeats != 3 || !c.View().IsSet("repeats") {
+		t.Fatalf("Rejected reload was not rolled back: [%v] [%v]", *greeting, *repeats)
+	}
+}
+
+func Test_runWithReload(t *testing.T) {
+	loc := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(loc, []byte(`{"workers":1}`), 0644)
+
+	c := ConfigSet{Location: loc}
+	AddOptionToSet(&c, "workers", int64(0))
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	applied := make(chan int64, 4)
+	rejected := make(chan error, 4)
+	r := Reloader{
+		Interval: 5 * time.Millisecond,
+		OnError:  func(err error) { rejected <- err },
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx, &c, func(s *Snapshot) error {
+		n, _ := SnapshotGet[int64](s, "workers")
+		if n > 100 {
+			return errors.New("too many workers")
+		}
+		applied <- n
+		return nil
+	})
+
+	if n := <-applied; n != 1 {
+		t.Fatalf("Initial apply mismatch, expected: [1] received: [%v]", n)
+	}
+
+	os.WriteFile(loc, []byte(`{"workers":4}`), 0644)
+	select {
+	case n := <-applied:
+		if n != 4 {
+			t.Fatalf("Reloaded value mismatch, expected: [4] received: [%v]", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("File change not reloaded")
+	}
+
+	os.WriteFile(loc, []byte(`{"workers":400}`), 0644)
+	select {
+	case <-rejected:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Rejected configuration not reported")
+	}
+	if v, _ := SnapshotGet[int64](c.View(), "workers"); v != 4 {
+		t.Fatalf("Rejected configuration was not rolled back, received: [%v]", v)
+	}
+}
+
+func Test_reloadOnSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no SIGHUP on windows")
+	}
+	var mem memFS
+	mem.WriteFile("app.json", []byte(`{"port": 80}`), 0o644)
+
+	var c ConfigSet
+	c.FS = &mem
+	c.Location = "app.json"
+	AddOptionToSet(&c, "port", int64(1), Min(1))
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+
+	failed := make(chan error, 1)
+	events, unsubscribe := c.Events()
+	defer unsubscribe()
+	stop := c.ReloadOnSignal(func(err error) { failed <- err }, syscall.SIGHUP)
+	defer stop()
+
+	self, _ := os.FindProcess(os.Getpid())
+	mem.WriteFile("app.json", []byte(`{"port": 8080}`), 0o644)
+	self.Signal(syscall.SIGHUP)
+	select {
+	case e := <-events:
+		if e.Kind != Changed || c.View().String("port") != "8080" {
+			t.Fatalf("Unexpected reload: %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Configuration not reloaded on signal")
+	}
+
+	mem.WriteFile("app.json", []byte(`{"port": 0}`), 0o644)
+	self.Signal(syscall.SIGHUP)
+	select {
+	case err := <-failed:
+		if !errors.Is(err, ErrRange) || c.View().String("port") != "8080" {
+			t.Fatalf("Rejected reload not rolled back: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Rejected reload not reported")
+	}
+}
@@ -0,0 +1,266 @@
+package configManager
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// A problem found while parsing, reported to the set's output
+type problem struct {
+	option  string // empty when the problem concerns the whole source
+	err     error
+	warning bool
+	hint    string // suggests a fix, may be empty
+}
+
+// Problems found in one source
+type report struct {
+	source   string
+	problems []problem
+}
+
+func (r *report) error(option string, err error, hint string) {
+	r.problems = append(r.problems, problem{option, err, false, hint})
+}
+
+func (r *report) warning(option string, err error, hint string) {
+	r.problems = append(r.problems, problem{option, err, true, hint})
+}
+
+// Sets the writer Parse reports problems to, grouped by source with the options involved and suggested fixes
+// Problems are still returned as errors, reports only add detail, nil discards them
+func (c *ConfigSet) SetOutput(w io.Writer) { c.base().output = w }
+
+// Returns the writer problems are reported to, nil if they are discarded
+func (c *ConfigSet) Output() io.Writer { return c.base().output }
+
+// A problem found while parsing, see ParseReport
+type Problem struct {
+	Source  string // File or source the problem was found in
+	Option  string // Empty when the problem concerns the whole source
+	Err     error
+	Warning bool   // Whether parsing carried on despite the problem
+	Hint    string // Suggests a fix, may be empty
+}
+
+func (p Problem) String() string {
+	s := p.Source + ": "
+	if p.Option != "" {
+		s += p.Option + ": "
+	}
+	s += problemMessage(p.Option, p.Err)
+	if p.Hint != "" {
+		s += ", " + p.Hint
+	}
+	return s
+}
+
+// Returns every problem found by the last Parse, ParseFromData, ParseWith or ParseGroup, including those
+// SetReportLimits kept from the output
+func (c *ConfigSet) ParseReport() []Problem { return slices.Clone(c.base().problems) }
+
+// Limits what Parse writes to the output so a file with hundreds of problems keeps the logs readable
+// At most perKind problems of one kind, such as unknown options, are listed per source and the rest are counted,
+// zero lists 10 and a negative number lists them all
+// A problem already written within window isn't written again, e.g. when the file is parsed again, zero writes it every time
+// ParseReport still returns every problem
+func (c *ConfigSet) SetReportLimits(perKind int, window time.Duration) {
+	c = c.base()
+	c.reportLimit = perKind
+	c.reportWindow = window
+}
+
+// Identifies a problem across reports, see SetReportLimits
+type problemKey struct {
+	source, option, msg string
+	warning             bool
+}
+
+// When each problem was last written to the output
+type reportLog map[problemKey]time.Time
+
+// Reports whether the problem was written within window, recording it as written now otherwise
+func (l reportLog) seen(k problemKey, now time.Time, window time.Duration) bool {
+	if at, ok := l[k]; ok && now.Sub(at) < window {
+		return true
+	}
+	l[k] = now
+	return false
+}
+
+// Writes the problems of r to the output, errors first
+// Beyond the limit of problems of one kind a line counts the rest
+//
+//	config.json: 1 error, 12 warnings
+//	  error   workers: value outside allowed range, the default is "1"
+//	  warning greting: unknown option, did you mean "greeting"?
+//	  ...
+//	  warning 2 more: unknown option
+func (c *ConfigSet) writeReport(r *report) {
+	for _, p := range r.problems {
+		c.problems = append(c.problems, Problem{r.source, p.option, p.err, p.warning, p.hint})
+	}
+	if c.output == nil || len(r.problems) == 0 {
+		return
+	}
+
+	slices.SortStableFunc(r.problems, func(a, b problem) int {
+		if a.warning != b.warning {
+			if a.warning {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a.option, b.option)
+	})
+
+	if c.reportWindow > 0 {
+		if c.reported == nil {
+			c.reported = make(reportLog)
+		}
+		now := c.now()
+		r.problems = slices.DeleteFunc(r.problems, func(p problem) bool {
+			return c.reported.seen(problemKey{r.source, p.option, problemMessage(p.option, p.err), p.warning}, now, c.reportWindow)
+		})
+		if len(r.problems) == 0 {
+			return
+		}
+	}
+
+	limit := c.reportLimit
+	if limit == 0 {
+		limit = 10
+	}
+
+	var errs, warns int
+	for _, p := range r.problems {
+		if p.warning {
+			warns++
+		} else {
+			errs++
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s, %s\n", r.source, plural(errs, "error"), plural(warns, "warning"))
+	// problems of one kind share their error message
+	type category struct {
+		warning bool
+		msg     string
+	}
+	listed := make(map[category]int)
+	var summarized []category
+	for _, p := range r.problems {
+		cat := category{p.warning, problemCause(p.option, p.err).Error()}
+		if listed[cat]++; limit > 0 && listed[cat] > limit {
+			if listed[cat] == limit+1 {
+				summarized = append(summarized, cat)
+			}
+			continue
+		}
+		b.WriteString("  " + problemKind(p.warning) + " ")
+		if p.option != "" {
+			b.WriteString(p.option + ": ")
+		}
+		b.WriteString(problemMessage(p.option, p.err))
+		if p.hint != "" {
+			b.WriteString(", " + p.hint)
+		}
+		b.WriteByte('\n')
+	}
+	for _, cat := range summarized {
+		fmt.Fprintf(&b, "  %s %d more: %s\n", problemKind(cat.warning), listed[cat]-limit, cat.msg)
+	}
+	io.WriteString(c.output, b.String())
+}
+
+// Labels a problem in reports, padded so messages line up
+func problemKind(warning bool) string {
+	if warning {
+		return "warning"
+	}
+	return "error  "
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+// Warns about keys of d that don't name an option, suggesting the closest option name
+func (c *ConfigSet) reportUnknown(r *report, d map[string]any) {
+	for key := range d {
+		if _, ok := c.formal[key]; ok {
+			continue
+		}
+		hint := ""
+		if s := c.closestOption(key); s != "" {
+			hint = fmt.Sprintf("did you mean %q?", s)
+		}
+		r.warning(key, fmt.Errorf("unknown option"), hint)
+	}
+}
+
+// Returns the option name closest to name, empty if none is close enough to be a typo
+func (c *ConfigSet) closestOption(name string) string {
+	best, bestDist := "", len(name)/3+1
+	for _, o := range c.sortOptions(c.formal) {
+		if d := editDistance(name, o.Name); d < bestDist {
+			best, bestDist = o.Name, d
+		}
+	}
+	return best
+}
+
+// Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// Sets the writer Parse of the global set reports problems to
+func SetOutput(w io.Writer) { globalConfig.SetOutput(w) }
+
+// Limits what Parse of the global set writes to the output
+func SetReportLimits(perKind int, window time.Duration) {
+	globalConfig.SetReportLimits(perKind, window)
+}
+
+// Returns every problem found by the last parse of the global set
+func ParseReport() []Problem { return globalConfig.ParseReport() }
+
+// Describes err as reported for option, without repeating the option's name
+func problemMessage(option string, err error) string {
+	if oe, ok := err.(*OptionError); ok && option != "" && oe.Name == option {
+		return fmt.Sprintf("invalid value %q: %v", oe.Raw, oe.Err)
+	}
+	return err.Error()
+}
+
+// Returns why option rejected its value, problems sharing a cause are summarized together
+func problemCause(option string, err error) error {
+	if oe, ok := err.(*OptionError); ok && option != "" && oe.Name == option {
+		return oe.Err
+	}
+	return err
+}
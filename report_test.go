@@ -0,0 +1,97 @@
+package configManager
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_reportOutput(t *testing.T) {
+	var mem memFS
+	mem.WriteFile("config.json", []byte(`{"greting":"hi","workers":11,"ratio":"half"}`), 0666)
+
+	var out strings.Builder
+	c := ConfigSet{FS: &mem, Location: "config.json", Audit: &FileAudit{}}
+	c.SetOutput(&out)
+	AddOptionToSet(&c, "greeting", "hello")
+	AddOptionToSet(&c, "workers", int64(1), Max(10))
+	AddOptionToSet(&c, "ratio", 0.5)
+
+	if err := c.Parse(); !errors.Is(err, ErrParse) && !errors.Is(err, ErrRange) {
+		t.Fatalf("Parse did not return the problem, err: %v", err)
+	}
+
+	want := []string{
+		"config.json: 0 errors, 1 warning",
+		"  warning insecure configuration file: config.json is world writable",
+		"config.json: 2 errors, 1 warning",
+		`  error   ratio: invalid value "half": parse error, the default is "0.5"`,
+		`  error   workers: `,
+		`  warning greting: unknown option, did you mean "greeting"?`,
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("Unexpected report:\n%s", out.String())
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(lines[i], w) {
+			t.Fatalf("Report line %d mismatch, expected: [%s] received: [%s]", i, w, lines[i])
+		}
+	}
+
+	var quiet ConfigSet
+	AddOptionToSet(&quiet, "workers", int64(1))
+	quiet.ParseFromData([]byte(`{"workers":"many"}`))
+	if quiet.Output() != nil {
+		t.Fatal("Output set without SetOutput")
+	}
+}
+
+func Test_reportLimits(t *testing.T) {
+	data := `{"workers": 1`
+	for i := range 25 {
+		data += fmt.Sprintf(`, "unknown%d": 1`, i)
+	}
+	data += "}"
+
+	var out strings.Builder
+	var c ConfigSet
+	c.SetOutput(&out)
+	AddOptionToSet(&c, "workers", int64(1))
+
+	if err := c.ParseFromData([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out.String(), "unknown option"); n != 11 {
+		t.Fatalf("Expected 10 listed warnings and a summary, got %d:\n%s", n, out.String())
+	}
+	if !strings.Contains(out.String(), "  warning 15 more: unknown option\n") {
+		t.Fatalf("Missing summary:\n%s", out.String())
+	}
+	if n := len(c.ParseReport()); n != 25 {
+		t.Fatalf("ParseReport should keep every problem, got %d", n)
+	}
+
+	now := time.Now()
+	c.clock = func() time.Time { return now }
+	c.SetReportLimits(-1, time.Minute)
+	out.Reset()
+	c.ParseFromData([]byte(data))
+	if n := strings.Count(out.String(), "unknown option"); n != 25 {
+		t.Fatalf("Negative limit should list every problem, got %d", n)
+	}
+
+	out.Reset()
+	c.ParseFromData([]byte(data))
+	if out.Len() != 0 || len(c.ParseReport()) != 25 {
+		t.Fatalf("Problems written again within the window:\n%s", out.String())
+	}
+
+	now = now.Add(time.Minute)
+	c.ParseFromData([]byte(data))
+	if out.Len() == 0 {
+		t.Fatal("Problems not written again after the window")
+	}
+}
@@ -0,0 +1,81 @@
+package configManager
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+)
+
+// Keys of a rollout wrapper, used to canary a new value on a percentage of instances
+//
+//	"workers": {"value": 32, "rollout_percent": 10, "fallback": 16}
+//
+// An instance is in the rollout when a stable hash of its instance ID and the option name
+// falls below the percentage, so the same instances keep the new value across reloads
+// and raising the percentage only adds instances
+// Instances outside the rollout use "fallback" if present, otherwise the option is left unset
+// rollout_salt may be given to reshuffle which instances are picked for a new rollout
+const (
+	RolloutValueKey    = "value"
+	RolloutPercentKey  = "rollout_percent"
+	RolloutSaltKey     = "rollout_salt"
+	RolloutFallbackKey = "fallback"
+)
+
+// Sets the stable identifier used to bucket this instance in rollouts
+// If not set the machine's hostname is used
+func (c *ConfigSet) SetInstanceID(id string) { c.base().instanceID = id }
+
+// Returns the identifier used to bucket this instance in rollouts
+func (c *ConfigSet) InstanceID() string {
+	c = c.base()
+	if c.instanceID != "" {
+		return c.instanceID
+	}
+	h, _ := os.Hostname()
+	return h
+}
+
+// Returns this instance's bucket, in [0, 100), for a rollout of the named option
+func (c *ConfigSet) RolloutBucket(name, salt string) int {
+	h := fnv.New32a()
+	h.Write([]byte(c.InstanceID()))
+	h.Write([]byte{0})
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(salt))
+	return int(h.Sum32() % 100)
+}
+
+// Resolves a rollout wrapper into the value this instance should use and its original text, text is raw's
+// rolled is false when raw isn't a rollout wrapper, ok is false when the option should be left unset
+func (c *ConfigSet) resolveRollout(name string, raw any, text json.RawMessage) (v any, vText json.RawMessage, rolled, ok bool, err error) {
+	m, isMap := raw.(map[string]any)
+	if !isMap {
+		return raw, text, false, true, nil
+	}
+	pct, hasPct := m[RolloutPercentKey]
+	val, hasVal := m[RolloutValueKey]
+	if !hasPct || !hasVal {
+		return raw, text, false, true, nil
+	}
+
+	percent, err := strconv.ParseFloat(fmt.Sprint(pct), 64)
+	if err != nil || percent < 0 || percent > 100 {
+		return nil, nil, true, false, fmt.Errorf("%w: %s must be a number between 0 and 100, got %v", ErrRange, RolloutPercentKey, pct)
+	}
+
+	salt := ""
+	if s, ok := m[RolloutSaltKey]; ok {
+		salt = fmt.Sprint(s)
+	}
+
+	if float64(c.RolloutBucket(name, salt)) < percent {
+		return val, rawMember(text, RolloutValueKey), true, true, nil
+	}
+
+	fb, ok := m[RolloutFallbackKey]
+	return fb, rawMember(text, RolloutFallbackKey), true, ok, nil
+}
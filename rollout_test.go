@@ -0,0 +1,64 @@
+package configManager
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_rollout(t *testing.T) {
+	toParse := `{"workers": {"value": 32, "rollout_percent": 25, "fallback": 16}}`
+
+	in := 0
+	for i := range 400 {
+		var c ConfigSet
+		c.SetInstanceID(fmt.Sprintf("host-%d", i))
+		workers, _ := AddOptionToSet(&c, "workers", int64(1))
+
+		if err := c.ParseFromData([]byte(toParse)); err != nil {
+			t.Fatal(err)
+		}
+
+		switch *workers {
+		case 32:
+			in++
+		case 16:
+		default:
+			t.Fatalf("Unexpected value %v", *workers)
+		}
+
+		inBucket := c.RolloutBucket("workers", "") < 25
+		if inBucket != (*workers == 32) {
+			t.Fatalf("Instance %d value does not match its bucket", i)
+		}
+	}
+
+	if in < 50 || in > 150 {
+		t.Fatalf("Rollout reached %d of 400 instances, expected about 100", in)
+	}
+}
+
+func Test_rolloutInvalidPercent(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "workers", int64(1))
+
+	if err := c.ParseFromData([]byte(`{"workers": {"value": 2, "rollout_percent": 150}}`)); err == nil {
+		t.Fatal("Parse accepted out of range rollout percentage")
+	}
+}
+
+func Test_rolloutNumberText(t *testing.T) {
+	toParse := `{
+		"limit": {"value": 1000000, "rollout_percent": 100},
+		"fee": {"value": 0.50, "rollout_percent": 0, "fallback": 0.10}
+	}`
+
+	var c ConfigSet
+	limit, _ := AddOptionToSet(&c, "limit", 1)
+	fee, _ := AddOptionToSet(&c, "fee", Decimal("0"))
+	if err := c.ParseFromData([]byte(toParse)); err != nil {
+		t.Fatal(err)
+	}
+	if *limit != 1000000 || *fee != "0.10" {
+		t.Fatalf("Unexpected rolled out values: %v %v", *limit, *fee)
+	}
+}
@@ -0,0 +1,93 @@
+package configManager
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// Layout of saved files, see ConfigSet.SaveOptions
+// The zero value writes two space indentation with keys in lexicographical order
+//
+//	c.SaveOptions = SaveOptions{Indent: "\t", Order: DeclaredKeys}
+type SaveOptions struct {
+	Indent  string   // Indentation of nested values in JSON, JSONC and XML, two spaces when empty
+	Compact bool     // Writes JSON, JSONC and XML on a single line, Indent is ignored
+	Order   KeyOrder // Order of keys in JSON and JSONC objects
+}
+
+// Order of keys in saved files, see SaveOptions
+type KeyOrder uint8
+
+const (
+	SortedKeys   KeyOrder = iota // Lexicographical order, the default
+	DeclaredKeys                 // Order options were defined in, a namespace goes where its first option was defined
+)
+
+// Returns the indentation of nested values, empty when compact
+func (s SaveOptions) indent() string {
+	switch {
+	case s.Compact:
+		return ""
+	case s.Indent == "":
+		return "  "
+	}
+	return s.Indent
+}
+
+// Returns the JSON marshaller following SaveOptions
+func (c *ConfigSet) jsonMarshaller() func(v any) ([]byte, error) {
+	style := c.SaveOptions
+	return func(v any) ([]byte, error) {
+		if style.Order == DeclaredKeys {
+			if d, ok := v.(map[string]any); ok {
+				v = c.declaredOrder(d, "", c.optionRanks())
+			}
+		}
+		if style.Compact {
+			return json.Marshal(v)
+		}
+		return json.MarshalIndent(v, "", style.indent())
+	}
+}
+
+// Returns the position of every option and namespace in definition order
+// A namespace ranks as its first defined option
+func (c *ConfigSet) optionRanks() map[string]int {
+	ranks := make(map[string]int, len(c.formal))
+	for name, o := range c.formal {
+		for i := range name {
+			if name[i] != NamespaceSeparator[0] {
+				continue
+			}
+			if r, ok := ranks[name[:i]]; !ok || o.order < r {
+				ranks[name[:i]] = o.order
+			}
+		}
+		if r, ok := ranks[name]; !ok || o.order < r {
+			ranks[name] = o.order
+		}
+	}
+	return ranks
+}
+
+// Turns d, the object of namespace prefix, and the namespaces nested in it into OrderedMaps following ranks
+// Maps that are an option's value are kept as they are
+func (c *ConfigSet) declaredOrder(d map[string]any, prefix string, ranks map[string]int) OrderedMap {
+	m := make(OrderedMap, 0, len(d))
+	for k, v := range d {
+		name := prefix + k
+		if nested, ok := v.(map[string]any); ok && c.formal[name] == nil {
+			v = c.declaredOrder(nested, name+NamespaceSeparator, ranks)
+		}
+		m = append(m, MapEntry{k, v})
+	}
+	slices.SortFunc(m, func(a, b MapEntry) int {
+		ra, rb := ranks[prefix+a.Key], ranks[prefix+b.Key]
+		if ra != rb {
+			return ra - rb
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return m
+}
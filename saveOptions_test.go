@@ -0,0 +1,36 @@
+package configManager
+
+import "testing"
+
+func Test_saveOptions(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "name", "api")
+	AddOptionToSet(&c, "server.port", int64(80))
+	AddOptionToSet(&c, "debug", false)
+	AddOptionToSet(&c, "server.host", "localhost")
+
+	for _, tc := range []struct {
+		opts SaveOptions
+		want string
+	}{
+		{SaveOptions{}, "{\n  \"debug\": false,\n  \"name\": \"api\",\n  \"server\": {\n    \"host\": \"localhost\",\n    \"port\": 80\n  }\n}"},
+		{SaveOptions{Indent: "\t"}, "{\n\t\"debug\": false,\n\t\"name\": \"api\",\n\t\"server\": {\n\t\t\"host\": \"localhost\",\n\t\t\"port\": 80\n\t}\n}"},
+		{SaveOptions{Compact: true}, `{"debug":false,"name":"api","server":{"host":"localhost","port":80}}`},
+		{SaveOptions{Compact: true, Order: DeclaredKeys}, `{"name":"api","server":{"port":80,"host":"localhost"},"debug":false}`},
+	} {
+		c.SaveOptions = tc.opts
+		data, err := c.SaveTo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tc.want {
+			t.Fatalf("%+v: unexpected output:\n%s", tc.opts, data)
+		}
+	}
+
+	x := ConfigSet{Format: XML, SaveOptions: SaveOptions{Compact: true}}
+	AddOptionToSet(&x, "name", "api")
+	if data, _ := x.SaveTo(); string(data) != "<config><name>api</name></config>" {
+		t.Fatalf("Compact XML: %s", data)
+	}
+}
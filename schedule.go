@@ -0,0 +1,208 @@
+package configManager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Keys of a scheduled value wrapper
+// An option's value may be given as a wrapper, or a list of wrappers, instead of a plain value
+//
+//	"banner": [
+//	  {"value": "Maintenance tonight", "valid_from": "2026-10-15T18:00:00Z", "valid_until": "2026-10-16T02:00:00Z"},
+//	  {"value": "Welcome!"}
+//	]
+//
+// The first wrapper whose window contains the current time is the active value, times are RFC 3339
+// A missing bound leaves that side of the window open, when no wrapper is active the default is used
+const (
+	ScheduleValueKey = "value"
+	ScheduleFromKey  = "valid_from"
+	ScheduleUntilKey = "valid_until"
+)
+
+type scheduledValue struct {
+	from, until time.Time // zero means unbounded
+	raw         any
+	text        json.RawMessage // Original text of raw, nil when unknown
+}
+
+func (s scheduledValue) activeAt(t time.Time) bool {
+	if !s.from.IsZero() && t.Before(s.from) {
+		return false
+	}
+	if !s.until.IsZero() && !t.Before(s.until) {
+		return false
+	}
+	return true
+}
+
+// Returns the current time, overridable in tests
+func (c *ConfigSet) now() time.Time {
+	if c.clock != nil {
+		return c.clock()
+	}
+	return time.Now()
+}
+
+// Reports whether raw is a schedule wrapper or a list of them and parses it, text is raw's original text
+func parseSchedule(raw any, text json.RawMessage) (sched []scheduledValue, ok bool, err error) {
+	var entries []any
+	entryText := func(int) json.RawMessage { return text }
+	switch r := raw.(type) {
+	case map[string]any:
+		entries = []any{r}
+	case []any:
+		entries = r
+		entryText = func(i int) json.RawMessage { return rawItem(text, i) }
+	default:
+		return nil, false, nil
+	}
+
+	for _, e := range entries {
+		m, isMap := e.(map[string]any)
+		if !isMap || !isScheduleWrapper(m) {
+			return nil, false, nil
+		}
+	}
+
+	for i, e := range entries {
+		m := e.(map[string]any)
+		sv := scheduledValue{raw: m[ScheduleValueKey], text: rawMember(entryText(i), ScheduleValueKey)}
+		if sv.from, err = scheduleTime(m, ScheduleFromKey); err != nil {
+			return nil, true, err
+		}
+		if sv.until, err = scheduleTime(m, ScheduleUntilKey); err != nil {
+			return nil, true, err
+		}
+		sched = append(sched, sv)
+	}
+	return sched, true, nil
+}
+
+func isScheduleWrapper(m map[string]any) bool {
+	if _, ok := m[ScheduleValueKey]; !ok {
+		return false
+	}
+	for k := range m {
+		if k != ScheduleValueKey && k != ScheduleFromKey && k != ScheduleUntilKey {
+			return false
+		}
+	}
+	return true
+}
+
+func scheduleTime(m map[string]any, key string) (time.Time, error) {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, fmt.Sprint(v))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s: %v", ErrParse, key, err)
+	}
+	return t, nil
+}
+
+// Returns the raw value active at t and its original text
+func activeSchedule(sched []scheduledValue, t time.Time) (any, json.RawMessage, bool) {
+	for _, s := range sched {
+		if s.activeAt(t) {
+			return s.raw, s.text, true
+		}
+	}
+	return nil, nil, false
+}
+
+// Re-evaluates every scheduled option against the current time
+// Options with no active window are reset to their default value
+// Returns the names of the options whose value changed
+func (c *ConfigSet) RefreshSchedules() ([]string, error) {
+	c = c.base()
+	now := c.now()
+	var changed []string
+	var err error
+
+	c.mu.Lock()
+	for _, o := range c.sortOptions(c.formal) {
+		sched, ok := c.schedules[o.Name]
+		if !ok {
+			continue
+		}
+
+		prev := o.Value.Get()
+		raw, text, active := activeSchedule(sched, now)
+		var e error
+		if active {
+			e = c.setValue(o, valueText(o, raw, text))
+		} else {
+			e = o.set(o.DefValue)
+		}
+		if e != nil {
+			err = e
+			continue
+		}
+		if !c.optionEqual(o.Name, prev, o.Value.Get()) {
+			changed = append(changed, o.Name)
+		}
+	}
+	if len(changed) > 0 {
+		c.publish()
+	}
+	c.mu.Unlock()
+
+	if len(changed) > 0 {
+		c.emit(Changed, "", nil, changed...)
+	}
+	return changed, err
+}
+
+// Returns the next time a scheduled option's active value may change
+// ok is false when no scheduled option has a future boundary
+func (c *ConfigSet) NextScheduleChange() (next time.Time, ok bool) {
+	c = c.base()
+	now := c.now()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, sched := range c.schedules {
+		for _, s := range sched {
+			for _, b := range []time.Time{s.from, s.until} {
+				if b.IsZero() || !b.After(now) {
+					continue
+				}
+				if !ok || b.Before(next) {
+					next, ok = b, true
+				}
+			}
+		}
+	}
+	return next, ok
+}
+
+// Keeps scheduled options up to date, waking at every window boundary until ctx is done
+// onChange, if not nil, is called with the names of the options that changed and any error
+func (c *ConfigSet) RunSchedules(ctx context.Context, onChange func(changed []string, err error)) error {
+	c = c.base()
+	for {
+		next, ok := c.NextScheduleChange()
+		if !ok {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		timer := time.NewTimer(next.Sub(c.now()))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		changed, err := c.RefreshSchedules()
+		if onChange != nil && (len(changed) > 0 || err != nil) {
+			onChange(changed, err)
+		}
+	}
+}
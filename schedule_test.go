@@ -0,0 +1,129 @@
+package configManager
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_scheduledValue(t *testing.T) {
+	toParse := `{
+		"banner": [
+			{"value": "maintenance", "valid_from": "2026-01-01T10:00:00Z", "valid_until": "2026-01-01T12:00:00Z"},
+			{"value": "welcome"}
+		],
+		"limit": {"value": 10, "valid_from": "2026-01-01T11:00:00Z"}
+	}`
+
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	c := ConfigSet{clock: func() time.Time { return now }}
+
+	banner, _ := AddOptionToSet(&c, "banner", "")
+	limit, _ := AddOptionToSet(&c, "limit", int64(100))
+
+	if err := c.ParseFromData([]byte(toParse)); err != nil {
+		t.Fatal(err)
+	}
+	if *banner != "welcome" || *limit != 100 {
+		t.Fatalf("Unexpected values before window: [%v] [%v]", *banner, *limit)
+	}
+
+	next, ok := c.NextScheduleChange()
+	if !ok || !next.Equal(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Unexpected next change: %v %v", next, ok)
+	}
+
+	now = time.Date(2026, 1, 1, 11, 30, 0, 0, time.UTC)
+	changed, err := c.RefreshSchedules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 2 || *banner != "maintenance" || *limit != 10 {
+		t.Fatalf("Unexpected values inside window: [%v] [%v] changed %v", *banner, *limit, changed)
+	}
+
+	now = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.RefreshSchedules()
+	if *banner != "welcome" {
+		t.Fatalf("Window end not exclusive, received: [%v]", *banner)
+	}
+}
+
+func Test_scheduledNumbers(t *testing.T) {
+	toParse := `{
+		"limit": [
+			{"value": 1000000, "valid_until": "2026-01-01T10:00:00Z"},
+			{"value": 12345678901234567890, "valid_from": "2026-01-01T10:00:00Z"}
+		]
+	}`
+
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	c := ConfigSet{clock: func() time.Time { return now }}
+	limit, _ := AddOptionToSet(&c, "limit", uint64(1))
+
+	if err := c.ParseFromData([]byte(toParse)); err != nil {
+		t.Fatal(err)
+	}
+	if *limit != 1000000 {
+		t.Fatalf("Unexpected value: %v", *limit)
+	}
+
+	now = time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC)
+	if _, err := c.RefreshSchedules(); err != nil {
+		t.Fatal(err)
+	}
+	if *limit != 12345678901234567890 {
+		t.Fatalf("Scheduled value lost digits: %v", *limit)
+	}
+}
+
+func Test_scheduleReplaced(t *testing.T) {
+	scheduled := `{"banner": [{"value": "maint", "valid_from": "2026-01-01T10:00:00Z"}, {"value": "welcome"}]}`
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	c := ConfigSet{clock: func() time.Time { return now }}
+	banner, _ := AddOptionToSet(&c, "banner", "")
+	AddOptionToSet(&c, "n", int64(0))
+
+	replace := map[string]func() error{
+		"reload": func() error { _, err := c.reloadData([]byte(`{"banner": "plain"}`)); return err },
+		"set":    func() error { return c.Set("banner", "plain") },
+		"patch":  func() error { return c.ApplyMergePatch([]byte(`{"banner": "plain"}`)) },
+	}
+	for name, fn := range replace {
+		now = time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+		if _, err := c.reloadData([]byte(scheduled)); err != nil || *banner != "welcome" {
+			t.Fatalf("%s: schedule not applied: [%v] %v", name, *banner, err)
+		}
+		if err := fn(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		now = time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC)
+		if _, err := c.RefreshSchedules(); err != nil || *banner != "plain" {
+			t.Fatalf("%s: replaced schedule came back: [%v] %v", name, *banner, err)
+		}
+	}
+
+	// a schedule without an active window leaves the option unset for a later source to set
+	var d ConfigSet
+	d.clock = c.clock
+	other, _ := AddOptionToSet(&d, "banner", "")
+	now = time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	d.ParseFromData([]byte(`{"banner": [{"value": "maint", "valid_from": "2026-01-01T10:00:00Z"}]}`))
+	if err := d.ParseFromData([]byte(`{"banner": "plain"}`)); err != nil {
+		t.Fatal(err)
+	}
+	now = time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC)
+	if _, err := d.RefreshSchedules(); err != nil || *other != "plain" {
+		t.Fatalf("parse: replaced schedule came back: [%v] %v", *other, err)
+	}
+
+	// a rejected parse keeps the schedule it would have replaced
+	now = time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	c.reloadData([]byte(scheduled))
+	if _, err := c.reloadData([]byte(`{"banner": "plain", "n": "x"}`)); err == nil {
+		t.Fatal("Invalid reload accepted")
+	}
+	now = time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC)
+	if _, err := c.RefreshSchedules(); err != nil || *banner != "maint" {
+		t.Fatalf("Schedule not restored after a failed reload: [%v] %v", *banner, err)
+	}
+}
@@ -0,0 +1,121 @@
+package configManager
+
+import (
+	"math"
+	"math/rand/v2"
+	"reflect"
+)
+
+// Attempts at drawing a value an option's constraints accept before the option is left out
+const seedAttempts = 20
+
+// Returns a random configuration document the set accepts, in the set's format, e.g. to seed fuzz and property tests
+//
+//	r := rand.New(rand.NewPCG(seed, 0))
+//	data, _ := c.RandomConfig(r)
+//	f.Add(data)
+//
+// Numbers are drawn between their Min and Max, OneOf options get one of their values, booleans and strings are random
+// Every value is checked against the option's constraints, options no value could be found for are left out
+// as are options of other types and experimental options; other options are left out at random so defaults get exercised too
+// Required options are always written
+func (c *ConfigSet) RandomConfig(r *rand.Rand) ([]byte, error) {
+	if c.root != nil {
+		return c.root.RandomConfig(r)
+	}
+	m, err := c.marshaller()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	values := make(map[string]any)
+	for _, o := range c.sortOptions(c.formal) {
+		if o.Experimental || !o.Required && r.IntN(4) == 0 {
+			continue
+		}
+		for range seedAttempts {
+			v, ok := randomValue(o, r)
+			if !ok {
+				break
+			}
+			if checkConstraints(v, o.constraints) == nil {
+				values[o.Name] = v
+				break
+			}
+		}
+	}
+	c.mu.RUnlock()
+
+	if c.nestedFormat() {
+		values = unflatten(values)
+	}
+	return m(values)
+}
+
+// Returns a random configuration document the global set accepts
+func RandomConfig(r *rand.Rand) ([]byte, error) { return globalConfig.RandomConfig(r) }
+
+// Draws a value for o, ok is false when o's type isn't supported
+func randomValue(o *Option, r *rand.Rand) (any, bool) {
+	var lo, hi float64
+	hasLo, hasHi := false, false
+	for _, con := range o.constraints {
+		switch con := con.(type) {
+		case oneOfConstraint:
+			if len(con.allowed) > 0 {
+				return con.allowed[r.IntN(len(con.allowed))], true
+			}
+		case minConstraint:
+			if f, ok := toFloat(reflect.ValueOf(con.min)); ok && (!hasLo || f > lo) {
+				lo, hasLo = f, true
+			}
+		case maxConstraint:
+			if f, ok := toFloat(reflect.ValueOf(con.max)); ok && (!hasHi || f < hi) {
+				hi, hasHi = f, true
+			}
+		}
+	}
+	// unbounded numbers stay within a thousand of the bound they have, or of zero
+	switch {
+	case !hasLo && !hasHi:
+		lo, hi = 0, 1000
+	case !hasLo:
+		lo = hi - 1000
+	case !hasHi:
+		hi = lo + 1000
+	}
+
+	v := reflect.ValueOf(o.Value.Get())
+	switch v.Kind() {
+	case reflect.Bool:
+		return r.IntN(2) == 1, true
+	case reflect.String:
+		b := make([]byte, 1+r.IntN(12))
+		for i := range b {
+			b[i] = byte('a' + r.IntN(26))
+		}
+		return string(b), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := float64(v.Type().Bits() - 2)
+		lo, hi = math.Ceil(max(lo, -math.Exp2(bits))), math.Floor(min(hi, math.Exp2(bits)))
+		if lo > hi {
+			return nil, false
+		}
+		// the span may not fit an int64, the sum wraps back into range
+		span := uint64(int64(hi) - int64(lo))
+		n := int64(lo) + int64(r.Uint64N(span+1))
+		return reflect.ValueOf(n).Convert(v.Type()).Interface(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		lo, hi = math.Ceil(max(lo, 0)), math.Floor(min(hi, math.Exp2(float64(v.Type().Bits()-1))))
+		if lo > hi {
+			return nil, false
+		}
+		n := uint64(lo) + r.Uint64N(uint64(hi-lo)+1)
+		return reflect.ValueOf(n).Convert(v.Type()).Interface(), true
+	case reflect.Float32, reflect.Float64:
+		f := lo + r.Float64()*(hi-lo)
+		return reflect.ValueOf(f).Convert(v.Type()).Interface(), true
+	}
+	return nil, false
+}
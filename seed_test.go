@@ -0,0 +1,45 @@
+package configManager
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"testing"
+)
+
+func seedSchema(format fileFormat) *ConfigSet {
+	c := &ConfigSet{Format: format}
+	AddOptionToSet(c, "server.port", int64(8080), Min(1), Max(65535))
+	AddOptionToSet(c, "server.mode", "fast", OneOf("fast", "safe"))
+	AddOptionToSet(c, "ratio", 0.75, Min(0.5), Max(1.0))
+	AddOptionToSet(c, "workers", int32(4), Min(1))
+	AddOptionToSet(c, "id", "abc-1", Pattern(`^[a-z]+-[0-9]$`))
+	AddOptionToSet(c, "debug", false)
+	c.Lookup("workers").Required = true
+	return c
+}
+
+func Test_randomConfig(t *testing.T) {
+	for _, format := range []fileFormat{JSON, TOML} {
+		c := seedSchema(format)
+		for seed := range uint64(200) {
+			data, err := c.RandomConfig(rand.New(rand.NewPCG(seed, 0)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			d := seedSchema(format)
+			if err := d.ParseFromData(data); err != nil {
+				t.Fatalf("Generated configuration rejected: %v\n%s", err, data)
+			}
+			if !d.View().IsSet("workers") {
+				t.Fatalf("Required option left out:\n%s", data)
+			}
+		}
+	}
+
+	c := seedSchema(JSON)
+	a, _ := c.RandomConfig(rand.New(rand.NewPCG(1, 2)))
+	b, _ := c.RandomConfig(rand.New(rand.NewPCG(1, 2)))
+	if !bytes.Equal(a, b) {
+		t.Fatalf("Same seed generated different configurations:\n%s\n%s", a, b)
+	}
+}
@@ -0,0 +1,135 @@
+package configManager
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path"
+)
+
+// Top level key holding conditional sections
+// Its value is a list of objects, each with a "when" object of selectors and the option values to apply
+//
+//	{
+//	  "workers": 2,
+//	  "conditional": [
+//	    {"when": {"role": "worker"}, "workers": 16},
+//	    {"when": {"hostname": "db-*", "role": ["primary", "replica"]}, "cache": false}
+//	  ]
+//	}
+//
+// A section applies when every selector in its "when" matches, selector values may be a
+// single pattern or a list of patterns, matched with path.Match so wildcards are allowed
+// Matching sections are merged over the top level values in file order
+// If an option is registered with this name it's treated as a regular option instead
+const ConditionalKey = "conditional"
+
+// Key inside a conditional section holding its selectors
+const whenKey = "when"
+
+// Sets the selectors conditional sections are evaluated against, e.g. {"role": "worker"}
+// If no "hostname" selector is given the machine's hostname is used
+// Constraints declared with Profile are picked again, values already set are not checked again
+func (c *ConfigSet) SetSelectors(selectors map[string]string) {
+	c = c.base()
+	c.selectors = maps.Clone(selectors)
+	c.activateProfiles()
+}
+
+// Returns the selectors conditional sections are evaluated against
+func (c *ConfigSet) Selectors() map[string]string {
+	c = c.base()
+	s := maps.Clone(c.selectors)
+	if s == nil {
+		s = make(map[string]string)
+	}
+	if _, ok := s["hostname"]; !ok {
+		if h, err := os.Hostname(); err == nil {
+			s["hostname"] = h
+		}
+	}
+	return s
+}
+
+// Merges every matching conditional section into the parsed data
+func (c *ConfigSet) applySections(d map[string]any) (map[string]any, error) {
+	raw, ok := d[ConditionalKey]
+	if !ok {
+		return d, nil
+	}
+	if _, registered := c.formal[ConditionalKey]; registered {
+		return d, nil
+	}
+
+	sections, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list of sections, got %T", ConditionalKey, raw)
+	}
+
+	selectors := c.Selectors()
+	merged := maps.Clone(d)
+	delete(merged, ConditionalKey)
+
+	for i, s := range sections {
+		section, ok := s.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s section %d must be an object, got %T", ConditionalKey, i, s)
+		}
+
+		match, err := sectionMatches(section[whenKey], selectors)
+		if err != nil {
+			return nil, fmt.Errorf("%s section %d: %w", ConditionalKey, i, err)
+		}
+		if !match {
+			continue
+		}
+
+		// nested objects name options the same way as at the top level, which was flattened before
+		values := maps.Clone(section)
+		delete(values, whenKey)
+		maps.Copy(merged, c.flatten(values))
+	}
+
+	return merged, nil
+}
+
+func sectionMatches(when any, selectors map[string]string) (bool, error) {
+	if when == nil {
+		return true, nil
+	}
+	conds, ok := when.(map[string]any)
+	if !ok {
+		return false, fmt.Errorf("%q must be an object, got %T", whenKey, when)
+	}
+
+	for name, want := range conds {
+		have, ok := selectors[name]
+		if !ok {
+			return false, nil
+		}
+
+		var patterns []any
+		switch w := want.(type) {
+		case []any:
+			patterns = w
+		default:
+			patterns = []any{w}
+		}
+
+		matched := false
+		for _, p := range patterns {
+			m, err := path.Match(fmt.Sprint(p), have)
+			if err != nil {
+				return false, fmt.Errorf("bad pattern for selector %q: %w", name, err)
+			}
+			if m {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,61 @@
+package configManager
+
+import "testing"
+
+func Test_conditionalSections(t *testing.T) {
+	toParse := `{
+		"workers": 2,
+		"cache": true,
+		"conditional": [
+			{"when": {"role": "worker"}, "workers": 16},
+			{"when": {"role": ["db", "cache"], "hostname": "prod-*"}, "cache": false},
+			{"when": {"zone": "eu"}, "workers": 4}
+		]
+	}`
+
+	var c ConfigSet
+	workers, _ := AddOptionToSet(&c, "workers", int64(1))
+	cache, _ := AddOptionToSet(&c, "cache", false)
+
+	c.SetSelectors(map[string]string{"role": "worker", "hostname": "prod-7"})
+	if err := c.ParseFromData([]byte(toParse)); err != nil {
+		t.Fatal(err)
+	}
+
+	if *workers != 16 {
+		t.Fatalf("Option value mismatch, expected: [16] received: [%v]", *workers)
+	}
+	if !*cache {
+		t.Fatal("Non matching section was applied")
+	}
+
+	var d ConfigSet
+	cache, _ = AddOptionToSet(&d, "cache", true)
+	d.SetSelectors(map[string]string{"role": "db", "hostname": "prod-1"})
+	if err := d.ParseFromData([]byte(toParse)); err != nil {
+		t.Fatal(err)
+	}
+	if *cache {
+		t.Fatal("Wildcard section was not applied")
+	}
+}
+
+func Test_nestedConditionalSection(t *testing.T) {
+	toParse := `{
+		"server": {"port": 80},
+		"conditional": [
+			{"when": {"role": "api"}, "server": {"port": 8080, "tls": {"enabled": true}}}
+		]
+	}`
+
+	var c ConfigSet
+	port, _ := AddOptionToSet(&c, "server.port", int64(1))
+	tls, _ := AddOptionToSet(&c, "server.tls.enabled", false)
+	c.SetSelectors(map[string]string{"role": "api"})
+	if err := c.ParseFromData([]byte(toParse)); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 8080 || !*tls {
+		t.Fatalf("Nested section not applied: port [%v] tls [%v]", *port, *tls)
+	}
+}
@@ -0,0 +1,87 @@
+package configManager
+
+import (
+	"maps"
+	"slices"
+)
+
+// An immutable copy of every option's value at one point in time
+// Reads are plain map lookups with no locking, so a Snapshot can be shared freely between goroutines
+// Values are the ones returned by each option's Get, reference types such as slices must not be modified
+type Snapshot struct {
+	values  map[string]any
+	strings map[string]string
+	set     map[string]bool
+}
+
+// Returns the value of the named option and whether it exists
+func (s *Snapshot) Get(name string) (any, bool) {
+	v, ok := s.values[name]
+	return v, ok
+}
+
+// Returns the named option's value as a string, empty if it doesn't exist
+func (s *Snapshot) String(name string) string { return s.strings[name] }
+
+// Reports whether the named option was set, as opposed to holding its default
+func (s *Snapshot) IsSet(name string) bool { return s.set[name] }
+
+// Returns all option names in lexicographical order
+func (s *Snapshot) Names() []string { return slices.Sorted(maps.Keys(s.values)) }
+
+// Returns the named option's value as a T
+// ok is false if the option doesn't exist or holds a different type
+func SnapshotGet[T any](s *Snapshot, name string) (v T, ok bool) {
+	a, exists := s.values[name]
+	if !exists {
+		return v, false
+	}
+	v, ok = a.(T)
+	return v, ok
+}
+
+func (c *ConfigSet) buildSnapshot() *Snapshot {
+	s := &Snapshot{
+		values:  make(map[string]any, len(c.formal)),
+		strings: make(map[string]string, len(c.formal)),
+		set:     make(map[string]bool, len(c.actual)),
+	}
+	for name, o := range c.formal {
+		s.values[name] = o.Value.Get()
+		s.strings[name] = o.Value.String()
+	}
+	for name := range c.actual {
+		s.set[name] = true
+	}
+	return s
+}
+
+// Replaces the current snapshot after options changed
+func (c *ConfigSet) publish() { c.view.Store(c.buildSnapshot()) }
+
+// Returns the latest snapshot of the configuration
+// The snapshot is replaced atomically whenever options are set, parsed or reloaded, and rebuilt on the next call after options are defined
+// a returned snapshot never changes so request handlers can hold on to it for consistent reads
+// Only that rebuild waits, for a Set or reload in progress, reading a published snapshot never blocks
+func (c *ConfigSet) View() *Snapshot {
+	c = c.base()
+	if s := c.view.Load(); s != nil {
+		return s
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.snapshot()
+}
+
+// Returns the latest snapshot, building it when options were defined since it was published
+// The caller holds c.mu, which definitions hold while they change the options
+func (c *ConfigSet) snapshot() *Snapshot {
+	if s := c.view.Load(); s != nil {
+		return s
+	}
+	c.view.CompareAndSwap(nil, c.buildSnapshot())
+	return c.view.Load()
+}
+
+// Returns the latest snapshot of the configuration
+func View() *Snapshot { return globalConfig.View() }
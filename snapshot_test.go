@@ -0,0 +1,179 @@
+package configManager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_snapshot(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "greeting", "hello")
+	AddOptionToSet(&c, "repeats", int64(1))
+
+	before := c.View()
+	if v, ok := SnapshotGet[string](before, "greeting"); !ok || v != "hello" {
+		t.Fatalf("Snapshot value mismatch, expected: [hello] received: [%v]", v)
+	}
+	if before.IsSet("greeting") {
+		t.Fatal("Default value reported as set")
+	}
+
+	if err := c.ParseFromData([]byte(`{"greeting":"howdy","repeats":3}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	after := c.View()
+	if v, _ := SnapshotGet[string](before, "greeting"); v != "hello" {
+		t.Fatal("Old snapshot was modified")
+	}
+	if v, _ := SnapshotGet[int64](after, "repeats"); v != 3 {
+		t.Fatalf("Snapshot value mismatch, expected: [3] received: [%v]", v)
+	}
+	if !after.IsSet("repeats") || after.String("repeats") != "3" {
+		t.Fatal("Snapshot did not record parsed option")
+	}
+	if _, ok := SnapshotGet[string](after, "repeats"); ok {
+		t.Fatal("SnapshotGet accepted mismatched type")
+	}
+}
+
+func Test_setSaveConsistency(t *testing.T) {
+	var c ConfigSet
+	for i := range 8 {
+		AddOptionToSet(&c, fmt.Sprintf("worker%d", i), int64(-1))
+	}
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			name := fmt.Sprintf("worker%d", i)
+			for j := range 50 {
+				if err := c.Set(name, strconv.Itoa(j)); err != nil {
+					t.Error(err)
+					return
+				}
+				data, err := c.SaveTo()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				// only this goroutine sets its option, so the save must hold exactly what it just set
+				var saved map[string]int64
+				json.Unmarshal(data, &saved)
+				if saved[name] != int64(j) {
+					t.Errorf("Saved %s is %d after setting %d", name, saved[name], j)
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func Test_snapshotDefinitions(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "first", "a")
+	c.View()
+
+	// definitions only mark the snapshot outdated, it's built once on the next read
+	for i := range 100 {
+		AddOptionToSet(&c, "opt"+strconv.Itoa(i), int64(i))
+		if c.view.Load() != nil {
+			t.Fatal("Snapshot built on definition")
+		}
+	}
+	if n := len(c.View().Names()); n != 101 {
+		t.Fatalf("Snapshot has %d options, want 101", n)
+	}
+
+	// views read while options are defined never miss one
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				c.View()
+			}
+		}
+	})
+	for i := range 2000 {
+		AddOptionToSet(&c, "late"+strconv.Itoa(i), "")
+	}
+	close(done)
+	wg.Wait()
+	if n := len(c.View().Names()); n != 2101 {
+		t.Fatalf("Snapshot has %d options, want 2101", n)
+	}
+}
+
+func Test_snapshotIsolated(t *testing.T) {
+	var c ConfigSet
+	jobs, _ := AddOptionToSet(&c, "jobs", BlockList{})
+	routes, _ := AddOptionToSet(&c, "routes", OrderedMap{})
+	if err := c.ParseFromData([]byte(`{"jobs": [{"name": "api", "tags": ["a"]}], "routes": {"/": {"to": "web"}}}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	s := c.View()
+	(*jobs)[0]["name"] = "changed"
+	(*jobs)[0]["tags"].([]any)[0] = "changed"
+	(*routes)[0].Value.(map[string]any)["to"] = "changed"
+
+	list, _ := SnapshotGet[BlockList](s, "jobs")
+	m, _ := SnapshotGet[OrderedMap](s, "routes")
+	if list[0].Name() != "api" || list[0].Strings("tags")[0] != "a" || m[0].Value.(map[string]any)["to"] != "web" {
+		t.Fatalf("Snapshot shares memory with the options: %v %v", list, m)
+	}
+
+	// a registration in progress doesn't hold up a rebuild
+	AddOptionToSet(&c, "late", "")
+	c.registering.Lock()
+	defer c.registering.Unlock()
+	done := make(chan *Snapshot)
+	go func() { done <- c.View() }()
+	select {
+	case s := <-done:
+		if _, ok := s.Get("late"); !ok {
+			t.Fatal("Rebuilt snapshot misses the new option")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("View blocked behind a registration")
+	}
+}
+
+func Test_setWhileDefining(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "workers", int64(1))
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := range 500 {
+			AddOptionToSet(&c, "opt"+strconv.Itoa(i), int64(i))
+		}
+	})
+	wg.Go(func() {
+		r := c.Batch()
+		for i := range 500 {
+			BatchOption(r, "batch"+strconv.Itoa(i), "")
+		}
+		r.Commit()
+	})
+	for i := range 500 {
+		if err := c.Set("workers", strconv.Itoa(i)); err != nil {
+			t.Fatal(err)
+		}
+		c.View()
+	}
+	wg.Wait()
+
+	if n := len(c.View().Names()); n != 1001 {
+		t.Fatalf("Snapshot has %d options, want 1001", n)
+	}
+}
@@ -0,0 +1,168 @@
+package configManager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Provides configuration data in the set's Format, e.g. a file, a remote backend or a test fixture
+type Source interface {
+	Load() ([]byte, error)
+}
+
+// Optionally implemented by a Source that Save can write to
+type SavableSource interface {
+	Source
+	Save(data []byte) error
+}
+
+// Optionally implemented by a Source that can report changes, used by Reloader
+// Watch calls changed whenever the data may have changed until ctx is done
+type WatchableSource interface {
+	Source
+	Watch(ctx context.Context, changed func()) error
+}
+
+// Optionally implemented by a Source providing values by option name rather than data in the set's Format,
+// e.g. the environment or command line arguments, Parse and reloads call LoadValues instead of Load
+// Values may be nested like a decoded file, {"server": {"port": 80}} provides "server.port"
+// A nil map provides nothing, e.g. a remote backend without a configuration for this application
+type ValueSource interface {
+	Source
+	LoadValues(ctx context.Context) (map[string]any, error)
+}
+
+// Optionally implemented by a Source to name it in problem reports and Explain, it's "source N" otherwise
+type NamedSource interface {
+	Source
+	Name() string
+}
+
+// Adds sources read by Parse, after the ones already added
+func (c *ConfigSet) AddSource(s ...Source) {
+	c = c.base()
+	c.Sources = append(c.Sources, s...)
+}
+
+// Adds sources read by Parse of the global set
+func AddSource(s ...Source) { globalConfig.AddSource(s...) }
+
+// A source in the order Parse reads it
+type layer struct {
+	src      Source
+	name     string // shown in problem reports
+	optional bool   // a missing file is skipped
+	canLock  bool   // options listed under LockedKey are locked
+}
+
+// Loads the layer, returning nil data for a missing optional file
+func (l layer) load() ([]byte, error) {
+	data, err := l.src.Load()
+	if err != nil && l.optional && errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Returns everything Parse reads, in order of precedence
+func (c *ConfigSet) layers() ([]layer, error) {
+	if c.Location == "" && len(c.Sources) == 0 {
+		return nil, fmt.Errorf("No file location provided")
+	}
+
+	var layers []layer
+	if c.OverlayLocation != "" {
+		layers = append(layers, layer{src: FileSource{c.FS, c.OverlayLocation}, name: c.OverlayLocation, optional: true})
+	}
+
+	tiers := slices.Sorted(maps.Keys(c.Tiers))
+	slices.Reverse(tiers)
+	for _, t := range tiers {
+		if loc := c.Tiers[t]; loc != "" {
+			layers = append(layers, layer{src: FileSource{c.FS, loc}, name: loc, optional: true})
+		}
+	}
+
+	for i, s := range c.Sources {
+		_, file := s.(FileSource)
+		_, values := s.(ValueSource)
+		layers = append(layers, layer{src: s, name: sourceName(i, s), canLock: !file && !values})
+	}
+
+	if c.Location != "" {
+		layers = append(layers, layer{src: FileSource{c.FS, c.Location}, name: c.Location})
+	}
+	return layers, nil
+}
+
+// Saves every option to the first source that can be written
+// A FileSource is written like Location, with the set's permissions and backups
+func (c *ConfigSet) saveToSource() error {
+	for i, s := range c.Sources {
+		ss, ok := s.(SavableSource)
+		if !ok {
+			continue
+		}
+		data, err := c.SaveTo()
+		if err != nil {
+			return fmt.Errorf("Could not save configuration: %v", err)
+		}
+		if f, file := s.(FileSource); file {
+			err = c.writeFileFS(f.FS, f.Path, data)
+		} else {
+			err = ss.Save(data)
+		}
+		if err != nil {
+			return err
+		}
+		c.emit(Saved, sourceName(i, s), nil)
+		return nil
+	}
+	return fmt.Errorf("No file location provided")
+}
+
+// Returns the name problem reports and events give the ith source: its path, its Name or "source N"
+func sourceName(i int, s Source) string {
+	if f, file := s.(FileSource); file {
+		return f.Path
+	}
+	if n, ok := s.(NamedSource); ok {
+		return n.Name()
+	}
+	return fmt.Sprintf("source %d", i+1)
+}
+
+// =-=-= Built in sources
+
+// A file on FS, or on the operating system's file system when FS is nil
+type FileSource struct {
+	FS   fs.FS
+	Path string
+}
+
+func (f FileSource) Load() ([]byte, error) { return readFile(f.FS, f.Path) }
+
+// Writes the file, creating missing directories, fails with ErrReadOnlyLocation when it can't be written
+// A ConfigSet saving to the source writes it with its own permissions and backups instead
+func (f FileSource) Save(data []byte) error { return writeFS(f.FS, f.Path, data, defaultWriteMode) }
+
+// Fixed configuration data, e.g. a test fixture or an embedded default
+type DataSource []byte
+
+func (d DataSource) Load() ([]byte, error) { return d, nil }
+
+// Adapts a function to a Source, e.g. a call to a remote backend
+type SourceFunc func() ([]byte, error)
+
+func (f SourceFunc) Load() ([]byte, error) { return f() }
+
+// Returns a source reading r once on its first Load, later loads return the same data
+func ReaderSource(r io.Reader) Source {
+	return SourceFunc(sync.OnceValues(func() ([]byte, error) { return io.ReadAll(r) }))
+}
@@ -0,0 +1,136 @@
+package configManager
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// A source whose data can be swapped, reporting every change through Watch
+type watchedSource struct {
+	data    atomic.Value
+	changes chan struct{}
+}
+
+func (w *watchedSource) Load() ([]byte, error) { return w.data.Load().([]byte), nil }
+
+func (w *watchedSource) Watch(ctx context.Context, changed func()) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.changes:
+			changed()
+		}
+	}
+}
+
+func Test_sources(t *testing.T) {
+	var mem memFS
+	mem.WriteFile("config.json", []byte(`{"greeting":"hello","name":"base","workers":1}`), 0644)
+
+	c := ConfigSet{FS: &mem, Location: "config.json"}
+	c.AddSource(
+		DataSource(`{"greeting":"fixture"}`),
+		ReaderSource(strings.NewReader(`{"greeting":"reader","name":"reader"}`)),
+	)
+	greeting, _ := AddOptionToSet(&c, "greeting", "")
+	name, _ := AddOptionToSet(&c, "name", "")
+	workers, _ := AddOptionToSet(&c, "workers", int64(0))
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *greeting != "fixture" || *name != "reader" || *workers != 1 {
+		t.Fatalf("Sources not merged by precedence: [%v] [%v] [%v]", *greeting, *name, *workers)
+	}
+
+	failing := ConfigSet{Sources: []Source{SourceFunc(func() ([]byte, error) { return nil, errors.New("backend down") })}}
+	if err := failing.Parse(); err == nil {
+		t.Fatal("Source error not returned")
+	}
+}
+
+func Test_saveToSource(t *testing.T) {
+	var mem memFS
+	c := ConfigSet{Sources: []Source{DataSource(`{}`), FileSource{&mem, "saved.json"}}}
+	AddOptionToSet(&c, "greeting", "hello")
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	d := ConfigSet{Sources: []Source{FileSource{&mem, "saved.json"}}}
+	greeting, _ := AddOptionToSet(&d, "greeting", "")
+	if err := d.Parse(); err != nil || *greeting != "hello" {
+		t.Fatalf("Saved source not parsed: [%v] %v", *greeting, err)
+	}
+
+	if err := (&ConfigSet{Sources: []Source{DataSource(`{}`)}}).Save(); err == nil {
+		t.Fatal("Saved without a writable source")
+	}
+}
+
+func Test_saveToFileSourceMode(t *testing.T) {
+	var mem memFS
+	c := ConfigSet{Sources: []Source{FileSource{&mem, "etc/app.json"}}, Backups: 1}
+	AddOptionToSet(&c, "token", "")
+	c.MarkSecret("token")
+	events, stop := c.Events()
+	defer stop()
+
+	for _, token := range []string{"a", "b"} {
+		c.Set("token", token)
+		if err := c.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fi, err := fs.Stat(&mem, "etc/app.json"); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("Secret source saved with the wrong mode: %v %v", fi.Mode(), err)
+	}
+	if _, err := fs.Stat(&mem, "etc/app.json.bak.1"); err != nil {
+		t.Fatalf("Source not backed up: %v", err)
+	}
+
+	for e := range events {
+		if e.Kind == Saved {
+			if e.Source != "etc/app.json" {
+				t.Fatalf("Saved event names the source %q", e.Source)
+			}
+			break
+		}
+	}
+}
+
+func Test_watchSource(t *testing.T) {
+	src := &watchedSource{changes: make(chan struct{})}
+	src.data.Store([]byte(`{"workers":1}`))
+
+	c := ConfigSet{Sources: []Source{src}}
+	AddOptionToSet(&c, "workers", int64(0))
+	c.Parse()
+
+	applied := make(chan int64, 4)
+	r := Reloader{Interval: -1}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx, &c, func(s *Snapshot) error {
+		n, _ := SnapshotGet[int64](s, "workers")
+		applied <- n
+		return nil
+	})
+	<-applied
+
+	src.data.Store([]byte(`{"workers":4}`))
+	src.changes <- struct{}{}
+	select {
+	case n := <-applied:
+		if n != 4 {
+			t.Fatalf("Reloaded value mismatch, expected: [4] received: [%v]", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watched change not reloaded")
+	}
+}
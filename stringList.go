@@ -0,0 +1,118 @@
+package configManager
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// =-=-= stringListValue
+
+type stringListValue struct {
+	ptr  *[]string
+	sep  string
+	trim bool
+}
+
+func newStringListValue(p *[]string, sep string, trim bool) *stringListValue {
+	return &stringListValue{p, sep, trim}
+}
+
+// Accepts a JSON array, as passed by Parse for native lists, or a delimited string
+func (l *stringListValue) Set(s string) error {
+	var list []string
+
+	if t := strings.TrimSpace(s); strings.HasPrefix(t, "[") {
+		var raw []any
+		if err := json.Unmarshal([]byte(t), &raw); err != nil {
+			return ErrParse
+		}
+		for _, e := range raw {
+			str, ok := e.(string)
+			if !ok {
+				b, _ := json.Marshal(e)
+				str = string(b)
+			}
+			list = append(list, str)
+		}
+	} else if s != "" {
+		list = strings.Split(s, l.sep)
+	}
+
+	out := make([]string, 0, len(list))
+	for _, e := range list {
+		if l.trim {
+			e = strings.TrimSpace(e)
+			if e == "" {
+				continue
+			}
+		}
+		out = append(out, e)
+	}
+
+	*l.ptr = out
+	return nil
+}
+
+func (l stringListValue) Get() any {
+	if l.ptr == nil {
+		return []string(nil)
+	}
+	return slices.Clone(*l.ptr)
+}
+
+// Joins the elements with the separator, or writes a JSON array when the joined text wouldn't read back the same:
+// an element holding the separator, a lone empty element or a list that would read as an array
+func (l stringListValue) String() string {
+	if l.ptr == nil {
+		return ""
+	}
+	list := *l.ptr
+	joined := strings.Join(list, l.sep)
+	if len(list) == 1 && list[0] == "" || strings.HasPrefix(strings.TrimSpace(joined), "[") ||
+		slices.ContainsFunc(list, func(e string) bool { return strings.Contains(e, l.sep) }) {
+		b, _ := json.Marshal(list)
+		return string(b)
+	}
+	return joined
+}
+
+// Lists are saved as native arrays
+func (l stringListValue) MarshalConfig() (any, error) {
+	if l.ptr == nil || *l.ptr == nil {
+		return []string{}, nil
+	}
+	return *l.ptr, nil
+}
+
+// Defines a new list option on the set c, accepting a native array or a string delimited by sep, e.g. "a, b, c"
+// When trim is true white space around elements is removed and empty elements are dropped
+// AddOption with a []string default defines the same option, split on commas and trimmed
+func StringListVarSet(c *ConfigSet, p *[]string, key string, defaultValue []string, sep string, trim bool, constraints ...Constraint) error {
+	if sep == "" {
+		return fmt.Errorf("empty separator for list option %s", key)
+	}
+	*p = slices.Clone(defaultValue)
+	return c.Var(newStringListValue(p, sep, trim), key, constraints...)
+}
+
+// Defines a new list option on the set c, accepting a native array or a string delimited by sep, e.g. "a, b, c"
+// When trim is true white space around elements is removed and empty elements are dropped
+func StringListSet(c *ConfigSet, key string, defaultValue []string, sep string, trim bool, constraints ...Constraint) (*[]string, error) {
+	p := new([]string)
+	err := StringListVarSet(c, p, key, defaultValue, sep, trim, constraints...)
+	return p, err
+}
+
+// Defines a new list option, accepting a native array or a string delimited by sep, e.g. "a, b, c"
+// When trim is true white space around elements is removed and empty elements are dropped
+func StringListVar(p *[]string, key string, defaultValue []string, sep string, trim bool, constraints ...Constraint) error {
+	return StringListVarSet(&globalConfig, p, key, defaultValue, sep, trim, constraints...)
+}
+
+// Defines a new list option, accepting a native array or a string delimited by sep, e.g. "a, b, c"
+// When trim is true white space around elements is removed and empty elements are dropped
+func StringList(key string, defaultValue []string, sep string, trim bool, constraints ...Constraint) (*[]string, error) {
+	return StringListSet(&globalConfig, key, defaultValue, sep, trim, constraints...)
+}
@@ -0,0 +1,95 @@
+package configManager
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_stringListVal(t *testing.T) {
+	var l []string
+	v := newStringListValue(&l, ",", true)
+
+	for in, want := range map[string][]string{
+		"a,b,c":        {"a", "b", "c"},
+		" a , b ,, c ": {"a", "b", "c"},
+		`["x", " y "]`: {"x", "y"},
+		`[1, true]`:    {"1", "true"},
+		"":             {},
+		"single":       {"single"},
+	} {
+		if err := v.Set(in); err != nil {
+			t.Fatalf("Set(%q) rejected valid value: %v", in, err)
+		}
+		if got := v.Get().([]string); !slices.Equal(got, want) {
+			t.Fatalf("Set(%q) produced %q, want %q", in, got, want)
+		}
+	}
+
+	if err := v.Set("[not json"); err == nil {
+		t.Fatal("Set accepted malformed array")
+	}
+}
+
+func Test_stringListOption(t *testing.T) {
+	var c ConfigSet
+	hosts, _ := StringListSet(&c, "hosts", []string{"localhost"}, ";", false)
+	tags, _ := StringListSet(&c, "tags", nil, ",", true)
+
+	if err := c.ParseFromData([]byte(`{"hosts": "a;b", "tags": ["x", "y"]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(*hosts, []string{"a", "b"}) || !slices.Equal(*tags, []string{"x", "y"}) {
+		t.Fatalf("Unexpected values: %q %q", *hosts, *tags)
+	}
+
+	data, _ := c.SaveTo()
+	if !strings.Contains(string(data), `"hosts": [`) {
+		t.Fatalf("List not saved as native array:\n%s", data)
+	}
+}
+
+func Test_stringListString(t *testing.T) {
+	for _, list := range [][]string{
+		{"a,b", "c"},
+		{""},
+		{"[x]", "y"},
+		{"a", "", "b"},
+		{" padded "},
+	} {
+		l := slices.Clone(list)
+		v := newStringListValue(&l, ",", false)
+		s := v.String()
+		if err := v.Set(s); err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(l, list) {
+			t.Fatalf("List changed through String, expected: %q received: %q from %q", list, l, s)
+		}
+	}
+
+	var c ConfigSet
+	if _, err := StringListSet(&c, "tags", nil, "", true); err == nil {
+		t.Fatal("Empty separator accepted")
+	}
+}
+
+func Test_stringListAddOption(t *testing.T) {
+	var c ConfigSet
+	hosts, err := AddOptionToSet(&c, "hosts", []string{"localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFromData([]byte(`{"hosts": ["a.example", "b.example"]}`)); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(*hosts, []string{"a.example", "b.example"}) {
+		t.Fatalf("Unexpected value: %q", *hosts)
+	}
+	if err := c.Set("hosts", "x.example, y.example"); err != nil || !slices.Equal(*hosts, []string{"x.example", "y.example"}) {
+		t.Fatalf("Comma separated list not accepted by Set: %v %q", err, *hosts)
+	}
+	if zero, err := c.IsZeroValue("hosts"); err != nil || zero {
+		t.Fatalf("Unexpected zero value check: %v %v", zero, err)
+	}
+}
@@ -0,0 +1,229 @@
+package configManager
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Struct tag naming the option a field receives, `config:"-"` skips the field
+const structTag = "config"
+
+// Copies the value of every option, set or default, into the matching fields of the struct v points to
+// A field matches the option named by its config tag, or its own name ignoring case
+// Struct fields that don't match an option hold a namespace, their fields match the options under it:
+//
+//	type Config struct {
+//	  Debug bool        // "debug"
+//	  DB    struct {    // namespace "db"
+//	    Host string     // "db.host"
+//	    Conns int `config:"max_conns"` // "db.max_conns"
+//	  }
+//	}
+//
+// Integers convert to any integer or float field they fit in, any other mismatch fails
+// Fields without an option are left alone
+func (c *ConfigSet) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Decode needs a pointer to a struct, got %T", v)
+	}
+
+	s := c.View()
+	byName := make(map[string]string, len(s.values))
+	for name := range s.values {
+		// a Sub view decodes its options by their relative names
+		if rel, ok := strings.CutPrefix(name, c.prefix); ok {
+			byName[strings.ToLower(rel)] = name
+		}
+	}
+	return decodeStruct(s, byName, rv.Elem(), "")
+}
+
+func decodeStruct(s *Snapshot, byName map[string]string, rv reflect.Value, prefix string) error {
+	t := rv.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		key := f.Name
+		if tag := f.Tag.Get(structTag); tag == "-" {
+			continue
+		} else if tag != "" {
+			key = tag
+		}
+		key = prefix + key
+
+		name, ok := key, false
+		if _, ok = s.values[key]; !ok {
+			name, ok = byName[strings.ToLower(key)]
+		}
+		if ok {
+			if err := assignField(rv.Field(i), s.values[name]); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			continue
+		}
+
+		if f.Type.Kind() == reflect.Struct {
+			if err := decodeStruct(s, byName, rv.Field(i), key+NamespaceSeparator); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Stores an option's value in a field, converting integers to other numeric types
+func assignField(field reflect.Value, value any) error {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() {
+		field.SetZero()
+		return nil
+	}
+	if v.Type().AssignableTo(field.Type()) {
+		field.Set(v)
+		return nil
+	}
+
+	from, to := numberKind(v.Kind()), numberKind(field.Kind())
+	if from == 0 || to == 0 || from == reflect.Float64 && to == reflect.Int {
+		return fmt.Errorf("cannot decode %T into field of type %v", value, field.Type())
+	}
+	converted := v.Convert(field.Type())
+	if from == reflect.Int && to == reflect.Int && !converted.Convert(v.Type()).Equal(v) {
+		return fmt.Errorf("%v overflows field of type %v", value, field.Type())
+	}
+	field.Set(converted)
+	return nil
+}
+
+// Registers an option for every field of the struct v points to, using the field's current value as default
+// Options are bound to the fields, parsing the set fills the struct in place
+// Fields are named by their config tag or their lowercase name, struct fields of types that aren't registered
+// hold a namespace like for Decode, and these tags add constraints and usage:
+//
+//	type Server struct {
+//	  Port    int64  `min:"1" max:"65535" usage:"port to listen on"`
+//	  Mode    string `oneof:"dev,prod"`
+//	  Name    string `config:"hostname" pattern:"^[a-z.]+$"`
+//	  TLS struct {
+//	    Cert string // "tls.cert"
+//	  }
+//	}
+//
+// Every field is defined together, the error lists every field that can't be, and then none is registered
+func (c *ConfigSet) FromStruct(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("FromStruct needs a pointer to a struct, got %T", v)
+	}
+
+	r := c.Batch()
+	usage := make(map[string]string)
+	c.structOptions(r, usage, rv.Elem(), "")
+	if err := r.Commit(); err != nil {
+		return err
+	}
+	for name, u := range usage {
+		c.Lookup(name).Usage = u
+	}
+	return nil
+}
+
+func (c *ConfigSet) structOptions(r *Registrar, usage map[string]string, rv reflect.Value, prefix string) {
+	t := rv.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := strings.ToLower(f.Name)
+		if tag := f.Tag.Get(structTag); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		name = prefix + name
+
+		p := rv.Field(i).Addr()
+		factory, reg := c.factoryFor(p.Type())
+		if reg == NoRegistry {
+			if f.Type.Kind() == reflect.Struct {
+				c.structOptions(r, usage, rv.Field(i), name+NamespaceSeparator)
+				continue
+			}
+			r.defs = append(r.defs, batchDef{name, nil, nil, fmt.Errorf("%s: no ValueFactory registered for type %v", name, p.Type())})
+			continue
+		}
+
+		constraints, err := tagConstraints(f)
+		if err != nil {
+			r.defs = append(r.defs, batchDef{name, nil, nil, fmt.Errorf("%s: %w", name, err)})
+			continue
+		}
+		r.Var(factory(p.Interface()), name, constraints...)
+		if u := f.Tag.Get("usage"); u != "" {
+			usage[name] = u
+		}
+	}
+}
+
+// Builds the constraints declared by a field's min, max, oneof and pattern tags
+func tagConstraints(f reflect.StructField) ([]Constraint, error) {
+	var constraints []Constraint
+	for _, tag := range []string{"min", "max", "oneof"} {
+		text, ok := f.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+		var bounds []any
+		for _, s := range strings.Split(text, ",") {
+			b, err := tagValue(f.Type.Kind(), strings.TrimSpace(s))
+			if err != nil {
+				return nil, fmt.Errorf("%s tag: %w", tag, err)
+			}
+			bounds = append(bounds, b)
+		}
+		switch tag {
+		case "min":
+			constraints = append(constraints, minConstraint{bounds[0]})
+		case "max":
+			constraints = append(constraints, maxConstraint{bounds[0]})
+		case "oneof":
+			constraints = append(constraints, oneOfConstraint{bounds})
+		}
+	}
+	if expr, ok := f.Tag.Lookup("pattern"); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("pattern tag: %w", err)
+		}
+		constraints = append(constraints, patternConstraint{re})
+	}
+	return constraints, nil
+}
+
+// Parses a bound written in a tag as a value comparable to a field of kind k
+func tagValue(k reflect.Kind, s string) (any, error) {
+	switch {
+	case k >= reflect.Int && k <= reflect.Int64:
+		return strconv.ParseInt(s, 0, 64)
+	case k >= reflect.Uint && k <= reflect.Uintptr:
+		return strconv.ParseUint(s, 0, 64)
+	case k == reflect.Float32 || k == reflect.Float64:
+		return strconv.ParseFloat(s, 64)
+	case k == reflect.Bool:
+		return strconv.ParseBool(s)
+	}
+	return s, nil
+}
+
+// Registers an option in the global set for every field of the struct v points to
+func FromStruct(v any) error { return globalConfig.FromStruct(v) }
+
+// Copies the value of every option of the global set into the struct v points to
+func Decode(v any) error { return globalConfig.Decode(v) }
@@ -0,0 +1,115 @@
+package configManager
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_decodeStruct(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "debug", false)
+	AddOptionToSet(&c, "db.host", "localhost")
+	AddOptionToSet(&c, "db.max_conns", int64(10))
+	AddOptionToSet(&c, "db.timeout", float64(1.5))
+	StringListSet(&c, "hosts", []string{"a"}, ",", true)
+	AddOptionToSet(&c, "secret", "s3cr3t")
+	if err := c.ParseFromData([]byte(`{"debug": true, "db": {"host": "db.internal"}, "hosts": ["x", "y"]}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Debug bool
+		DB    struct {
+			Host    string
+			Conns   int `config:"max_conns"`
+			Timeout float32
+		}
+		Hosts  []string
+		Secret string `config:"-"`
+		Extra  int
+	}
+	cfg.Extra = 7
+	if err := c.Decode(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Debug || cfg.DB.Host != "db.internal" || cfg.DB.Conns != 10 || cfg.DB.Timeout != 1.5 {
+		t.Fatalf("Unexpected decoded values: %+v", cfg)
+	}
+	if !slices.Equal(cfg.Hosts, []string{"x", "y"}) || cfg.Secret != "" || cfg.Extra != 7 {
+		t.Fatalf("Unexpected decoded values: %+v", cfg)
+	}
+
+	var bad struct{ Debug string }
+	if err := c.Decode(&bad); err == nil {
+		t.Fatal("Mismatched type decoded")
+	}
+	var small struct {
+		DB struct {
+			MaxConns int8 `config:"max_conns"`
+		} `config:"db"`
+	}
+	c.Set("db.max_conns", "1000")
+	if err := c.Decode(&small); err == nil {
+		t.Fatal("Overflowing value decoded")
+	}
+	if err := c.Decode(cfg); err == nil {
+		t.Fatal("Non pointer accepted")
+	}
+}
+
+func Test_fromStruct(t *testing.T) {
+	type server struct {
+		Port    int64  `min:"1" max:"65535" usage:"port to listen on"`
+		Mode    string `oneof:"dev, prod"`
+		Name    string `config:"hostname" pattern:"^[a-z.]+$"`
+		Ratio   float64
+		Ignored string `config:"-"`
+		TLS     struct {
+			Cert string
+		}
+	}
+	cfg := server{Port: 8080, Mode: "dev", Name: "localhost", Ratio: 0.5}
+	cfg.TLS.Cert = "cert.pem"
+
+	var c ConfigSet
+	if err := c.FromStruct(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	c.VisitAll(func(o *Option) { names = append(names, o.Name) })
+	if !slices.Equal(names, []string{"hostname", "mode", "port", "ratio", "tls.cert"}) {
+		t.Fatalf("Unexpected options: %v", names)
+	}
+	if o := c.Lookup("port"); o.DefValue != "8080" || o.Usage != "port to listen on" {
+		t.Fatalf("Unexpected port option: %+v", o)
+	}
+
+	if err := c.ParseFromData([]byte(`{"port": 443, "mode": "prod", "tls": {"cert": "/etc/cert.pem"}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != 443 || cfg.Mode != "prod" || cfg.TLS.Cert != "/etc/cert.pem" || cfg.Name != "localhost" {
+		t.Fatalf("Struct not filled by Parse: %+v", cfg)
+	}
+	for name, value := range map[string]string{"port": "0", "mode": "test", "hostname": "Bad_Host"} {
+		if err := c.Set(name, value); err == nil {
+			t.Fatalf("Tag constraint on %s not applied", name)
+		}
+	}
+}
+
+func Test_fromStructErrors(t *testing.T) {
+	var c ConfigSet
+	bad := struct {
+		Count complex64
+		Port  int64 `min:"ten"`
+		Host  string
+	}{}
+	err := c.FromStruct(&bad)
+	if err == nil || !strings.Contains(err.Error(), "count") || !strings.Contains(err.Error(), "port") {
+		t.Fatalf("Expected errors for every bad field: %v", err)
+	}
+	if c.Lookup("host") != nil {
+		t.Fatal("Options registered despite errors")
+	}
+}
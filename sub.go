@@ -0,0 +1,44 @@
+package configManager
+
+import "strings"
+
+// Returns a view of the set where option names are relative to prefix, so a library can register
+// its options, e.g. "host" and "port" of Sub("db"), without knowing where the application puts them
+//
+// Methods taking an option name, such as Set, Transform, Pin or Blocks, take it relative to the view,
+// functions such as AddOptionToSet and StringListSet accept the view like any set
+// Visited options, transformers and ${name} references use full names
+// Parsing, saving and every other set-wide method act on the parent, whose fields such as Location and Format apply
+func (c *ConfigSet) Sub(prefix string) *ConfigSet {
+	prefix = strings.TrimSuffix(prefix, NamespaceSeparator) + NamespaceSeparator
+	if c.root != nil {
+		return &ConfigSet{root: c.root, prefix: c.prefix + prefix}
+	}
+	return &ConfigSet{root: c, prefix: prefix}
+}
+
+// Returns the namespace of a Sub view without its trailing separator, empty for a regular set
+func (c *ConfigSet) Prefix() string { return strings.TrimSuffix(c.prefix, NamespaceSeparator) }
+
+// Returns the set a Sub view registers into, c itself for a regular set
+func (c *ConfigSet) base() *ConfigSet {
+	if c.root != nil {
+		return c.root
+	}
+	return c
+}
+
+// Returns the set holding the option name relative to c and its full name there
+func (c *ConfigSet) owner(name string) (*ConfigSet, string) { return c.base(), c.prefix + name }
+
+// Wraps fn to only see options under the view's prefix
+func (c *ConfigSet) underPrefix(fn func(*Option)) func(*Option) {
+	return func(o *Option) {
+		if strings.HasPrefix(o.Name, c.prefix) {
+			fn(o)
+		}
+	}
+}
+
+// Returns a view of the global set where option names are relative to prefix
+func Sub(prefix string) *ConfigSet { return globalConfig.Sub(prefix) }
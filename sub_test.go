@@ -0,0 +1,111 @@
+package configManager
+
+import (
+	"slices"
+	"testing"
+)
+
+// Registers options the way a library would, unaware of where it's mounted
+func registerDB(c *ConfigSet) (*string, *int64) {
+	host, _ := AddOptionToSet(c, "host", "localhost")
+	port, _ := AddOptionToSet(c, "port", int64(5432), Min(1))
+	return host, port
+}
+
+func Test_sub(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "name", "app")
+	db := c.Sub("db")
+	host, port := registerDB(db)
+	replica := db.Sub("replica.")
+	registerDB(replica)
+
+	if c.Lookup("db.host") == nil || c.Lookup("db.replica.port") == nil || db.Prefix() != "db" {
+		t.Fatal("Sub options not registered under the prefix")
+	}
+	if err := c.ParseFromData([]byte(`{"db":{"host":"db.internal","replica":{"port":6543}}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *host != "db.internal" || *port != 5432 || db.Lookup("replica.port").Value.String() != "6543" {
+		t.Fatalf("Sub options not parsed: [%v] [%v]", *host, *port)
+	}
+
+	if err := db.Set("port", "7000"); err != nil || *port != 7000 {
+		t.Fatalf("Set through view failed: [%v] %v", *port, err)
+	}
+	if err := db.Set("port", "0"); err == nil {
+		t.Fatal("Constraint not applied through view")
+	}
+
+	var names []string
+	replica.VisitAll(func(o *Option) { names = append(names, o.Name) })
+	if !slices.Equal(names, []string{"db.replica.host", "db.replica.port"}) {
+		t.Fatalf("Unexpected visited options: %v", names)
+	}
+	if _, err := AddOptionToSet(db, "host", ""); err == nil {
+		t.Fatal("Redefinition through view accepted")
+	}
+}
+
+func Test_subOptionMethods(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "name", " app ")
+	db := c.Sub("db")
+	host, port := registerDB(db)
+	AddOptionToSet(db, "pool.size", int64(4))
+	AddOptionToSet(db, "shards", BlockList{})
+	url, _ := LazyStringSet(db, "url", "pg://${db.host}:${db.port}")
+
+	if err := db.Transform("host", TrimSpace); err != nil {
+		t.Fatal(err)
+	}
+	db.AddTransformer(StripQuotes)
+	if err := db.Describe("port", "Port of the database"); err != nil || c.Lookup("db.port").Usage == "" {
+		t.Fatalf("Describe through view failed: %v", err)
+	}
+	if err := db.Pin("host", SystemTier); err != nil {
+		t.Fatal(err)
+	}
+	if tier, ok := c.PinnedTier("db.host"); !ok || tier != SystemTier {
+		t.Fatal("Pin through view not applied to the parent")
+	}
+	if tier, ok := db.PinnedTier("host"); !ok || tier != SystemTier {
+		t.Fatal("PinnedTier through view failed")
+	}
+
+	data := `{"name": "'app'", "db": {"host": " 'db.internal' ", "shards": [{"name": "a"}, {"name": "b"}]}}`
+	if err := db.ParseFromData([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if *host != "'db.internal'" || c.Lookup("name").Value.String() != "'app'" {
+		t.Fatalf("Transformers through view misapplied: [%v] [%v]", *host, c.Lookup("name").Value)
+	}
+	if len(db.Blocks("shards")) != 2 {
+		t.Fatalf("Blocks through view not found: %v", db.Blocks("shards"))
+	}
+	if got := url.Resolve(); got != "pg://'db.internal':5432" {
+		t.Fatalf("LazyString through view not resolved: %v", got)
+	}
+
+	if err := db.ParseArgs([]string{"--port=6000"}); err != nil || *port != 6000 {
+		t.Fatalf("ParseArgs through view failed: [%v] %v", *port, err)
+	}
+	if err := db.ParseArgs([]string{"--name=x"}); err == nil {
+		t.Fatal("ParseArgs through view accepted an option outside it")
+	}
+
+	if ns := db.Namespaces(); !slices.Equal(ns, []string{"host", "pool", "port", "shards", "url"}) {
+		t.Fatalf("Unexpected view namespaces: %v", ns)
+	}
+	if !db.Wants("pool.max") || db.Wants("name") || !c.Wants("db.pool.max") {
+		t.Fatal("Wants through view misreported")
+	}
+
+	var cfg struct {
+		Host string
+		Pool struct{ Size int64 }
+	}
+	if err := db.Decode(&cfg); err != nil || cfg.Host != "'db.internal'" || cfg.Pool.Size != 4 {
+		t.Fatalf("Decode through view failed: %+v %v", cfg, err)
+	}
+}
@@ -0,0 +1,48 @@
+package configManager
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// A decoding error located in the configuration data, lines and columns count from 1
+// Err is the decoder's error, e.g. a *json.SyntaxError
+type SyntaxError struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *SyntaxError) Unwrap() error { return e.Err }
+
+// Adds the line and column to an error reporting a byte offset into data, other errors are returned as is
+// TOML and XML errors already name their line
+func locate(data []byte, err error) error {
+	var offset int64
+	var se *json.SyntaxError
+	var te *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &se):
+		offset = se.Offset
+	case errors.As(err, &te):
+		offset = te.Offset
+	default:
+		return err
+	}
+	if offset <= 0 || offset > int64(len(data)) {
+		return err
+	}
+
+	// the offset is just past the byte at fault
+	at := data[:offset-1]
+	line := bytes.Count(at, []byte("\n")) + 1
+	column := utf8.RuneCount(at[bytes.LastIndexByte(at, '\n')+1:]) + 1
+	return &SyntaxError{line, column, err}
+}
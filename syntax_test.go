@@ -0,0 +1,39 @@
+package configManager
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func Test_syntaxError(t *testing.T) {
+	cases := []struct {
+		format       fileFormat
+		data         string
+		line, column int
+	}{
+		{JSON, "{\n  \"port\": 80,\n  \"host\": localhost\n}", 3, 11},
+		{JSON, "{\"port\": 80,}", 1, 13},
+		{JSONC, "{\n  // the port\n  \"port\": 80 80\n}", 3, 14},
+		{JSON, "{\n  \"name\": \"héllo\" x\n}", 2, 19},
+	}
+	for _, tc := range cases {
+		c := ConfigSet{Format: tc.format}
+		AddOptionToSet(&c, "port", int64(0))
+		err := c.ParseFromData([]byte(tc.data))
+		var se *SyntaxError
+		if !errors.As(err, &se) || se.Line != tc.line || se.Column != tc.column {
+			t.Fatalf("%q: expected line %d column %d, received %v", tc.data, tc.line, tc.column, err)
+		}
+		var je *json.SyntaxError
+		if !errors.As(err, &je) {
+			t.Fatalf("Decoder error not wrapped: %v", err)
+		}
+	}
+
+	var c ConfigSet
+	err := c.ParseWith(json.Unmarshal, []byte("{\n\"a\": }"))
+	if want := "line 2, column 6: invalid character '}' looking for beginning of value"; err == nil || err.Error() != want {
+		t.Fatalf("Expected [%s] received [%v]", want, err)
+	}
+}
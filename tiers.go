@@ -0,0 +1,87 @@
+package configManager
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// A level of configuration file when several are merged, see ConfigSet.Tiers
+type Tier int
+
+const (
+	SystemTier  Tier = iota // Shared by every user, e.g. /etc/app/config.json
+	UserTier                // The current user's settings, e.g. ~/.config/app/config.json
+	ProjectTier             // Settings of the current project, e.g. ./.app.json
+)
+
+func (t Tier) String() string {
+	switch t {
+	case SystemTier:
+		return "system"
+	case UserTier:
+		return "user"
+	case ProjectTier:
+		return "project"
+	}
+	return fmt.Sprintf("tier %d", int(t))
+}
+
+// Pins the named option to a tier, Save writes it only to that tier's file and never to Location
+// e.g. pin user preferences to UserTier so saving them never touches the system file
+func (c *ConfigSet) Pin(name string, tier Tier) error {
+	c, name = c.owner(name)
+	if _, ok := c.formal[name]; !ok {
+		return fmt.Errorf("No such option: %v", name)
+	}
+	if c.pins == nil {
+		c.pins = make(map[string]Tier)
+	}
+	c.pins[name] = tier
+	return nil
+}
+
+// Returns the tier the named option is pinned to, ok is false if it isn't pinned
+func (c *ConfigSet) PinnedTier(name string) (t Tier, ok bool) {
+	c, name = c.owner(name)
+	t, ok = c.pins[name]
+	return t, ok
+}
+
+// Returns the tiers options are pinned to, lowest first
+func (c *ConfigSet) pinnedTiers() []Tier {
+	tiers := make(map[Tier]struct{})
+	for _, t := range c.pins {
+		tiers[t] = struct{}{}
+	}
+	return slices.Sorted(maps.Keys(tiers))
+}
+
+// Saves only the options pinned to tier, to that tier's file
+// Use it for "save settings" actions that must leave every other file untouched
+func (c *ConfigSet) SaveTier(tier Tier) error {
+	c = c.base()
+	loc := c.Tiers[tier]
+	if loc == "" {
+		return fmt.Errorf("No file location provided for %v configuration", tier)
+	}
+
+	data, err := c.marshal(func(o *Option) bool {
+		t, pinned := c.pins[o.Name]
+		return pinned && t == tier
+	})
+	if err != nil {
+		return fmt.Errorf("Could not save configuration: %v", err)
+	}
+	if err := c.writeFile(loc, c.commented(loc, data)); err != nil {
+		return err
+	}
+	c.emit(Saved, loc, nil)
+	return nil
+}
+
+// Pins the named option of the global set to a tier
+func Pin(name string, tier Tier) error { return globalConfig.Pin(name, tier) }
+
+// Saves only the options of the global set pinned to tier
+func SaveTier(tier Tier) error { return globalConfig.SaveTier(tier) }
@@ -0,0 +1,63 @@
+package configManager
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+)
+
+func Test_tiers(t *testing.T) {
+	var mem memFS
+	mem.WriteFile("etc/app.json", []byte(`{"theme":"light","workers":2,"editor":"vi"}`), 0644)
+	mem.WriteFile("home/.app.json", []byte(`{"theme":"dark","editor":"nano"}`), 0644)
+	mem.WriteFile("project/.app.json", []byte(`{"editor":"code"}`), 0644)
+
+	c := ConfigSet{FS: &mem, Location: "etc/app.json", Tiers: map[Tier]string{
+		UserTier:    "home/.app.json",
+		ProjectTier: "project/.app.json",
+		SystemTier:  "etc/missing.json",
+	}}
+	theme, _ := AddOptionToSet(&c, "theme", "")
+	workers, _ := AddOptionToSet(&c, "workers", int64(0))
+	editor, _ := AddOptionToSet(&c, "editor", "")
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *theme != "dark" || *workers != 2 || *editor != "code" {
+		t.Fatalf("Tiers not merged by precedence: [%v] [%v] [%v]", *theme, *workers, *editor)
+	}
+
+	if err := c.Pin("theme", UserTier); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Pin("nope", UserTier); err == nil {
+		t.Fatal("Unknown option pinned")
+	}
+
+	c.Set("theme", "solarized")
+	c.Set("workers", "8")
+	if err := c.SaveTier(UserTier); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := fs.ReadFile(&mem, "home/.app.json"); string(data) != "{\n  \"theme\": \"solarized\"\n}" {
+		t.Fatalf("Unexpected user file: %s", data)
+	}
+	if data, _ := fs.ReadFile(&mem, "etc/app.json"); string(data) != `{"theme":"light","workers":2,"editor":"vi"}` {
+		t.Fatalf("SaveTier touched the system file: %s", data)
+	}
+
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	var saved ConfigSet
+	saved.FS, saved.Location = &mem, "etc/app.json"
+	sysTheme, _ := AddOptionToSet(&saved, "theme", "unset")
+	saved.Parse()
+	if *sysTheme != "unset" {
+		t.Fatalf("Pinned option written to Location: [%v]", *sysTheme)
+	}
+
+	if err := c.SaveTier(SystemTier + 10); err == nil || errors.Is(err, ErrReadOnlyLocation) {
+		t.Fatalf("Tier without location saved, err: %v", err)
+	}
+}
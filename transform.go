@@ -0,0 +1,83 @@
+package configManager
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Rewrites an option's raw string before it's handed to the option's Value
+// name is the option being set, returning an error rejects the value
+type Transformer func(name, value string) (string, error)
+
+// Adds transformers run on every option of the set, in the order they were added
+// Set-wide transformers run before the option's own transformers, those added to a Sub view only run on its options
+func (c *ConfigSet) AddTransformer(t ...Transformer) {
+	if c.root != nil {
+		for _, fn := range t {
+			c.root.AddTransformer(func(name, value string) (string, error) {
+				if !strings.HasPrefix(name, c.prefix) {
+					return value, nil
+				}
+				return fn(name, value)
+			})
+		}
+		return
+	}
+	c.transformers = append(c.transformers, t...)
+}
+
+// Adds transformers run only on the named option, after the set-wide ones
+func (c *ConfigSet) Transform(name string, t ...Transformer) error {
+	c, name = c.owner(name)
+	o, ok := c.formal[name]
+	if !ok {
+		return fmt.Errorf("No such option: %v", name)
+	}
+	o.transformers = append(o.transformers, t...)
+	return nil
+}
+
+// Runs the transformer pipeline and sets the option
+// Failures are returned as an *OptionError holding the text given
+func (c *ConfigSet) setValue(o *Option, s string) error {
+	raw := s
+	var err error
+	for _, stage := range [][]Transformer{c.transformers, o.transformers} {
+		for _, t := range stage {
+			s, err = t(o.Name, s)
+			if err != nil {
+				return &OptionError{o.Name, raw, err}
+			}
+		}
+	}
+	if err = o.set(s); err != nil {
+		return &OptionError{o.Name, raw, err}
+	}
+	return nil
+}
+
+// Adds transformers run on every option of the global set
+func AddTransformer(t ...Transformer) { globalConfig.AddTransformer(t...) }
+
+// Adds transformers run only on the named option of the global set
+func Transform(name string, t ...Transformer) error { return globalConfig.Transform(name, t...) }
+
+// =-=-= Built in transformers
+
+// Removes leading and trailing white space
+func TrimSpace(_, value string) (string, error) { return strings.TrimSpace(value), nil }
+
+// Removes one pair of matching surrounding quotes, single or double
+func StripQuotes(_, value string) (string, error) {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if first == last && (first == '"' || first == '\'') {
+			return value[1 : len(value)-1], nil
+		}
+	}
+	return value, nil
+}
+
+// Replaces $VAR and ${VAR} with the value of the environment variable
+func ExpandEnv(_, value string) (string, error) { return os.ExpandEnv(value), nil }
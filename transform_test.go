@@ -0,0 +1,45 @@
+package configManager
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func Test_transformers(t *testing.T) {
+	t.Setenv("CM_TEST_HOST", "db.internal")
+
+	var c ConfigSet
+	host, _ := AddOptionToSet(&c, "host", "")
+	size, _ := AddOptionToSet(&c, "size", int64(0))
+
+	c.AddTransformer(TrimSpace, StripQuotes)
+	c.Transform("host", ExpandEnv)
+	c.Transform("size", func(name, value string) (string, error) {
+		// accept sizes written in kilobytes
+		if n, ok := strings.CutSuffix(value, "k"); ok {
+			return n + "000", nil
+		}
+		return value, nil
+	})
+
+	if err := c.ParseFromData([]byte(`{"host":"  \"${CM_TEST_HOST}\" ", "size":"4k"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *host != "db.internal" {
+		t.Fatalf("Option value mismatch, expected: [db.internal] received: [%v]", *host)
+	}
+	if *size != 4000 {
+		t.Fatalf("Option value mismatch, expected: [4000] received: [%v]", *size)
+	}
+
+	c.Transform("host", func(name, value string) (string, error) {
+		return "", fmt.Errorf("%s is read only", name)
+	})
+	if err := c.Set("host", "other"); err == nil {
+		t.Fatal("Set ignored transformer error")
+	}
+	if *host != "db.internal" {
+		t.Fatalf("Rejected value was applied, received: [%v]", *host)
+	}
+}
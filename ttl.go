@@ -0,0 +1,123 @@
+package configManager
+
+import (
+	"fmt"
+	"time"
+)
+
+// What happens to an option once its TTL has passed
+type StalePolicy int
+
+const (
+	KeepStale    StalePolicy = iota // Keep the last known good value, only report it as stale
+	ResetStale                      // Reset the option to its default value
+)
+
+type ttlPolicy struct {
+	ttl    time.Duration
+	policy StalePolicy
+}
+
+// Gives the named option a time to live, after which a set value is considered stale
+// Meant for options fed from caches or remote sources that must be refreshed periodically
+// Staleness is reported by IsStale and Health, ExpireStale applies the policy
+func (c *ConfigSet) SetTTL(name string, ttl time.Duration, policy StalePolicy) error {
+	if c.root != nil {
+		return c.root.SetTTL(c.prefix+name, ttl, policy)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.formal[name]; !ok {
+		return fmt.Errorf("No such option: %v", name)
+	}
+	if c.ttls == nil {
+		c.ttls = make(map[string]ttlPolicy)
+	}
+	c.ttls[name] = ttlPolicy{ttl, policy}
+	return nil
+}
+
+// Reports whether the named option was set longer than its TTL ago
+// Options without a TTL or not set at all are never stale
+func (c *ConfigSet) IsStale(name string) bool {
+	if c.root != nil {
+		return c.root.IsStale(c.prefix + name)
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.isStale(name)
+}
+
+func (c *ConfigSet) isStale(name string) bool {
+	p, ok := c.ttls[name]
+	if !ok {
+		return false
+	}
+	at, ok := c.setAt[name]
+	if !ok {
+		return false
+	}
+	return c.now().Sub(at) >= p.ttl
+}
+
+// Applies the stale policy of every option whose TTL has passed
+// Options with ResetStale go back to their default and are no longer considered set, Explain reports them from "default"
+// Returns the names of the options that were reset, each one is reported by a Changed event from "ttl"
+func (c *ConfigSet) ExpireStale() []string {
+	if c.root != nil {
+		return c.root.ExpireStale()
+	}
+	c.mu.Lock()
+	var reset []string
+	for _, o := range c.sortOptions(c.actual) {
+		if !c.isStale(o.Name) || c.ttls[o.Name].policy != ResetStale {
+			continue
+		}
+		o.set(o.DefValue)
+		delete(c.actual, o.Name)
+		delete(c.setAt, o.Name)
+		delete(c.origins, o.Name)
+		reset = append(reset, o.Name)
+	}
+	if len(reset) > 0 {
+		c.publish()
+	}
+	c.mu.Unlock()
+
+	for _, name := range reset {
+		c.emit(Changed, "ttl", nil, name)
+	}
+	return reset
+}
+
+// Summarizes the freshness of the set
+type Health struct {
+	Stale   []string             // Options past their TTL, in lexicographical order
+	Expires map[string]time.Time // When each fresh option with a TTL becomes stale
+}
+
+// Reports whether no option is stale
+func (h Health) Ok() bool { return len(h.Stale) == 0 }
+
+// Returns the freshness of every option with a TTL
+func (c *ConfigSet) Health() Health {
+	if c.root != nil {
+		return c.root.Health()
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	h := Health{Expires: make(map[string]time.Time)}
+	for _, o := range c.sortOptions(c.formal) {
+		p, ok := c.ttls[o.Name]
+		if !ok {
+			continue
+		}
+		if c.isStale(o.Name) {
+			h.Stale = append(h.Stale, o.Name)
+		} else if at, set := c.setAt[o.Name]; set {
+			h.Expires[o.Name] = at.Add(p.ttl)
+		}
+	}
+	return h
+}
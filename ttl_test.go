@@ -0,0 +1,60 @@
+package configManager
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ttl(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := ConfigSet{clock: func() time.Time { return now }}
+
+	rate, _ := AddOptionToSet(&c, "rate", int64(10))
+	region, _ := AddOptionToSet(&c, "region", "eu")
+	c.SetTTL("rate", time.Minute, ResetStale)
+	c.SetTTL("region", time.Minute, KeepStale)
+
+	c.Set("rate", "50")
+	c.Set("region", "us")
+
+	if h := c.Health(); !h.Ok() || !h.Expires["rate"].Equal(now.Add(time.Minute)) {
+		t.Fatalf("Unexpected health before expiry: %+v", h)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if !c.IsStale("rate") || !c.IsStale("region") {
+		t.Fatal("Options not reported stale after TTL")
+	}
+	if h := c.Health(); !slices.Equal(h.Stale, []string{"rate", "region"}) {
+		t.Fatalf("Unexpected stale options: %v", h.Stale)
+	}
+
+	if e, _ := c.Explain("region"); !e.Stale || !strings.Contains(e.String(), "stale") {
+		t.Fatalf("Explain does not report staleness: %v", e)
+	}
+
+	events, stop := c.Events()
+	defer stop()
+	if reset := c.ExpireStale(); !slices.Equal(reset, []string{"rate"}) {
+		t.Fatalf("Unexpected reset options: %v", reset)
+	}
+	if e := <-events; e.Kind != Changed || e.Source != "ttl" || !slices.Equal(e.Options, []string{"rate"}) {
+		t.Fatalf("Expiry not reported: %+v", e)
+	}
+	if v, _ := c.View().Get("rate"); v != int64(10) {
+		t.Fatalf("Expiry not visible in the view: %v", v)
+	}
+	if *rate != 10 || *region != "us" {
+		t.Fatalf("Stale policies not applied: [%v] [%v]", *rate, *region)
+	}
+	if e, _ := c.Explain("rate"); e.Source != "default" || e.Stale {
+		t.Fatalf("Expired option not explained as default: %v", e)
+	}
+
+	c.Set("region", "ap")
+	if c.IsStale("region") {
+		t.Fatal("Refreshed option still stale")
+	}
+}
@@ -0,0 +1,120 @@
+package configManager
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// Sets the help message shown by WriteUsage for the named option
+// Required and Deprecated markers are set on the Option returned by Lookup
+func (c *ConfigSet) Describe(name, usage string) error {
+	c, name = c.owner(name)
+	o, ok := c.formal[name]
+	if !ok {
+		return fmt.Errorf("No such option: %v", name)
+	}
+	o.Usage = usage
+	return nil
+}
+
+// Returns a name for the option's type the way the flag package does, empty for booleans
+// A name in back quotes inside the usage, e.g. "path of the `file`", is used instead
+func usageType(o *Option) (name, usage string) {
+	usage = o.Usage
+	if start := strings.IndexByte(usage, '`'); start >= 0 {
+		if end := strings.IndexByte(usage[start+1:], '`'); end >= 0 {
+			end += start + 1
+			name = usage[start+1 : end]
+			return name, usage[:start] + name + usage[end+1:]
+		}
+	}
+
+	switch o.Value.Get().(type) {
+	case bool:
+		return "", usage
+	case string:
+		return "string", usage
+	case int, int8, int16, int32, int64:
+		return "int", usage
+	case uint, uint8, uint16, uint32, uint64:
+		return "uint", usage
+	case float32, float64:
+		return "float", usage
+	}
+	return "value", usage
+}
+
+// Reports whether the option's default is its type's zero value, which usage text leaves out
+func defaultIsZero(o *Option) (zero bool) {
+	defer func() {
+		if recover() != nil {
+			zero = false
+		}
+	}()
+	typ := reflect.TypeOf(o.Value)
+	var z reflect.Value
+	if typ.Kind() == reflect.Pointer {
+		z = reflect.New(typ.Elem())
+	} else {
+		z = reflect.Zero(typ)
+	}
+	return o.DefValue == z.Interface().(Value).String()
+}
+
+// Writes usage text for every option in lexicographical order, formatted like flag.PrintDefaults
+// so applications presenting both flags and configuration keep one help format
+//
+//	-workers int
+//	  	number of workers (default 4) (required)
+func (c *ConfigSet) WriteUsage(w io.Writer) {
+	var b strings.Builder
+	c.VisitAll(func(o *Option) {
+		fmt.Fprintf(&b, "  -%s", o.Name)
+		name, usage := usageType(o)
+		if name != "" {
+			b.WriteString(" " + name)
+		}
+		b.WriteString("\n    \t")
+		b.WriteString(strings.ReplaceAll(usage, "\n", "\n    \t"))
+
+		if !defaultIsZero(o) {
+			if _, ok := o.Value.Get().(string); ok {
+				fmt.Fprintf(&b, " (default %q)", o.DefValue)
+			} else {
+				fmt.Fprintf(&b, " (default %v)", o.DefValue)
+			}
+		}
+		if o.Required {
+			b.WriteString(" (required)")
+		}
+		if o.Deprecated != "" {
+			fmt.Fprintf(&b, " (deprecated: %s)", o.Deprecated)
+		}
+		if o.Experimental {
+			b.WriteString(" (experimental)")
+		}
+		b.WriteByte('\n')
+	})
+	io.WriteString(w, b.String())
+}
+
+// Writes usage text for every option to the set's output, or standard error if none was set
+func (c *ConfigSet) PrintDefaults() {
+	w := c.Output()
+	if w == nil {
+		w = os.Stderr
+	}
+	c.WriteUsage(w)
+}
+
+// Sets the help message of the named option of the global set
+func Describe(name, usage string) error { return globalConfig.Describe(name, usage) }
+
+// Writes usage text for every option of the global set
+func WriteUsage(w io.Writer) { globalConfig.WriteUsage(w) }
+
+// Writes usage text for every option of the global set to its output
+func PrintDefaults() { globalConfig.PrintDefaults() }
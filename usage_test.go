@@ -0,0 +1,41 @@
+package configManager
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func Test_writeUsage(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "debug", false)
+	AddOptionToSet(&c, "name", "app")
+	AddOptionToSet(&c, "workers", int64(4))
+	AddOptionToSet(&c, "log", "")
+	c.Describe("debug", "enable debug output")
+	c.Describe("name", "service name")
+	c.Describe("workers", "number of workers")
+	c.Describe("log", "path of the log `file`")
+	c.Lookup("workers").Required = true
+	c.Lookup("debug").Deprecated = "use log.level"
+
+	var out strings.Builder
+	c.WriteUsage(&out)
+
+	want := "  -debug\n    \tenable debug output (deprecated: use log.level)\n" +
+		"  -log file\n    \tpath of the log file\n" +
+		"  -name string\n    \tservice name (default \"app\")\n" +
+		"  -workers int\n    \tnumber of workers (default 4) (required)\n"
+	if out.String() != want {
+		t.Fatalf("Unexpected usage:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	// flags defined from the set carry the help messages
+	var flags strings.Builder
+	f := c.ToFlagSet("app", flag.ContinueOnError)
+	f.SetOutput(&flags)
+	f.PrintDefaults()
+	if !strings.Contains(flags.String(), "\tservice name (default app)\n") {
+		t.Fatalf("Flag usage differs:\n%s", flags.String())
+	}
+}
@@ -0,0 +1,84 @@
+package configManager
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Reported by Validate for required options that weren't set
+var ErrRequired = errors.New("required option not set")
+
+// Lists every problem Validate found
+// errors.Is matches the error of any problem, e.g. ErrRange or ErrRequired
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		lines[i] = p.String()
+	}
+	return fmt.Sprintf("%d invalid options: %s", len(e.Problems), strings.Join(lines, "; "))
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Problems))
+	for i, p := range e.Problems {
+		errs[i] = p.Err
+	}
+	return errs
+}
+
+// Checks the current values of every option, however they were set, and returns a *ValidationError listing all problems
+// Required options must be set, values must satisfy their constraints and the configuration every policy
+// Each problem's Source is where the option got its value, "default" when it wasn't set
+func (c *ConfigSet) Validate() error {
+	if c.root != nil {
+		return c.root.Validate()
+	}
+	err := c.validateAll()
+	if err != nil {
+		c.notify(err)
+	}
+	return err
+}
+
+func (c *ConfigSet) validateAll() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var problems []Problem
+	for _, o := range c.sortOptions(c.formal) {
+		source := "default"
+		if _, set := c.actual[o.Name]; set {
+			source = c.origins[o.Name]
+		} else if o.Required {
+			problems = append(problems, Problem{Source: source, Option: o.Name, Err: ErrRequired})
+			continue
+		}
+		if err := checkConstraints(o.Value.Get(), o.constraints); err != nil {
+			problems = append(problems, Problem{Source: source, Option: o.Name, Err: err})
+		}
+	}
+
+	err := c.checkPolicies()
+	var pe *PolicyError
+	switch {
+	case errors.As(err, &pe):
+		for _, v := range pe.Violations {
+			problems = append(problems, Problem{Source: "policy " + v.Policy, Option: v.Option, Err: fmt.Errorf("%w: %s", ErrPolicy, v.Reason)})
+		}
+	case err != nil:
+		problems = append(problems, Problem{Source: "policies", Err: err})
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{problems}
+	}
+	return nil
+}
+
+// Checks the current values of every option of the global set
+func Validate() error { return globalConfig.Validate() }
@@ -0,0 +1,45 @@
+package configManager
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_validate(t *testing.T) {
+	var c ConfigSet
+	port, _ := AddOptionToSet(&c, "port", int64(80), Min(1), Max(65535))
+	AddOptionToSet(&c, "name", "")
+	AddOptionToSet(&c, "tls.cert", "")
+	AddOptionToSet(&c, "tls.key", "")
+	c.Lookup("name").Required = true
+	c.AllOrNone("tls", "tls.cert", "tls.key")
+
+	c.Set("tls.cert", "a.pem")
+	*port = 0
+
+	err := c.Validate()
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Problems) != 3 {
+		t.Fatalf("Expected 3 problems received %v", err)
+	}
+	if !errors.Is(err, ErrRequired) || !errors.Is(err, ErrRange) || !errors.Is(err, ErrPolicy) {
+		t.Fatalf("Problems not wrapped: %v", err)
+	}
+	for _, want := range []string{
+		"default: name: required option not set",
+		"default: port: value outside allowed range: 0 is less than 1",
+		"policy tls: tls.key: rejected by policy: tls.cert, tls.key must be set together, missing tls.key",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("Missing %q from %v", want, err)
+		}
+	}
+
+	c.Set("name", "api")
+	c.Set("port", "443")
+	c.Set("tls.key", "a.key")
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
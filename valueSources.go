@@ -0,0 +1,160 @@
+package configManager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Loads a ValueSource into option names and values, like decode does for files
+func (c *ConfigSet) loadValues(ctx context.Context, name string, s ValueSource) (map[string]any, map[string]json.RawMessage, error) {
+	m, err := s.LoadValues(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+	d, err := c.applySections(c.flatten(m))
+	return d, nil, err
+}
+
+// Satisfies Source for the built in ValueSources, which are only read through LoadValues
+type valuesOnly struct{}
+
+func (valuesOnly) Load() ([]byte, error) {
+	return nil, errors.New("source provides values, see ValueSource")
+}
+
+// =-=-= Contributions
+
+// A value a source provided for an option, see Explanation
+type Contribution struct {
+	Source string
+	Value  string
+}
+
+// Records that source provided v for the named option
+func (c *ConfigSet) contribute(name, source string, v any, raw json.RawMessage) {
+	if c.contributions == nil {
+		c.contributions = make(map[string][]Contribution)
+	}
+	c.contributions[name] = append(c.contributions[name], Contribution{source, stringify(v, raw)})
+}
+
+// =-=-= Built in value sources
+
+type mapSource struct {
+	valuesOnly
+	name   string
+	values map[string]any
+}
+
+// Returns a source providing fixed values, e.g. application defaults computed at startup added last
+func MapSource(name string, values map[string]any) Source {
+	return mapSource{name: name, values: values}
+}
+
+func (m mapSource) Name() string                                       { return m.name }
+func (m mapSource) LoadValues(context.Context) (map[string]any, error) { return m.values, nil }
+
+type envSource struct {
+	valuesOnly
+	c      *ConfigSet
+	prefix string
+}
+
+// Returns a source reading each option from an environment variable named after it,
+// prefix followed by the name in upper case with dots and dashes replaced by underscores,
+// e.g. APP_SERVER_PORT for "server.port" with prefix "APP_"
+func (c *ConfigSet) EnvSource(prefix string) Source {
+	if c.root != nil {
+		return c.root.EnvSource(prefix)
+	}
+	return envSource{c: c, prefix: prefix}
+}
+
+// Returns a source reading the global set's options from environment variables
+func EnvSource(prefix string) Source { return globalConfig.EnvSource(prefix) }
+
+func (e envSource) Name() string { return "environment" }
+
+func (e envSource) LoadValues(context.Context) (map[string]any, error) {
+	values := make(map[string]any)
+	for name, o := range e.c.formal {
+		key := e.prefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(name))
+		if s, ok := os.LookupEnv(key); ok {
+			values[name] = typedText(o, s)
+		}
+	}
+	return values, nil
+}
+
+type argsSource struct {
+	valuesOnly
+	c    *ConfigSet
+	args []string
+}
+
+// Returns a source reading --key=value arguments, a bool option may be given as --key alone
+// Unlike ParseArgs the arguments are a source like any other, so sources taking precedence can still override them
+func (c *ConfigSet) ArgsSource(args []string) Source {
+	if c.root != nil {
+		return c.root.ArgsSource(args)
+	}
+	return argsSource{c: c, args: args}
+}
+
+// Returns a source reading --key=value arguments for the global set
+func ArgsSource(args []string) Source { return globalConfig.ArgsSource(args) }
+
+func (a argsSource) Name() string { return "arguments" }
+
+func (a argsSource) LoadValues(context.Context) (map[string]any, error) {
+	values := make(map[string]any, len(a.args))
+	for _, arg := range a.args {
+		kv, ok := strings.CutPrefix(arg, "--")
+		if !ok || kv == "" {
+			return nil, fmt.Errorf("invalid argument %q, expected --key=value", arg)
+		}
+		key, value, hasValue := strings.Cut(kv, "=")
+		o, ok := a.c.formal[key]
+		if !ok {
+			return nil, fmt.Errorf("No such option: %v", key)
+		}
+		if !hasValue {
+			if _, isBool := o.Value.Get().(bool); !isBool {
+				return nil, fmt.Errorf("missing value for %s, expected --%s=value", key, key)
+			}
+			value = "true"
+		}
+		values[key] = typedText(o, value)
+	}
+	return values, nil
+}
+
+// Converts text to the kind of value o holds, so it passes a Strict Coercion like a typed file value
+// Text that doesn't convert is returned as is and rejected when it's set
+func typedText(o *Option, s string) any {
+	switch v := reflect.ValueOf(o.Value.Get()); {
+	case v.Kind() == reflect.Bool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case v.CanInt():
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	case v.CanUint():
+		if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return n
+		}
+	case v.CanFloat():
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
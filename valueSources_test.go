@@ -0,0 +1,87 @@
+package configManager
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_valueSources(t *testing.T) {
+	var mem memFS
+	mem.WriteFile("app.json", []byte(`{"server": {"port": 8080, "host": "file"}, "debug": true}`), 0o644)
+	t.Setenv("APP_SERVER_PORT", "9090")
+
+	c := ConfigSet{FS: &mem}
+	c.SetCoercion(Strict)
+	port, _ := AddOptionToSet(&c, "server.port", int64(80))
+	host, _ := AddOptionToSet(&c, "server.host", "localhost")
+	debug, _ := AddOptionToSet(&c, "debug", false)
+	name, _ := AddOptionToSet(&c, "name", "")
+	c.AddSource(
+		c.ArgsSource([]string{"--debug=false"}),
+		c.EnvSource("APP_"),
+		FileSource{&mem, "app.json"},
+		MapSource("defaults", map[string]any{"server": map[string]any{"host": "default"}, "name": "app"}),
+	)
+
+	if err := c.Parse(); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 9090 || *host != "file" || *debug || *name != "app" {
+		t.Fatalf("Unexpected values: [%v] [%v] [%v] [%v]", *port, *host, *debug, *name)
+	}
+
+	e, _ := c.Explain("server.port")
+	want := []Contribution{{"environment", "9090"}, {"app.json", "8080"}}
+	if e.Source != "environment" || len(e.Contributions) != 2 || e.Contributions[0] != want[0] || e.Contributions[1] != want[1] {
+		t.Fatalf("Unexpected explanation: %+v", e)
+	}
+	if s := e.String(); !strings.HasSuffix(s, `, overrides "8080" from app.json`) {
+		t.Fatalf("Unexpected explanation: %s", s)
+	}
+	if e, _ := c.Explain("server.host"); e.Source != "app.json" || len(e.Contributions) != 2 {
+		t.Fatalf("Unexpected explanation: %+v", e)
+	}
+
+	// reloads read the same sources
+	mem.WriteFile("app.json", []byte(`{"server": {"port": 8080}}`), 0o644)
+	if _, err := c.reloadFile(); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 9090 || *host != "default" {
+		t.Fatalf("Unexpected values after reload: [%v] [%v]", *port, *host)
+	}
+}
+
+type failingSource struct{ valuesOnly }
+
+func (failingSource) Name() string { return "remote" }
+func (failingSource) LoadValues(ctx context.Context) (map[string]any, error) {
+	return nil, ctx.Err()
+}
+
+func Test_valueSourcesWithoutFiles(t *testing.T) {
+	var c ConfigSet
+	port, _ := AddOptionToSet(&c, "port", int64(80))
+	c.AddSource(MapSource("remote", map[string]any{"port": 8080}))
+	if err := c.Parse(); err != nil || *port != 8080 {
+		t.Fatalf("Value source without files not parsed: [%v] %v", *port, err)
+	}
+
+	var d ConfigSet
+	AddOptionToSet(&d, "port", int64(80))
+	d.AddSource(failingSource{})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := d.ParseContext(ctx); !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "remote") {
+		t.Fatalf("Context not handed to the source: %v", err)
+	}
+
+	var e ConfigSet
+	AddOptionToSet(&e, "port", int64(80))
+	e.AddSource(e.ArgsSource([]string{"--port"}))
+	if err := e.Parse(); err == nil {
+		t.Fatal("Argument without value accepted")
+	}
+}
@@ -105,3 +105,109 @@ func Test_int64Val(t *testing.T) {
 	}
 }
 
+
+func Test_uint64Val(t *testing.T) {
+	var f uint64
+	v := newUint64Value(&f)
+
+	if err := valueTester(
+		v,
+		[]string{
+			"0",
+			"69",
+			fmt.Sprint(uint64(math.MaxUint64)),
+		},
+
+		[]string{
+			"",
+			"-1",
+			"6.9",
+			"18446744073709551616",
+		},
+		&f,
+		func(a string, b uint64) bool { return a == strconv.FormatUint(b, 10) },
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_uint8Val(t *testing.T) {
+	var f uint8
+	v := newUint8Value(&f)
+
+	if err := valueTester(
+		v,
+		[]string{
+			"0",
+			"255",
+		},
+
+		[]string{
+			"256",
+			"-1",
+		},
+		&f,
+		func(a string, b uint8) bool { return a == strconv.FormatUint(uint64(b), 10) },
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_uintOption(t *testing.T) {
+	var c ConfigSet
+	workers, err := AddOptionToSet(&c, "workers", uint(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFromData([]byte(`{"workers": 16}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *workers != 16 {
+		t.Fatalf("Option value mismatch, expected: [16] received: %v", *workers)
+	}
+
+	var other ConfigSet
+	AddOptionToSet(&other, "workers", uint(8))
+	if err := other.ParseFromData([]byte(`{"workers": -1}`)); err == nil {
+		t.Fatal("Negative value accepted")
+	}
+}
+
+func Test_int16Val(t *testing.T) {
+	var f int16
+	v := newInt16Value(&f)
+
+	if err := valueTester(
+		v,
+		[]string{
+			"69",
+			"-42",
+			fmt.Sprint(math.MaxInt16),
+			fmt.Sprint(math.MinInt16),
+		},
+
+		[]string{
+			"",
+			"6.9",
+			fmt.Sprint(math.MaxInt16 + 1),
+		},
+		&f,
+		func(a string, b int16) bool { return a == strconv.FormatInt(int64(b), 10) },
+	); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_intOption(t *testing.T) {
+	var c ConfigSet
+	count, err := AddOptionToSet(&c, "count", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFromData([]byte(`{"count": 12}`)); err != nil {
+		t.Fatal(err)
+	}
+	if *count != 12 {
+		t.Fatalf("Option value mismatch, expected: [12] received: %v", *count)
+	}
+}
@@ -0,0 +1,153 @@
+package configManager
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Exposes a set through the getters of github.com/spf13/viper, so code migrating from viper can switch
+// backends first and rewrite call sites later, e.g. by declaring an interface with the methods it uses
+//
+// Like viper the getters never fail, they convert what they can and return the zero value otherwise
+// Unlike viper keys are matched exactly, option names are case sensitive
+// Values are read from the set's latest snapshot, an adapter is safe for concurrent use
+type ViperAdapter struct {
+	c      *ConfigSet
+	prefix string
+}
+
+// Returns a viper-like adapter over the set, relative to its prefix for a Sub view
+func (c *ConfigSet) Viper() *ViperAdapter {
+	if c.root != nil {
+		return &ViperAdapter{c.root, c.prefix}
+	}
+	return &ViperAdapter{c, ""}
+}
+
+// Returns a viper-like adapter over the global set
+func Viper() *ViperAdapter { return globalConfig.Viper() }
+
+// Returns an adapter whose keys are relative to key, nil if no option lives under it like viper's Sub
+func (v *ViperAdapter) Sub(key string) *ViperAdapter {
+	prefix := v.prefix + strings.TrimSuffix(key, NamespaceSeparator) + NamespaceSeparator
+	for _, name := range v.c.View().Names() {
+		if strings.HasPrefix(name, prefix) {
+			return &ViperAdapter{v.c, prefix}
+		}
+	}
+	return nil
+}
+
+// Reports whether the option was set by any source, or any option under key when it's a namespace
+func (v *ViperAdapter) IsSet(key string) bool {
+	s := v.c.View()
+	if s.IsSet(v.prefix + key) {
+		return true
+	}
+	prefix := v.prefix + key + NamespaceSeparator
+	for _, name := range s.Names() {
+		if strings.HasPrefix(name, prefix) && s.IsSet(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns every option name relative to the adapter's prefix, in lexicographical order
+func (v *ViperAdapter) AllKeys() []string {
+	var keys []string
+	for _, name := range v.c.View().Names() {
+		if key, ok := strings.CutPrefix(name, v.prefix); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Returns the option's value as returned by its Get, nil if it doesn't exist
+func (v *ViperAdapter) Get(key string) any {
+	value, _ := v.c.View().Get(v.prefix + key)
+	return value
+}
+
+// Returns the option's value as a string, as it would be written to the file
+func (v *ViperAdapter) GetString(key string) string { return v.c.View().String(v.prefix + key) }
+
+func (v *ViperAdapter) GetBool(key string) bool {
+	switch value := v.Get(key).(type) {
+	case bool:
+		return value
+	case string:
+		b, _ := strconv.ParseBool(value)
+		return b
+	}
+	f, ok := viperNumber(v.Get(key))
+	return ok && f != 0
+}
+
+func (v *ViperAdapter) GetInt(key string) int { return int(v.GetInt64(key)) }
+
+func (v *ViperAdapter) GetInt64(key string) int64 {
+	switch value := v.Get(key).(type) {
+	case int64:
+		return value
+	case string:
+		n, _ := strconv.ParseInt(strings.TrimSpace(value), 0, 64)
+		return n
+	}
+	f, _ := viperNumber(v.Get(key))
+	return int64(f)
+}
+
+func (v *ViperAdapter) GetFloat64(key string) float64 {
+	if s, ok := v.Get(key).(string); ok {
+		f, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return f
+	}
+	f, _ := viperNumber(v.Get(key))
+	return f
+}
+
+// Accepts numbers as nanoseconds and strings such as "1m30s" like viper
+func (v *ViperAdapter) GetDuration(key string) time.Duration {
+	switch value := v.Get(key).(type) {
+	case time.Duration:
+		return value
+	case string:
+		d, _ := time.ParseDuration(strings.TrimSpace(value))
+		return d
+	}
+	f, _ := viperNumber(v.Get(key))
+	return time.Duration(f)
+}
+
+// Splits strings on white space like viper
+func (v *ViperAdapter) GetStringSlice(key string) []string {
+	switch value := v.Get(key).(type) {
+	case []string:
+		return value
+	case string:
+		return strings.Fields(value)
+	case []any:
+		list := make([]string, len(value))
+		for i, e := range value {
+			list[i] = fmt.Sprint(e)
+		}
+		return list
+	}
+	return nil
+}
+
+// Returns the numeric value of v, bools count as 0 and 1, ok is false when it isn't a number
+func viperNumber(v any) (f float64, ok bool) {
+	if b, isBool := v.(bool); isBool {
+		if b {
+			return 1, true
+		}
+		return 0, true
+	}
+	return toFloat(reflect.ValueOf(v))
+}
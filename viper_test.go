@@ -0,0 +1,60 @@
+package configManager
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func Test_viperAdapter(t *testing.T) {
+	var c ConfigSet
+	AddOptionToSet(&c, "db.host", "localhost")
+	AddOptionToSet(&c, "db.port", int64(5432))
+	AddOptionToSet(&c, "db.timeout", "1m30s")
+	AddOptionToSet(&c, "ratio", 0.5)
+	AddOptionToSet(&c, "debug", false)
+	AddOptionToSet(&c, "count", "42")
+	c.Set("db.port", "6543")
+	c.Set("debug", "true")
+
+	v := c.Viper()
+	if s := v.GetString("db.host"); s != "localhost" {
+		t.Fatalf("GetString: %q", s)
+	}
+	if n := v.GetInt("db.port"); n != 6543 {
+		t.Fatalf("GetInt: %d", n)
+	}
+	if n := v.GetInt("count"); n != 42 {
+		t.Fatalf("GetInt from string: %d", n)
+	}
+	if f := v.GetFloat64("ratio"); f != 0.5 {
+		t.Fatalf("GetFloat64: %g", f)
+	}
+	if !v.GetBool("debug") {
+		t.Fatal("GetBool: false")
+	}
+	if d := v.GetDuration("db.timeout"); d != 90*time.Second {
+		t.Fatalf("GetDuration: %v", d)
+	}
+	if v.GetInt("missing") != 0 || v.Get("missing") != nil {
+		t.Fatal("Missing keys should give zero values")
+	}
+
+	if !v.IsSet("db.port") || v.IsSet("db.host") || !v.IsSet("db") || v.IsSet("ratio") {
+		t.Fatal("IsSet doesn't follow set options")
+	}
+
+	db := v.Sub("db")
+	if db == nil || db.GetInt("port") != 6543 || !db.IsSet("port") {
+		t.Fatal("Sub doesn't resolve relative keys")
+	}
+	if keys := db.AllKeys(); !slices.Equal(keys, []string{"host", "port", "timeout"}) {
+		t.Fatalf("AllKeys: %v", keys)
+	}
+	if v.Sub("cache") != nil {
+		t.Fatal("Sub of an empty namespace should be nil")
+	}
+	if c.Sub("db").Viper().GetString("host") != "localhost" {
+		t.Fatal("Adapter of a Sub view isn't relative to it")
+	}
+}
@@ -0,0 +1,161 @@
+package configManager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A named weight of a WeightedList
+type Weighted struct {
+	Name   string
+	Weight int
+}
+
+// An ordered list of names with positive weights, such as load balancer backends written "backend1=5,backend2=1"
+// Names are unique, the file may also hold an object {"backend1": 5, "backend2": 1} or a list of "name=weight" strings
+type WeightedList []Weighted
+
+// Returns the sum of every weight
+func (l WeightedList) Total() int {
+	total := 0
+	for _, w := range l {
+		total += w.Weight
+	}
+	return total
+}
+
+// Returns the name owning n, a number in [0, Total), so a uniformly random n picks names in proportion to their weight
+// Returns an empty string when n is out of range
+func (l WeightedList) Pick(n int) string {
+	if n < 0 {
+		return ""
+	}
+	for _, w := range l {
+		if n < w.Weight {
+			return w.Name
+		}
+		n -= w.Weight
+	}
+	return ""
+}
+
+// Returns the list as "name=weight" pairs separated by commas
+func (l WeightedList) String() string {
+	parts := make([]string, len(l))
+	for i, w := range l {
+		parts[i] = w.Name + "=" + strconv.Itoa(w.Weight)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Parses a single "name=weight" entry
+func parseWeighted(s string) (Weighted, error) {
+	name, weight, ok := strings.Cut(s, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return Weighted{}, fmt.Errorf("%w: %q is not name=weight", ErrParse, s)
+	}
+	w, err := strconv.Atoi(strings.TrimSpace(weight))
+	if err != nil {
+		return Weighted{}, fmt.Errorf("%w: invalid weight of %s", ErrParse, name)
+	}
+	return Weighted{name, w}, nil
+}
+
+// Decodes a JSON object of weights keeping its order
+func weightedFromObject(data []byte) (WeightedList, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	var l WeightedList
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var n json.Number
+		if err := dec.Decode(&n); err != nil {
+			return nil, fmt.Errorf("invalid weight of %v", tok)
+		}
+		w, err := strconv.Atoi(n.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight of %v", tok)
+		}
+		l = append(l, Weighted{tok.(string), w})
+	}
+	return l, nil
+}
+
+// Checks weights are positive and names unique
+func validWeighted(l WeightedList) error {
+	seen := make(map[string]bool, len(l))
+	for _, w := range l {
+		if w.Weight <= 0 {
+			return fmt.Errorf("%w: weight of %s must be positive, got %d", ErrParse, w.Name, w.Weight)
+		}
+		if seen[w.Name] {
+			return fmt.Errorf("%w: %s listed twice", ErrParse, w.Name)
+		}
+		seen[w.Name] = true
+	}
+	return nil
+}
+
+// =-=-= weightedListValue
+
+type weightedListValue WeightedList
+
+func newWeightedListValue(p *WeightedList) *weightedListValue { return (*weightedListValue)(p) }
+
+// Accepts "name=weight" pairs separated by commas, a JSON object of weights or a JSON array of pairs
+func (v *weightedListValue) Set(s string) error {
+	var l WeightedList
+	switch t := strings.TrimSpace(s); {
+	case strings.HasPrefix(t, "{"):
+		var err error
+		if l, err = weightedFromObject([]byte(t)); err != nil {
+			return fmt.Errorf("%w: %v", ErrParse, err)
+		}
+	case strings.HasPrefix(t, "["):
+		var pairs []string
+		if err := json.Unmarshal([]byte(t), &pairs); err != nil {
+			return ErrParse
+		}
+		t = strings.Join(pairs, ",")
+		fallthrough
+	case t != "":
+		for _, p := range strings.Split(t, ",") {
+			w, err := parseWeighted(p)
+			if err != nil {
+				return err
+			}
+			l = append(l, w)
+		}
+	}
+
+	if err := validWeighted(l); err != nil {
+		return err
+	}
+	*v = weightedListValue(l)
+	return nil
+}
+
+func (v weightedListValue) Get() any { return slices.Clone(WeightedList(v)) }
+
+func (v weightedListValue) String() string { return WeightedList(v).String() }
+
+// Saved as an object so the file reads {"backend1": 5, "backend2": 1}
+func (v weightedListValue) MarshalConfig() (any, error) {
+	m := make(OrderedMap, len(v))
+	for i, w := range v {
+		m[i] = MapEntry{w.Name, w.Weight}
+	}
+	return m, nil
+}
@@ -0,0 +1,60 @@
+package configManager
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func Test_weightedListVal(t *testing.T) {
+	var l WeightedList
+	v := newWeightedListValue(&l)
+	want := WeightedList{{"backend1", 5}, {"backend2", 1}}
+
+	for _, in := range []string{
+		"backend1=5,backend2=1",
+		" backend1 = 5 , backend2=1 ",
+		`{"backend1": 5, "backend2": 1}`,
+		`["backend1=5", "backend2=1"]`,
+	} {
+		if err := v.Set(in); err != nil {
+			t.Fatalf("Set(%q) rejected valid value: %v", in, err)
+		}
+		if got := v.Get().(WeightedList); !slices.Equal(got, want) {
+			t.Fatalf("Set(%q) produced %v", in, got)
+		}
+	}
+
+	for _, in := range []string{"a=0", "a=-1", "a=1,a=2", "a", "=3", "a=x", `{"a": 1.5}`, `{"a": 1, "a": 2}`} {
+		if err := v.Set(in); !errors.Is(err, ErrParse) {
+			t.Fatalf("Set(%q) accepted invalid value: %v", in, err)
+		}
+	}
+}
+
+func Test_weightedListPick(t *testing.T) {
+	l := WeightedList{{"a", 3}, {"b", 1}}
+	if l.Total() != 4 || l.Pick(0) != "a" || l.Pick(2) != "a" || l.Pick(3) != "b" || l.Pick(4) != "" || l.Pick(-1) != "" {
+		t.Fatal("Unexpected weighted pick")
+	}
+}
+
+func Test_weightedListOption(t *testing.T) {
+	var c ConfigSet
+	backends, err := AddOptionToSet(&c, "backends", WeightedList{{"local", 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ParseFromData([]byte(`{"backends": {"b2": 1, "b1": 5}}`)); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(*backends, WeightedList{{"b2", 1}, {"b1", 5}}) {
+		t.Fatalf("Unexpected value: %v", *backends)
+	}
+
+	data, _ := c.SaveTo()
+	if !strings.Contains(string(data), `"backends": {`) || strings.Index(string(data), "b2") > strings.Index(string(data), "b1") {
+		t.Fatalf("List not saved as an ordered object:\n%s", data)
+	}
+}
@@ -0,0 +1,223 @@
+package configManager
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Root element written by Save, any root element name is accepted by Parse
+const xmlRoot = "config"
+
+// Which of an attribute and a child element with the same name sets an option, see ConfigSet.XMLAttributes
+type XMLPreference uint8
+
+const (
+	PreferElements   XMLPreference = iota // <server port="80"><port>81</port></server> sets server.port to 81, the default
+	PreferAttributes                      // <server port="80"><port>81</port></server> sets server.port to 80
+	IgnoreAttributes                      // Only elements set options, attributes are skipped
+)
+
+// Returns an unmarshaller decoding the children of the root element into a map
+// Elements holding other elements become nested maps, repeated elements become lists, text is kept as a string
+// Attributes bind like child elements, and when an element has both text and attributes the text is its value
+// and each attribute a dotted option under it, attributes of repeated elements holding text are skipped
+//
+//	<config><server><port>80</port></server></config> sets "server.port"
+//	<config><server port="80"/></config> sets "server.port"
+//	<config><timeout unit="s">30</timeout></config> sets "timeout" and "timeout.unit"
+func xmlUnmarshaller(pref XMLPreference) func(data []byte, v any) error {
+	return func(data []byte, v any) error {
+		d, ok := v.(*map[string]any)
+		if !ok {
+			return fmt.Errorf("xml: cannot decode into %T", v)
+		}
+
+		dec := xml.NewDecoder(bytes.NewReader(data))
+		for {
+			tok, err := dec.Token()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if start, ok := tok.(xml.StartElement); ok {
+				root, err := xmlElement(dec, start, pref)
+				if err != nil {
+					return err
+				}
+				switch r := root.(type) {
+				case map[string]any:
+					maps.Copy(*d, r)
+				case xmlText:
+					maps.Copy(*d, r.attrs)
+				}
+				return nil
+			}
+		}
+	}
+}
+
+// An element holding both text and attributes
+type xmlText struct {
+	text  string
+	attrs map[string]any
+}
+
+// Decodes the element opened by start, returning a map when it has children or only attributes and its text otherwise
+func xmlElement(dec *xml.Decoder, start xml.StartElement, pref XMLPreference) (any, error) {
+	var text strings.Builder
+	var children map[string]any
+	var attrs map[string]any
+	fromAttrs := make(map[string][]string) // keys added for the attributes of each child holding text
+	for _, a := range start.Attr {
+		if pref == IgnoreAttributes || a.Name.Space == "xmlns" || a.Name.Local == "xmlns" || a.Name.Space == "xml" {
+			continue
+		}
+		if attrs == nil {
+			attrs = make(map[string]any)
+		}
+		attrs[a.Name.Local] = a.Value
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			v, err := xmlElement(dec, t, pref)
+			if err != nil {
+				return nil, err
+			}
+			if children == nil {
+				children = make(map[string]any)
+			}
+			xmlPut(children, fromAttrs, t.Name.Local, v)
+		case xml.EndElement:
+			value := strings.TrimSpace(text.String())
+			switch {
+			case children == nil && attrs == nil:
+				return value, nil
+			case children == nil && value != "":
+				return xmlText{value, attrs}, nil
+			case children == nil:
+				return attrs, nil
+			}
+			for name, v := range attrs {
+				if _, clash := children[name]; !clash || pref == PreferAttributes {
+					children[name] = v
+				}
+			}
+			return children, nil
+		}
+	}
+}
+
+// Stores the value of the element called name among its siblings, repeated elements become lists
+// fromAttrs records the keys added for attributes of elements holding text, they're removed once the element repeats
+func xmlPut(siblings map[string]any, fromAttrs map[string][]string, name string, v any) {
+	_, repeated := siblings[name]
+	if t, ok := v.(xmlText); ok {
+		if !repeated {
+			siblings[name] = t.text
+			for attr, av := range t.attrs {
+				key := name + NamespaceSeparator + attr
+				siblings[key] = av
+				fromAttrs[name] = append(fromAttrs[name], key)
+			}
+			return
+		}
+		v = t.text
+	}
+	if repeated {
+		for _, key := range fromAttrs[name] {
+			delete(siblings, key)
+		}
+		delete(fromAttrs, name)
+	}
+	switch prev := siblings[name].(type) {
+	case nil:
+		siblings[name] = v
+	case []any:
+		siblings[name] = append(prev, v)
+	default:
+		siblings[name] = []any{prev, v}
+	}
+}
+
+// Returns a marshaller encoding a map as the children of a config root element, nested maps as nested elements
+// and lists as repeated elements, every key must be a valid element name
+// Nested elements are indented by indent, an empty indent writes everything on one line
+func xmlMarshaller(indent string) func(v any) ([]byte, error) {
+	return func(v any) ([]byte, error) {
+		d, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("xml: cannot encode %T", v)
+		}
+
+		var buf bytes.Buffer
+		enc := xml.NewEncoder(&buf)
+		enc.Indent("", indent)
+		if err := xmlEncode(enc, xmlRoot, d); err != nil {
+			return nil, err
+		}
+		if err := enc.Flush(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+func xmlEncode(enc *xml.Encoder, name string, v any) error {
+	if !validXMLName(name) {
+		return fmt.Errorf("xml: %q is not a valid element name", name)
+	}
+
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+		for i := range rv.Len() {
+			if err := xmlEncode(enc, name, rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	if m, ok := v.(map[string]any); ok {
+		for _, k := range slices.Sorted(maps.Keys(m)) {
+			if err := xmlEncode(enc, k, m[k]); err != nil {
+				return err
+			}
+		}
+	} else if err := enc.EncodeToken(xml.CharData(stringify(v, nil))); err != nil {
+		return err
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// Reports whether name can be used as an element name, dotted names are nested before they get here
+func validXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		letter := r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 0x7f
+		if !letter && (i == 0 || !(r == '-' || r == '.' || r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}